@@ -16,6 +16,7 @@ package config
 
 import (
 	"strings"
+	"time"
 
 	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
 	"github.com/palantir/witchcraft-go-logging/wlog"
@@ -28,6 +29,8 @@ type Runtime struct {
 	HealthChecks      HealthChecksConfig        `yaml:"health-checks,omitempty"`
 	LoggerConfig      *LoggerConfig             `yaml:"logging,omitempty"`
 	ServiceDiscovery  httpclient.ServicesConfig `yaml:"service-discovery,omitempty"`
+	CORS              CORSConfig                `yaml:"cors,omitempty"`
+	Requests          RequestsConfig            `yaml:"requests,omitempty"`
 }
 
 type DiagnosticsConfig struct {
@@ -38,6 +41,86 @@ type HealthChecksConfig struct {
 	SharedSecret string `yaml:"shared-secret"`
 }
 
+// CORSConfig configures the handling of cross-origin requests. CORS headers are only added to responses if
+// AllowedOrigins is non-empty.
+type CORSConfig struct {
+	// AllowedOrigins specifies the origins that are allowed to make cross-origin requests. An entry of "*" allows all
+	// origins. An entry may contain a single "*" in place of a subdomain to allow all of its subdomains: for example,
+	// "https://*.example.com" allows "https://app.example.com" but not "https://example.com".
+	AllowedOrigins []string `yaml:"allowed-origins,omitempty"`
+	// AllowedMethods restricts the methods that are allowed for cross-origin requests. If empty, the methods of the
+	// routes registered for the requested path are allowed.
+	AllowedMethods []string `yaml:"allowed-methods,omitempty"`
+	// AllowedHeaders specifies the request headers that are allowed for cross-origin requests. An entry of "*" allows
+	// all headers.
+	AllowedHeaders []string `yaml:"allowed-headers,omitempty"`
+	// ExposedHeaders specifies the response headers that browsers are allowed to expose to cross-origin callers.
+	ExposedHeaders []string `yaml:"exposed-headers,omitempty"`
+	// AllowCredentials specifies whether cross-origin requests may include credentials such as cookies. Credentials
+	// are only allowed for origins that match an entry of AllowedOrigins other than "*".
+	AllowCredentials bool `yaml:"allow-credentials,omitempty"`
+	// MaxAge specifies how long the result of a preflight request may be cached. If 0, the header is not set.
+	MaxAge time.Duration `yaml:"max-age,omitempty"`
+}
+
+// RequestsConfig configures the defaults for the handling of requests to routes registered on the server.
+type RequestsConfig struct {
+	// Timeout specifies the maximum duration of requests to routes that are not registered with a timeout. If 0,
+	// requests do not have a timeout.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// MaxBodySize specifies the maximum size in bytes of the bodies of requests to routes that are not registered with
+	// a maximum body size. If 0, request bodies are not limited.
+	MaxBodySize int64 `yaml:"max-body-size,omitempty"`
+	// Compression configures the compression of responses.
+	Compression CompressionConfig `yaml:"compression,omitempty"`
+	// Decompression configures the decompression of request bodies.
+	Decompression DecompressionConfig `yaml:"decompression,omitempty"`
+	// Cache configures the cache of responses to routes registered with RouteCached.
+	Cache ResponseCacheConfig `yaml:"cache,omitempty"`
+}
+
+// CompressionConfig configures the compression of responses to routes registered on the server. If enabled, responses
+// are compressed using an encoding that the client accepts if their content type is compressible and their body is at
+// least MinSize bytes.
+type CompressionConfig struct {
+	// Enabled enables the compression of responses to all routes that are not registered with DisableCompression.
+	Enabled bool `yaml:"enabled,omitempty"`
+	// MinSize specifies the minimum size in bytes of the response bodies that are compressed. If 0, defaults to 1024.
+	MinSize int64 `yaml:"min-size,omitempty"`
+}
+
+// DecompressionConfig configures the decompression of the bodies of requests to routes registered on the server.
+type DecompressionConfig struct {
+	// Enabled enables the decompression of request bodies for all routes, including routes that are not registered
+	// with DecompressRequestBody.
+	Enabled bool `yaml:"enabled,omitempty"`
+	// MaxSize specifies the maximum size in bytes of decompressed request bodies, which applies in addition to the
+	// maximum request body size so that small compressed bodies cannot decompress to arbitrarily large bodies even if
+	// the maximum request body size is not configured. If 0, defaults to 104857600 (100 MiB).
+	MaxSize int64 `yaml:"max-size,omitempty"`
+}
+
+// ResponseCacheConfig configures the in-memory cache of responses to routes registered on the server with RouteCached.
+type ResponseCacheConfig struct {
+	// Disabled disables the cache: responses are neither served from nor stored in the cache.
+	Disabled bool `yaml:"disabled,omitempty"`
+	// MaxEntries specifies the maximum number of responses in the cache. Once the cache is full, the least recently
+	// used responses are evicted. If 0, defaults to 1000.
+	MaxEntries int `yaml:"max-entries,omitempty"`
+	// MaxEntrySize specifies the maximum size in bytes of the bodies of the responses that are cached. If 0, defaults
+	// to 1048576 (1 MiB).
+	MaxEntrySize int64 `yaml:"max-entry-size,omitempty"`
+	// MaxTotalSize specifies the maximum total size in bytes of the responses in the cache. Once the cache is full, the
+	// least recently used responses are evicted. If 0, defaults to 104857600 (100 MiB).
+	MaxTotalSize int64 `yaml:"max-total-size,omitempty"`
+	// DefaultTTL specifies how long responses are cached for routes whose cache name does not have a TTL in TTLs. If
+	// 0, defaults to 1 minute.
+	DefaultTTL time.Duration `yaml:"default-ttl,omitempty"`
+	// TTLs specifies how long responses are cached by the name of the cache configured for the route. A TTL of 0
+	// disables the caching of responses for the name.
+	TTLs map[string]time.Duration `yaml:"ttls,omitempty"`
+}
+
 type LoggerConfig struct {
 	// Level configures the log level for leveled loggers (such as service logs). Does not impact non-leveled loggers
 	// (such as request logs).
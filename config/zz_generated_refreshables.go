@@ -6,6 +6,7 @@ import (
 	httpclient "github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
 	refreshable "github.com/palantir/pkg/refreshable"
 	wlog "github.com/palantir/witchcraft-go-logging/wlog"
+	time "time"
 )
 
 type RefreshableRuntime interface {
@@ -18,6 +19,8 @@ type RefreshableRuntime interface {
 	HealthChecks() RefreshableHealthChecksConfig
 	LoggerConfig() RefreshableLoggerConfigPtr
 	ServiceDiscovery() RefreshableServicesConfig
+	CORS() RefreshableCORSConfig
+	Requests() RefreshableRequestsConfig
 }
 
 type RefreshingRuntime struct {
@@ -68,6 +71,18 @@ func (r RefreshingRuntime) ServiceDiscovery() RefreshableServicesConfig {
 	}))
 }
 
+func (r RefreshingRuntime) CORS() RefreshableCORSConfig {
+	return NewRefreshingCORSConfig(r.MapRuntime(func(i Runtime) interface{} {
+		return i.CORS
+	}))
+}
+
+func (r RefreshingRuntime) Requests() RefreshableRequestsConfig {
+	return NewRefreshingRequestsConfig(r.MapRuntime(func(i Runtime) interface{} {
+		return i.Requests
+	}))
+}
+
 type RefreshableDiagnosticsConfig interface {
 	refreshable.Refreshable
 	CurrentDiagnosticsConfig() DiagnosticsConfig
@@ -647,3 +662,341 @@ func (r RefreshingStringToClientConfig) SubscribeToStringToClientConfig(consumer
 		consumer(i.(map[string]httpclient.ClientConfig))
 	})
 }
+
+type RefreshableCORSConfig interface {
+	refreshable.Refreshable
+	CurrentCORSConfig() CORSConfig
+	MapCORSConfig(func(CORSConfig) interface{}) refreshable.Refreshable
+	SubscribeToCORSConfig(func(CORSConfig)) (unsubscribe func())
+
+	AllowedOrigins() refreshable.StringSlice
+	AllowedMethods() refreshable.StringSlice
+	AllowedHeaders() refreshable.StringSlice
+	ExposedHeaders() refreshable.StringSlice
+	AllowCredentials() refreshable.Bool
+	MaxAge() refreshable.Duration
+}
+
+type RefreshingCORSConfig struct {
+	refreshable.Refreshable
+}
+
+func NewRefreshingCORSConfig(in refreshable.Refreshable) RefreshingCORSConfig {
+	return RefreshingCORSConfig{Refreshable: in}
+}
+
+func (r RefreshingCORSConfig) CurrentCORSConfig() CORSConfig {
+	return r.Current().(CORSConfig)
+}
+
+func (r RefreshingCORSConfig) MapCORSConfig(mapFn func(CORSConfig) interface{}) refreshable.Refreshable {
+	return r.Map(func(i interface{}) interface{} {
+		return mapFn(i.(CORSConfig))
+	})
+}
+
+func (r RefreshingCORSConfig) SubscribeToCORSConfig(consumer func(CORSConfig)) (unsubscribe func()) {
+	return r.Subscribe(func(i interface{}) {
+		consumer(i.(CORSConfig))
+	})
+}
+
+func (r RefreshingCORSConfig) AllowedOrigins() refreshable.StringSlice {
+	return refreshable.NewStringSlice(r.MapCORSConfig(func(i CORSConfig) interface{} {
+		return i.AllowedOrigins
+	}))
+}
+
+func (r RefreshingCORSConfig) AllowedMethods() refreshable.StringSlice {
+	return refreshable.NewStringSlice(r.MapCORSConfig(func(i CORSConfig) interface{} {
+		return i.AllowedMethods
+	}))
+}
+
+func (r RefreshingCORSConfig) AllowedHeaders() refreshable.StringSlice {
+	return refreshable.NewStringSlice(r.MapCORSConfig(func(i CORSConfig) interface{} {
+		return i.AllowedHeaders
+	}))
+}
+
+func (r RefreshingCORSConfig) ExposedHeaders() refreshable.StringSlice {
+	return refreshable.NewStringSlice(r.MapCORSConfig(func(i CORSConfig) interface{} {
+		return i.ExposedHeaders
+	}))
+}
+
+func (r RefreshingCORSConfig) AllowCredentials() refreshable.Bool {
+	return refreshable.NewBool(r.MapCORSConfig(func(i CORSConfig) interface{} {
+		return i.AllowCredentials
+	}))
+}
+
+func (r RefreshingCORSConfig) MaxAge() refreshable.Duration {
+	return refreshable.NewDuration(r.MapCORSConfig(func(i CORSConfig) interface{} {
+		return i.MaxAge
+	}))
+}
+
+type RefreshableRequestsConfig interface {
+	refreshable.Refreshable
+	CurrentRequestsConfig() RequestsConfig
+	MapRequestsConfig(func(RequestsConfig) interface{}) refreshable.Refreshable
+	SubscribeToRequestsConfig(func(RequestsConfig)) (unsubscribe func())
+
+	Timeout() refreshable.Duration
+	MaxBodySize() refreshable.Int64
+	Compression() RefreshableCompressionConfig
+	Decompression() RefreshableDecompressionConfig
+	Cache() RefreshableResponseCacheConfig
+}
+
+type RefreshingRequestsConfig struct {
+	refreshable.Refreshable
+}
+
+func NewRefreshingRequestsConfig(in refreshable.Refreshable) RefreshingRequestsConfig {
+	return RefreshingRequestsConfig{Refreshable: in}
+}
+
+func (r RefreshingRequestsConfig) CurrentRequestsConfig() RequestsConfig {
+	return r.Current().(RequestsConfig)
+}
+
+func (r RefreshingRequestsConfig) MapRequestsConfig(mapFn func(RequestsConfig) interface{}) refreshable.Refreshable {
+	return r.Map(func(i interface{}) interface{} {
+		return mapFn(i.(RequestsConfig))
+	})
+}
+
+func (r RefreshingRequestsConfig) SubscribeToRequestsConfig(consumer func(RequestsConfig)) (unsubscribe func()) {
+	return r.Subscribe(func(i interface{}) {
+		consumer(i.(RequestsConfig))
+	})
+}
+
+func (r RefreshingRequestsConfig) Timeout() refreshable.Duration {
+	return refreshable.NewDuration(r.MapRequestsConfig(func(i RequestsConfig) interface{} {
+		return i.Timeout
+	}))
+}
+
+func (r RefreshingRequestsConfig) MaxBodySize() refreshable.Int64 {
+	return refreshable.NewInt64(r.MapRequestsConfig(func(i RequestsConfig) interface{} {
+		return i.MaxBodySize
+	}))
+}
+
+func (r RefreshingRequestsConfig) Compression() RefreshableCompressionConfig {
+	return NewRefreshingCompressionConfig(r.MapRequestsConfig(func(i RequestsConfig) interface{} {
+		return i.Compression
+	}))
+}
+
+func (r RefreshingRequestsConfig) Decompression() RefreshableDecompressionConfig {
+	return NewRefreshingDecompressionConfig(r.MapRequestsConfig(func(i RequestsConfig) interface{} {
+		return i.Decompression
+	}))
+}
+
+func (r RefreshingRequestsConfig) Cache() RefreshableResponseCacheConfig {
+	return NewRefreshingResponseCacheConfig(r.MapRequestsConfig(func(i RequestsConfig) interface{} {
+		return i.Cache
+	}))
+}
+
+type RefreshableCompressionConfig interface {
+	refreshable.Refreshable
+	CurrentCompressionConfig() CompressionConfig
+	MapCompressionConfig(func(CompressionConfig) interface{}) refreshable.Refreshable
+	SubscribeToCompressionConfig(func(CompressionConfig)) (unsubscribe func())
+
+	Enabled() refreshable.Bool
+	MinSize() refreshable.Int64
+}
+
+type RefreshingCompressionConfig struct {
+	refreshable.Refreshable
+}
+
+func NewRefreshingCompressionConfig(in refreshable.Refreshable) RefreshingCompressionConfig {
+	return RefreshingCompressionConfig{Refreshable: in}
+}
+
+func (r RefreshingCompressionConfig) CurrentCompressionConfig() CompressionConfig {
+	return r.Current().(CompressionConfig)
+}
+
+func (r RefreshingCompressionConfig) MapCompressionConfig(mapFn func(CompressionConfig) interface{}) refreshable.Refreshable {
+	return r.Map(func(i interface{}) interface{} {
+		return mapFn(i.(CompressionConfig))
+	})
+}
+
+func (r RefreshingCompressionConfig) SubscribeToCompressionConfig(consumer func(CompressionConfig)) (unsubscribe func()) {
+	return r.Subscribe(func(i interface{}) {
+		consumer(i.(CompressionConfig))
+	})
+}
+
+func (r RefreshingCompressionConfig) Enabled() refreshable.Bool {
+	return refreshable.NewBool(r.MapCompressionConfig(func(i CompressionConfig) interface{} {
+		return i.Enabled
+	}))
+}
+
+func (r RefreshingCompressionConfig) MinSize() refreshable.Int64 {
+	return refreshable.NewInt64(r.MapCompressionConfig(func(i CompressionConfig) interface{} {
+		return i.MinSize
+	}))
+}
+
+type RefreshableDecompressionConfig interface {
+	refreshable.Refreshable
+	CurrentDecompressionConfig() DecompressionConfig
+	MapDecompressionConfig(func(DecompressionConfig) interface{}) refreshable.Refreshable
+	SubscribeToDecompressionConfig(func(DecompressionConfig)) (unsubscribe func())
+
+	Enabled() refreshable.Bool
+	MaxSize() refreshable.Int64
+}
+
+type RefreshingDecompressionConfig struct {
+	refreshable.Refreshable
+}
+
+func NewRefreshingDecompressionConfig(in refreshable.Refreshable) RefreshingDecompressionConfig {
+	return RefreshingDecompressionConfig{Refreshable: in}
+}
+
+func (r RefreshingDecompressionConfig) CurrentDecompressionConfig() DecompressionConfig {
+	return r.Current().(DecompressionConfig)
+}
+
+func (r RefreshingDecompressionConfig) MapDecompressionConfig(mapFn func(DecompressionConfig) interface{}) refreshable.Refreshable {
+	return r.Map(func(i interface{}) interface{} {
+		return mapFn(i.(DecompressionConfig))
+	})
+}
+
+func (r RefreshingDecompressionConfig) SubscribeToDecompressionConfig(consumer func(DecompressionConfig)) (unsubscribe func()) {
+	return r.Subscribe(func(i interface{}) {
+		consumer(i.(DecompressionConfig))
+	})
+}
+
+func (r RefreshingDecompressionConfig) Enabled() refreshable.Bool {
+	return refreshable.NewBool(r.MapDecompressionConfig(func(i DecompressionConfig) interface{} {
+		return i.Enabled
+	}))
+}
+
+func (r RefreshingDecompressionConfig) MaxSize() refreshable.Int64 {
+	return refreshable.NewInt64(r.MapDecompressionConfig(func(i DecompressionConfig) interface{} {
+		return i.MaxSize
+	}))
+}
+
+type RefreshableResponseCacheConfig interface {
+	refreshable.Refreshable
+	CurrentResponseCacheConfig() ResponseCacheConfig
+	MapResponseCacheConfig(func(ResponseCacheConfig) interface{}) refreshable.Refreshable
+	SubscribeToResponseCacheConfig(func(ResponseCacheConfig)) (unsubscribe func())
+
+	Disabled() refreshable.Bool
+	MaxEntries() refreshable.Int
+	MaxEntrySize() refreshable.Int64
+	MaxTotalSize() refreshable.Int64
+	DefaultTTL() refreshable.Duration
+	TTLs() RefreshableStringToDuration
+}
+
+type RefreshingResponseCacheConfig struct {
+	refreshable.Refreshable
+}
+
+func NewRefreshingResponseCacheConfig(in refreshable.Refreshable) RefreshingResponseCacheConfig {
+	return RefreshingResponseCacheConfig{Refreshable: in}
+}
+
+func (r RefreshingResponseCacheConfig) CurrentResponseCacheConfig() ResponseCacheConfig {
+	return r.Current().(ResponseCacheConfig)
+}
+
+func (r RefreshingResponseCacheConfig) MapResponseCacheConfig(mapFn func(ResponseCacheConfig) interface{}) refreshable.Refreshable {
+	return r.Map(func(i interface{}) interface{} {
+		return mapFn(i.(ResponseCacheConfig))
+	})
+}
+
+func (r RefreshingResponseCacheConfig) SubscribeToResponseCacheConfig(consumer func(ResponseCacheConfig)) (unsubscribe func()) {
+	return r.Subscribe(func(i interface{}) {
+		consumer(i.(ResponseCacheConfig))
+	})
+}
+
+func (r RefreshingResponseCacheConfig) Disabled() refreshable.Bool {
+	return refreshable.NewBool(r.MapResponseCacheConfig(func(i ResponseCacheConfig) interface{} {
+		return i.Disabled
+	}))
+}
+
+func (r RefreshingResponseCacheConfig) MaxEntries() refreshable.Int {
+	return refreshable.NewInt(r.MapResponseCacheConfig(func(i ResponseCacheConfig) interface{} {
+		return i.MaxEntries
+	}))
+}
+
+func (r RefreshingResponseCacheConfig) MaxEntrySize() refreshable.Int64 {
+	return refreshable.NewInt64(r.MapResponseCacheConfig(func(i ResponseCacheConfig) interface{} {
+		return i.MaxEntrySize
+	}))
+}
+
+func (r RefreshingResponseCacheConfig) MaxTotalSize() refreshable.Int64 {
+	return refreshable.NewInt64(r.MapResponseCacheConfig(func(i ResponseCacheConfig) interface{} {
+		return i.MaxTotalSize
+	}))
+}
+
+func (r RefreshingResponseCacheConfig) DefaultTTL() refreshable.Duration {
+	return refreshable.NewDuration(r.MapResponseCacheConfig(func(i ResponseCacheConfig) interface{} {
+		return i.DefaultTTL
+	}))
+}
+
+func (r RefreshingResponseCacheConfig) TTLs() RefreshableStringToDuration {
+	return NewRefreshingStringToDuration(r.MapResponseCacheConfig(func(i ResponseCacheConfig) interface{} {
+		return i.TTLs
+	}))
+}
+
+type RefreshableStringToDuration interface {
+	refreshable.Refreshable
+	CurrentStringToDuration() map[string]time.Duration
+	MapStringToDuration(func(map[string]time.Duration) interface{}) refreshable.Refreshable
+	SubscribeToStringToDuration(func(map[string]time.Duration)) (unsubscribe func())
+}
+
+type RefreshingStringToDuration struct {
+	refreshable.Refreshable
+}
+
+func NewRefreshingStringToDuration(in refreshable.Refreshable) RefreshingStringToDuration {
+	return RefreshingStringToDuration{Refreshable: in}
+}
+
+func (r RefreshingStringToDuration) CurrentStringToDuration() map[string]time.Duration {
+	return r.Current().(map[string]time.Duration)
+}
+
+func (r RefreshingStringToDuration) MapStringToDuration(mapFn func(map[string]time.Duration) interface{}) refreshable.Refreshable {
+	return r.Map(func(i interface{}) interface{} {
+		return mapFn(i.(map[string]time.Duration))
+	})
+}
+
+func (r RefreshingStringToDuration) SubscribeToStringToDuration(consumer func(map[string]time.Duration)) (unsubscribe func()) {
+	return r.Subscribe(func(i interface{}) {
+		consumer(i.(map[string]time.Duration))
+	})
+}
@@ -20,6 +20,7 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -35,6 +36,9 @@ import (
 	"github.com/palantir/witchcraft-go-server/v2/config"
 	"github.com/palantir/witchcraft-go-server/v2/status"
 	"github.com/palantir/witchcraft-go-server/v2/witchcraft"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wopenapi"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wresource"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -400,3 +404,126 @@ func TestDefaultNotFoundHandler(t *testing.T) {
 	default:
 	}
 }
+
+func TestDefaultMethodNotAllowedHandler(t *testing.T) {
+	server, port, _, serverErr, cleanup := createAndRunTestServer(t, func(ctx context.Context, info witchcraft.InitInfo) (deferFn func(), rErr error) {
+		return nil, info.Router.Get("/foo", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(200)
+		}))
+	}, ioutil.Discard)
+	defer func() {
+		_ = server.Close()
+	}()
+	defer cleanup()
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("https://localhost:%d%s/foo", port, basePath), nil)
+	require.NoError(t, err)
+	resp, err := testServerClient().Do(req)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	assert.Equal(t, "405 Method Not Allowed", resp.Status)
+	assert.Equal(t, "GET, OPTIONS", resp.Header.Get("Allow"))
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	cerr, err := errors.UnmarshalError(body)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Witchcraft:MethodNotAllowed", cerr.Name())
+	}
+
+	req, err = http.NewRequest(http.MethodOptions, fmt.Sprintf("https://localhost:%d%s/foo", port, basePath), nil)
+	require.NoError(t, err)
+	resp, err = testServerClient().Do(req)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "GET, OPTIONS", resp.Header.Get("Allow"))
+
+	select {
+	case err := <-serverErr:
+		require.NoError(t, err)
+	default:
+	}
+}
+
+// TestOpenAPIDocument verifies that a server configured using WithOpenAPI serves an OpenAPI document describing the
+// routes of the main server on the management server.
+func TestOpenAPIDocument(t *testing.T) {
+	port, err := httpserver.AvailablePort()
+	require.NoError(t, err)
+	managementPort, err := httpserver.AvailablePort()
+	require.NoError(t, err)
+	server, serverErr, cleanup := createAndRunCustomTestServer(t, port, managementPort, func(ctx context.Context, info witchcraft.InitInfo) (deferFn func(), rErr error) {
+		return nil, wresource.New("foo", info.Router).Get("getFoo", "/foo/{id:int}", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+		}), wrouter.RouteDocumentation(wrouter.RouteDoc{Summary: "Returns a foo"}))
+	}, ioutil.Discard, func(t *testing.T, initFn witchcraft.InitFunc, installCfg config.Install, logOutputBuffer io.Writer) *witchcraft.Server {
+		return createTestServer(t, initFn, installCfg, logOutputBuffer).WithOpenAPI(wopenapi.Info{Version: "1.0.0"})
+	})
+	defer func() {
+		_ = server.Close()
+	}()
+	defer cleanup()
+
+	resp, err := testServerClient().Get(fmt.Sprintf("https://localhost:%d%s/openapi", managementPort, basePath))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var doc wopenapi.Document
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+
+	assert.Equal(t, wopenapi.Info{Title: productName, Version: "1.0.0"}, doc.Info)
+	require.Contains(t, doc.Paths, basePath+"/foo/{id}")
+	op := doc.Paths[basePath+"/foo/{id}"]["get"]
+	require.NotNil(t, op)
+	assert.Equal(t, "getFoo", op.OperationID)
+	assert.Equal(t, []string{"foo"}, op.Tags)
+	assert.Equal(t, "Returns a foo", op.Summary)
+	assert.NotContains(t, doc.Paths, basePath+"/openapi", "management routes should not be documented")
+
+	select {
+	case err := <-serverErr:
+		require.NoError(t, err)
+	default:
+	}
+}
+
+// TestMiddlewareStages verifies that middleware can be inserted relative to the built-in middleware stages and that
+// built-in stages can be disabled.
+func TestMiddlewareStages(t *testing.T) {
+	port, err := httpserver.AvailablePort()
+	require.NoError(t, err)
+	managementPort, err := httpserver.AvailablePort()
+	require.NoError(t, err)
+	server, serverErr, cleanup := createAndRunCustomTestServer(t, port, managementPort, nil, ioutil.Discard, func(t *testing.T, initFn witchcraft.InitFunc, installCfg config.Install, logOutputBuffer io.Writer) *witchcraft.Server {
+		return createTestServer(t, initFn, installCfg, logOutputBuffer).
+			WithMiddleware(func(rw http.ResponseWriter, r *http.Request, next http.Handler) {
+				rw.Header().Add("X-Order", "user")
+				next.ServeHTTP(rw, r)
+			}).
+			WithMiddlewareBefore(witchcraft.MiddlewareStageUser, witchcraft.NewNamedRequestMiddleware("before-user", func(rw http.ResponseWriter, r *http.Request, next http.Handler) {
+				rw.Header().Add("X-Order", "before-user")
+				next.ServeHTTP(rw, r)
+			})).
+			WithMiddlewareAfter(witchcraft.MiddlewareStageRoutePanicRecovery, witchcraft.NewNamedRouteMiddleware("after-recovery", func(rw http.ResponseWriter, r *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+				rw.Header().Add("X-Order", "after-recovery")
+				next(rw, r, reqVals)
+			})).
+			WithMiddlewareDisabled(witchcraft.MiddlewareStageHSTS)
+	})
+	defer func() {
+		_ = server.Close()
+	}()
+	defer cleanup()
+
+	resp, err := testServerClient().Get(fmt.Sprintf("https://localhost:%d%s/ok", port, basePath))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"before-user", "user", "after-recovery"}, resp.Header.Values("X-Order"))
+	assert.Empty(t, resp.Header.Get("Strict-Transport-Security"))
+
+	select {
+	case err := <-serverErr:
+		require.NoError(t, err)
+	default:
+	}
+}
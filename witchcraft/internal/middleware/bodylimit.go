@@ -0,0 +1,149 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/refreshable"
+	wparams "github.com/palantir/witchcraft-go-params"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+const serverRequestTooLargeMetricName = "server.request.tooLarge"
+
+// requestEntityTooLargeErrorType is the type of the error returned for requests whose body exceeds the maximum size.
+var requestEntityTooLargeErrorType = NewStatusErrorType("Witchcraft:RequestEntityTooLarge", http.StatusRequestEntityTooLarge)
+
+// NewRouteBodyLimit returns a middleware that limits the size of request bodies to the maximum configured for the route,
+// or the provided default maximum if the route does not configure one. Requests whose Content-Length exceeds the
+// maximum are rejected before the handler is invoked. Otherwise, the body is wrapped in a reader that fails with an
+// *http.MaxBytesError once more than the maximum has been read, which handles bodies whose size is not known up front.
+// If the handler reads past the maximum before it has written its response, the response it writes is discarded in
+// favor of the 413 response. Rejected requests mark the request too large meter for the route.
+func NewRouteBodyLimit(defaultMaxBodySize refreshable.Int64, mr metrics.RootRegistry) wrouter.RouteHandlerMiddleware {
+	return func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+		maxBodySize := reqVals.MaxBodySize
+		if maxBodySize == 0 && defaultMaxBodySize != nil {
+			maxBodySize = defaultMaxBodySize.CurrentInt64()
+		}
+		if maxBodySize <= 0 || req.Body == nil || req.Body == http.NoBody {
+			next(rw, req, reqVals)
+			return
+		}
+		if req.ContentLength > maxBodySize {
+			writeRequestEntityTooLarge(rw, req, reqVals, maxBodySize, mr)
+			return
+		}
+
+		serveLimitedBody(rw, req, reqVals, maxBodySize, mr, next)
+	}
+}
+
+// serveLimitedBody invokes the provided handler with a request whose body fails with an *http.MaxBytesError once more
+// than maxBodySize bytes have been read, and writes the 413 response if the handler reads past the maximum before it
+// has written its response.
+func serveLimitedBody(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, maxBodySize int64, mr metrics.RootRegistry, next wrouter.RouteRequestHandler) {
+	body := &limitedBody{ReadCloser: req.Body, remaining: maxBodySize, limit: maxBodySize}
+	req.Body = body
+	brw := &bodyLimitResponseWriter{ResponseWriter: rw, body: body}
+	next(brw, req, reqVals)
+	if body.exceeded.Load() && !brw.written {
+		writeRequestEntityTooLarge(rw, req, reqVals, maxBodySize, mr)
+	}
+}
+
+func writeRequestEntityTooLarge(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, maxBodySize int64, mr metrics.RootRegistry) {
+	if !reqVals.DisableTelemetry {
+		mr.Meter(serverRequestTooLargeMetricName, reqVals.MetricTags...).Mark(1)
+	}
+	requestEntityTooLargeErrorType.Write(req.Context(), rw, nil, wparams.NewSafeParam("maxBodySize", maxBodySize))
+}
+
+// limitedBody is a request body that fails with an *http.MaxBytesError once more than limit bytes have been read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+	exceeded  atomic.Bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded.Load() {
+		return 0, &http.MaxBytesError{Limit: b.limit}
+	}
+	// read at most one byte more than the remaining limit to determine whether the limit is exceeded
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) <= b.remaining {
+		b.remaining -= int64(n)
+		return n, err
+	}
+	n = int(b.remaining)
+	b.remaining = 0
+	b.exceeded.Store(true)
+	return n, &http.MaxBytesError{Limit: b.limit}
+}
+
+// bodyLimitResponseWriter discards the response written by the handler once the request body has exceeded its limit so
+// that the 413 response can be written instead.
+type bodyLimitResponseWriter struct {
+	http.ResponseWriter
+	body    *limitedBody
+	written bool
+}
+
+func (w *bodyLimitResponseWriter) WriteHeader(status int) {
+	if !w.written && w.body.exceeded.Load() {
+		return
+	}
+	w.written = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *bodyLimitResponseWriter) Write(p []byte) (int, error) {
+	if !w.written && w.body.exceeded.Load() {
+		return 0, &http.MaxBytesError{Limit: w.body.limit}
+	}
+	w.written = true
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *bodyLimitResponseWriter) Flush() {
+	flusher, ok := w.ResponseWriter.(http.Flusher)
+	if !ok || (!w.written && w.body.exceeded.Load()) {
+		return
+	}
+	w.written = true
+	flusher.Flush()
+}
+
+func (w *bodyLimitResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("the ResponseWriter doesn't support the Hijacker interface")
+	}
+	// the handler owns the connection once it is hijacked, so the 413 response must not be written
+	w.written = true
+	return hijacker.Hijack()
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/errors"
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/refreshable"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/routertest"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteBodyLimit(t *testing.T) {
+	registry := metrics.NewRootMetricsRegistry()
+	defaultMaxBodySize := refreshable.NewDefaultRefreshable(int64(0))
+	r := wrouter.New(whttprouter.New())
+	r.AddRouteHandlerMiddleware(middleware.NewRouteBodyLimit(refreshable.NewInt64(defaultMaxBodySize), registry))
+
+	var handlerCalls int
+	echoHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		handlerCalls++
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, _ = rw.Write(body)
+	})
+	// ignoreErrorHandler writes a successful response even if reading the body fails
+	ignoreErrorHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = io.Copy(io.Discard, req.Body)
+		rw.WriteHeader(http.StatusOK)
+	})
+	tags := metrics.MustNewTags(map[string]string{"endpoint": "echo"})
+	require.NoError(t, r.Post("/echo", echoHandler, wrouter.RouteMaxBodySize(5), wrouter.MetricTags(tags)))
+	require.NoError(t, r.Post("/ignore", ignoreErrorHandler, wrouter.RouteMaxBodySize(5)))
+	require.NoError(t, r.Post("/default", echoHandler))
+
+	doRequest := func(path, body string, chunked bool) *httptest.ResponseRecorder {
+		var bodyReader io.Reader = strings.NewReader(body)
+		if chunked {
+			// hide the length of the body so that it must be enforced while reading
+			bodyReader = io.NopCloser(bodyReader)
+		}
+		return routertest.ServeRequest(r, http.MethodPost, path, nil, bodyReader)
+	}
+	assertTooLarge := func(t *testing.T, rw *httptest.ResponseRecorder) {
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+		var serializableErr errors.SerializableError
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &serializableErr))
+		assert.Equal(t, "Witchcraft:RequestEntityTooLarge", serializableErr.ErrorName)
+	}
+
+	t.Run("within limit", func(t *testing.T) {
+		rw := doRequest("/echo", "hello", true)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "hello", rw.Body.String())
+	})
+
+	t.Run("content length exceeds limit", func(t *testing.T) {
+		handlerCalls = 0
+		assertTooLarge(t, doRequest("/echo", "hello world", false))
+		assert.Equal(t, 0, handlerCalls, "handler should not be invoked")
+	})
+
+	t.Run("chunked body exceeds limit", func(t *testing.T) {
+		assertTooLarge(t, doRequest("/echo", "hello world", true))
+		assertTooLarge(t, doRequest("/ignore", "hello world", true))
+	})
+
+	t.Run("default limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, doRequest("/default", "hello world", true).Code)
+		require.NoError(t, defaultMaxBodySize.Update(int64(5)))
+		assertTooLarge(t, doRequest("/default", "hello world", true))
+	})
+
+	assert.Equal(t, int64(2), registry.Meter("server.request.tooLarge", tags...).Count())
+}
+
+// Tests that handlers can hijack the connection of requests with limited bodies and that the 413 response is not written
+// once the connection is hijacked.
+func TestRouteBodyLimitHijack(t *testing.T) {
+	registry := metrics.NewRootMetricsRegistry()
+	bodyLimitMiddleware := middleware.NewRouteBodyLimit(refreshable.NewInt64(refreshable.NewDefaultRefreshable(int64(4))), registry)
+	rw := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder()}
+	req := httptest.NewRequest(http.MethodPost, "/", io.NopCloser(strings.NewReader("too large")))
+	req.ContentLength = -1
+	bodyLimitMiddleware(rw, req, wrouter.RequestVals{},
+		func(rw http.ResponseWriter, r *http.Request, reqVals wrouter.RequestVals) {
+			hijacker, ok := rw.(http.Hijacker)
+			require.True(t, ok)
+			_, _, _ = hijacker.Hijack()
+			_, err := io.ReadAll(r.Body)
+			require.Error(t, err)
+		},
+	)
+	assert.True(t, rw.hijacked)
+	assert.Empty(t, rw.Body.String())
+	assert.Equal(t, int64(0), registry.Meter("server.request.tooLarge").Count())
+}
@@ -0,0 +1,462 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/witchcraft-go-server/v2/config"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+const (
+	serverResponseCacheHitMetricName   = "server.response.cache.hit"
+	serverResponseCacheMissMetricName  = "server.response.cache.miss"
+	serverResponseCacheEvictMetricName = "server.response.cache.evict"
+
+	defaultResponseCacheMaxEntries   = 1000
+	defaultResponseCacheMaxEntrySize = 1 << 20
+	defaultResponseCacheMaxTotalSize = 100 << 20
+	defaultResponseCacheTTL          = time.Minute
+)
+
+// ResponseCache is a bounded in-memory cache of responses to routes registered with RouteCached that evicts the least
+// recently used responses once it has the maximum number of entries or the maximum total size.
+type ResponseCache struct {
+	cfg config.RefreshableResponseCacheConfig
+	mr  metrics.RootRegistry
+
+	mu sync.Mutex
+	// entries stores the elements of lru by the key of their entry.
+	entries map[string]*list.Element
+	// lru stores the *responseCacheEntry values of the cache from the most to the least recently used.
+	lru *list.List
+	// size is the sum of the sizes of the entries of the cache.
+	size int64
+}
+
+// responseCacheEntry is a cached response.
+type responseCacheEntry struct {
+	recordedResponse
+	key  string
+	name string
+	// tags are the metric tags of the route of the response. Nil if the route disables telemetry.
+	tags    metrics.Tags
+	created time.Time
+	expires time.Time
+	// size is the approximate number of bytes used by the response.
+	size int64
+}
+
+// NewResponseCache returns a new empty response cache configured by the provided configuration.
+func NewResponseCache(cfg config.RefreshableResponseCacheConfig, mr metrics.RootRegistry) *ResponseCache {
+	return &ResponseCache{
+		cfg:     cfg,
+		mr:      mr,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Middleware returns a middleware that serves responses to GET and HEAD requests to routes registered with RouteCached
+// from the cache and stores successful responses in the cache. The cache key consists of the method, the route, the
+// path, the configured query parameters and headers and, unless the route is public, the Authorization and Cookie
+// headers of the request, so that responses are only shared between requests of the same caller. The no-store, no-cache
+// and max-age directives of the Cache-Control header of requests are honored, and responses whose Cache-Control header
+// contains no-store (or private for public routes), that set cookies or whose Vary header names a request header that
+// is not part of the cache key (or is "*") are not cached. The cache holds at most the configured maximum number of
+// responses and total size of responses, evicting the least recently used responses to stay within both. Responses
+// served from the cache have an Age header. Cache hits, misses and capacity evictions mark the corresponding meters for
+// the route.
+func (c *ResponseCache) Middleware() wrouter.RouteHandlerMiddleware {
+	return func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+		routeCache := reqVals.Cache
+		cfg := c.cfg.CurrentResponseCacheConfig()
+		if routeCache == nil || cfg.Disabled || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+			next(rw, req, reqVals)
+			return
+		}
+		directives := parseCacheControl(req.Header.Get("Cache-Control"))
+		if _, ok := directives["no-store"]; ok {
+			next(rw, req, reqVals)
+			return
+		}
+		var tags metrics.Tags
+		if !reqVals.DisableTelemetry {
+			tags = reqVals.MetricTags
+		}
+
+		key := responseCacheKey(req, reqVals, routeCache)
+		now := time.Now()
+		if _, noCache := directives["no-cache"]; !noCache {
+			if entry, ok := c.get(key, now); ok && entry.freshFor(directives, now) {
+				c.mark(serverResponseCacheHitMetricName, tags)
+				entry.writeTo(rw, now)
+				return
+			}
+		}
+		c.mark(serverResponseCacheMissMetricName, tags)
+
+		maxEntrySize := cfg.MaxEntrySize
+		if maxEntrySize <= 0 {
+			maxEntrySize = defaultResponseCacheMaxEntrySize
+		}
+		crw := &recordingResponseWriter{
+			ResponseWriter: rw,
+			maxSize:        maxEntrySize,
+			initialHeader:  rw.Header().Clone(),
+		}
+		next(crw, req, reqVals)
+		if !crw.cacheable(routeCache) {
+			return
+		}
+		ttl, ok := cfg.TTLs[routeCache.Name]
+		if !ok {
+			ttl = cfg.DefaultTTL
+			if ttl <= 0 {
+				ttl = defaultResponseCacheTTL
+			}
+		}
+		if ttl <= 0 {
+			// the runtime configuration disables caching for the name
+			return
+		}
+		maxEntries := cfg.MaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultResponseCacheMaxEntries
+		}
+		maxTotalSize := cfg.MaxTotalSize
+		if maxTotalSize <= 0 {
+			maxTotalSize = defaultResponseCacheMaxTotalSize
+		}
+		response := crw.response()
+		entry := &responseCacheEntry{
+			recordedResponse: response,
+			key:              key,
+			name:             routeCache.Name,
+			tags:             tags,
+			created:          now,
+			expires:          now.Add(ttl),
+			size:             int64(len(key)) + response.size(),
+		}
+		if entry.size > maxTotalSize {
+			return
+		}
+		c.add(entry, maxEntries, maxTotalSize)
+	}
+}
+
+// Purge removes the responses cached for routes with the provided cache name, or all responses if the name is empty.
+// Returns the number of responses that were removed.
+func (c *ResponseCache) Purge(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var purged int
+	for el := c.lru.Front(); el != nil; {
+		nextEl := el.Next()
+		if entry := el.Value.(*responseCacheEntry); name == "" || entry.name == name {
+			c.remove(el)
+			purged++
+		}
+		el = nextEl
+	}
+	return purged
+}
+
+func (c *ResponseCache) get(key string, now time.Time) (*responseCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*responseCacheEntry)
+	if !now.Before(entry.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return entry, true
+}
+
+func (c *ResponseCache) add(entry *responseCacheEntry, maxEntries int, maxTotalSize int64) {
+	var evicted []*responseCacheEntry
+	func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if el, ok := c.entries[entry.key]; ok {
+			c.size -= el.Value.(*responseCacheEntry).size
+			el.Value = entry
+			c.lru.MoveToFront(el)
+		} else {
+			c.entries[entry.key] = c.lru.PushFront(entry)
+		}
+		c.size += entry.size
+		for c.lru.Len() > maxEntries || c.size > maxTotalSize {
+			oldest := c.lru.Back()
+			c.remove(oldest)
+			evicted = append(evicted, oldest.Value.(*responseCacheEntry))
+		}
+	}()
+	for _, entry := range evicted {
+		c.mark(serverResponseCacheEvictMetricName, entry.tags)
+	}
+}
+
+// remove removes the provided element from the cache. Must be called with the lock held.
+func (c *ResponseCache) remove(el *list.Element) {
+	entry := el.Value.(*responseCacheEntry)
+	c.lru.Remove(el)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
+
+func (c *ResponseCache) mark(name string, tags metrics.Tags) {
+	if tags == nil {
+		return
+	}
+	c.mr.Meter(name, tags...).Mark(1)
+}
+
+// freshFor returns true if the age of the entry satisfies the max-age directive of a request, if any.
+func (e *responseCacheEntry) freshFor(directives map[string]string, now time.Time) bool {
+	maxAge, ok := directives["max-age"]
+	if !ok {
+		return true
+	}
+	seconds, err := strconv.ParseInt(maxAge, 10, 64)
+	if err != nil {
+		return true
+	}
+	return now.Sub(e.created) <= time.Duration(seconds)*time.Second
+}
+
+func (e *responseCacheEntry) writeTo(rw http.ResponseWriter, now time.Time) {
+	rw.Header().Set("Age", strconv.FormatInt(int64(now.Sub(e.created)/time.Second), 10))
+	e.recordedResponse.writeTo(rw)
+}
+
+// responseCacheKey returns the key of the cached response to the provided request.
+func responseCacheKey(req *http.Request, reqVals wrouter.RequestVals, routeCache *wrouter.RouteCache) string {
+	hash := sha256.New()
+	write := func(parts ...string) {
+		writeKeyParts(hash, parts...)
+	}
+	write(routeCache.Name, req.Method, reqVals.Spec.PathTemplate, reqVals.Conditions.String(), req.URL.Path)
+
+	query := req.URL.Query()
+	queryParams := append([]string(nil), routeCache.QueryParams...)
+	sort.Strings(queryParams)
+	for _, param := range queryParams {
+		write(param)
+		write(query[param]...)
+	}
+	headers := make([]string, len(routeCache.Headers))
+	for i, header := range routeCache.Headers {
+		headers[i] = http.CanonicalHeaderKey(header)
+	}
+	sort.Strings(headers)
+	for _, header := range headers {
+		write(header)
+		write(req.Header.Values(header)...)
+	}
+	if !routeCache.Public {
+		writeRequestIdentity(hash, req)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// parseCacheControl returns the directives of the provided Cache-Control header value by their lowercase name.
+// Directives without an argument map to an empty string.
+func parseCacheControl(cacheControl string) map[string]string {
+	directives := make(map[string]string)
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if name == "" {
+			continue
+		}
+		directives[strings.ToLower(name)] = strings.Trim(value, `"`)
+	}
+	return directives
+}
+
+// recordedResponse is a response recorded by a recordingResponseWriter.
+type recordedResponse struct {
+	status int
+	// header stores the headers set by the route.
+	header http.Header
+	body   []byte
+}
+
+// size returns the approximate number of bytes used by the recorded response.
+func (r recordedResponse) size() int64 {
+	size := int64(len(r.body))
+	for k, values := range r.header {
+		for _, v := range values {
+			size += int64(len(k) + len(v))
+		}
+	}
+	return size
+}
+
+// writeTo writes the recorded response to the provided writer.
+func (r recordedResponse) writeTo(rw http.ResponseWriter) {
+	header := rw.Header()
+	for k, v := range r.header {
+		header[k] = append([]string(nil), v...)
+	}
+	rw.WriteHeader(r.status)
+	_, _ = rw.Write(r.body)
+}
+
+// recordingResponseWriter records the response written to it, up to a maximum body size, while writing it to the
+// wrapped writer.
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	maxSize int64
+	// initialHeader stores the headers of the response before the handler was invoked, which are not recorded since
+	// they were not set by the route.
+	initialHeader http.Header
+
+	status   int
+	header   http.Header
+	buf      bytes.Buffer
+	tooLarge bool
+	hijacked bool
+}
+
+func (w *recordingResponseWriter) WriteHeader(status int) {
+	if w.status == 0 && status >= http.StatusOK {
+		w.status = status
+		w.header = make(http.Header)
+		for k, v := range w.Header() {
+			if !stringSlicesEqual(v, w.initialHeader[k]) {
+				w.header[k] = append([]string(nil), v...)
+			}
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.tooLarge {
+		if int64(w.buf.Len()+len(p)) > w.maxSize {
+			w.tooLarge = true
+			w.buf = bytes.Buffer{}
+		} else {
+			_, _ = w.buf.Write(p)
+		}
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *recordingResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.WriteHeader(http.StatusOK)
+		}
+		flusher.Flush()
+	}
+}
+
+func (w *recordingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("the ResponseWriter doesn't support the Hijacker interface")
+	}
+	w.hijacked = true
+	return hijacker.Hijack()
+}
+
+// recorded returns true if the complete response was recorded.
+func (w *recordingResponseWriter) recorded() bool {
+	return w.status != 0 && !w.hijacked && !w.tooLarge
+}
+
+func (w *recordingResponseWriter) response() recordedResponse {
+	return recordedResponse{status: w.status, header: w.header, body: w.buf.Bytes()}
+}
+
+// cacheable returns true if the recorded response can be cached for a route with the provided cache.
+func (w *recordingResponseWriter) cacheable(routeCache *wrouter.RouteCache) bool {
+	if !w.recorded() || w.status != http.StatusOK || w.header.Get("Set-Cookie") != "" {
+		return false
+	}
+	directives := parseCacheControl(w.header.Get("Cache-Control"))
+	if _, ok := directives["no-store"]; ok {
+		return false
+	}
+	if _, ok := directives["private"]; ok && routeCache.Public {
+		return false
+	}
+	return varyKeyed(w.header.Values("Vary"), routeCache)
+}
+
+// varyKeyed returns true if all the request headers named by the provided values of a Vary header are part of the
+// cache key of the provided route cache, so that the cached response is only served to requests it applies to.
+func varyKeyed(vary []string, routeCache *wrouter.RouteCache) bool {
+	if len(vary) == 0 {
+		return true
+	}
+	keyed := make(map[string]struct{}, len(routeCache.Headers)+len(requestIdentityHeaders))
+	for _, header := range routeCache.Headers {
+		keyed[http.CanonicalHeaderKey(header)] = struct{}{}
+	}
+	if !routeCache.Public {
+		for _, header := range requestIdentityHeaders {
+			keyed[header] = struct{}{}
+		}
+	}
+	for _, value := range vary {
+		for _, header := range strings.Split(value, ",") {
+			header = strings.TrimSpace(header)
+			if header == "" {
+				continue
+			}
+			if _, ok := keyed[http.CanonicalHeaderKey(header)]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
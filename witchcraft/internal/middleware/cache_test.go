@@ -0,0 +1,209 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/refreshable"
+	"github.com/palantir/witchcraft-go-server/v2/config"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/routertest"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	registry := metrics.NewRootMetricsRegistry()
+	cfg := refreshable.NewDefaultRefreshable(config.ResponseCacheConfig{
+		MaxEntries: 3,
+		TTLs: map[string]time.Duration{
+			"short":    time.Millisecond,
+			"disabled": 0,
+		},
+	})
+	cache := middleware.NewResponseCache(config.NewRefreshingResponseCacheConfig(cfg), registry)
+	r := wrouter.New(whttprouter.New())
+	r.AddRouteHandlerMiddleware(cache.Middleware())
+
+	handlerCalls := make(map[string]int)
+	countingHandler := func(name string, status int, header http.Header) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			handlerCalls[name]++
+			for k, v := range header {
+				rw.Header()[k] = v
+			}
+			rw.Header().Set("Content-Type", "text/plain")
+			rw.WriteHeader(status)
+			_, _ = fmt.Fprintf(rw, "%s %d", name, handlerCalls[name])
+		})
+	}
+	tags := metrics.MustNewTags(map[string]string{"endpoint": "items"})
+	require.NoError(t, r.Get("/items/{id}", countingHandler("items", http.StatusOK, nil), wrouter.MetricTags(tags), wrouter.RouteCached(wrouter.RouteCache{
+		Name:        "items",
+		QueryParams: []string{"format"},
+		Headers:     []string{"accept-language"},
+	})))
+	require.NoError(t, r.Get("/public", countingHandler("public", http.StatusOK, nil), wrouter.RouteCached(wrouter.RouteCache{Name: "public", Public: true})))
+	require.NoError(t, r.Get("/short", countingHandler("short", http.StatusOK, nil), wrouter.RouteCached(wrouter.RouteCache{Name: "short"})))
+	require.NoError(t, r.Get("/disabled", countingHandler("disabled", http.StatusOK, nil), wrouter.RouteCached(wrouter.RouteCache{Name: "disabled"})))
+	require.NoError(t, r.Get("/error", countingHandler("error", http.StatusInternalServerError, nil), wrouter.RouteCached(wrouter.RouteCache{Name: "error"})))
+	require.NoError(t, r.Get("/cookie", countingHandler("cookie", http.StatusOK, http.Header{"Set-Cookie": {"a=b"}}), wrouter.RouteCached(wrouter.RouteCache{Name: "cookie"})))
+	require.NoError(t, r.Get("/nostore", countingHandler("nostore", http.StatusOK, http.Header{"Cache-Control": {"no-store"}}), wrouter.RouteCached(wrouter.RouteCache{Name: "nostore"})))
+	require.NoError(t, r.Get("/vary", countingHandler("vary", http.StatusOK, http.Header{"Vary": {"Accept"}}), wrouter.RouteCached(wrouter.RouteCache{Name: "vary"})))
+	require.NoError(t, r.Get("/varyall", countingHandler("varyall", http.StatusOK, http.Header{"Vary": {"*"}}), wrouter.RouteCached(wrouter.RouteCache{Name: "varyall"})))
+	require.NoError(t, r.Get("/varykeyed", countingHandler("varykeyed", http.StatusOK, http.Header{"Vary": {"accept-language, Authorization"}}), wrouter.RouteCached(wrouter.RouteCache{
+		Name:    "varykeyed",
+		Headers: []string{"Accept-Language"},
+	})))
+	require.NoError(t, r.Post("/items/{id}", countingHandler("post", http.StatusOK, nil), wrouter.RouteCached(wrouter.RouteCache{Name: "post"})))
+
+	t.Run("hit", func(t *testing.T) {
+		rw := routertest.ServeRequest(r, http.MethodGet, "/items/1", nil, nil)
+		assert.Equal(t, "items 1", rw.Body.String())
+		assert.Empty(t, rw.Header().Get("Age"))
+
+		rw = routertest.ServeRequest(r, http.MethodGet, "/items/1?other=ignored", nil, nil)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "items 1", rw.Body.String())
+		assert.Equal(t, "text/plain", rw.Header().Get("Content-Type"))
+		assert.Equal(t, "0", rw.Header().Get("Age"))
+		assert.Equal(t, 1, handlerCalls["items"])
+	})
+
+	t.Run("cache key", func(t *testing.T) {
+		assert.Equal(t, "items 2", routertest.ServeRequest(r, http.MethodGet, "/items/2", nil, nil).Body.String())
+		assert.Equal(t, "items 3", routertest.ServeRequest(r, http.MethodGet, "/items/1?format=json", nil, nil).Body.String())
+		assert.Equal(t, "items 4", routertest.ServeRequest(r, http.MethodGet, "/items/1", http.Header{"Accept-Language": {"fr"}}, nil).Body.String())
+		assert.Equal(t, "items 5", routertest.ServeRequest(r, http.MethodGet, "/items/1", http.Header{"Authorization": {"Bearer other"}}, nil).Body.String())
+
+		assert.Equal(t, "public 1", routertest.ServeRequest(r, http.MethodGet, "/public", http.Header{"Authorization": {"Bearer a"}}, nil).Body.String())
+		assert.Equal(t, "public 1", routertest.ServeRequest(r, http.MethodGet, "/public", http.Header{"Authorization": {"Bearer b"}}, nil).Body.String())
+		require.Equal(t, 3, cache.Purge(""))
+	})
+
+	t.Run("request directives", func(t *testing.T) {
+		assert.Equal(t, "items 6", routertest.ServeRequest(r, http.MethodGet, "/items/1", nil, nil).Body.String())
+		assert.Equal(t, "items 6", routertest.ServeRequest(r, http.MethodGet, "/items/1", http.Header{"Cache-Control": {"max-age=3600"}}, nil).Body.String())
+		assert.Equal(t, "items 7", routertest.ServeRequest(r, http.MethodGet, "/items/1", http.Header{"Cache-Control": {"no-store"}}, nil).Body.String())
+		assert.Equal(t, "items 6", routertest.ServeRequest(r, http.MethodGet, "/items/1", nil, nil).Body.String())
+		assert.Equal(t, "items 8", routertest.ServeRequest(r, http.MethodGet, "/items/1", http.Header{"Cache-Control": {"no-cache"}}, nil).Body.String())
+		assert.Equal(t, "items 8", routertest.ServeRequest(r, http.MethodGet, "/items/1", nil, nil).Body.String())
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, "items 9", routertest.ServeRequest(r, http.MethodGet, "/items/1", http.Header{"Cache-Control": {"max-age=0"}}, nil).Body.String())
+	})
+
+	t.Run("not cached", func(t *testing.T) {
+		for _, name := range []string{"short", "disabled", "error", "cookie", "nostore", "vary", "varyall"} {
+			routertest.ServeRequest(r, http.MethodGet, "/"+name, nil, nil)
+			time.Sleep(5 * time.Millisecond)
+			routertest.ServeRequest(r, http.MethodGet, "/"+name, nil, nil)
+			assert.Equal(t, 2, handlerCalls[name], name)
+		}
+		routertest.ServeRequest(r, http.MethodPost, "/items/1", nil, nil)
+		routertest.ServeRequest(r, http.MethodPost, "/items/1", nil, nil)
+		assert.Equal(t, 2, handlerCalls["post"])
+	})
+
+	t.Run("vary by keyed headers", func(t *testing.T) {
+		assert.Equal(t, "varykeyed 1", routertest.ServeRequest(r, http.MethodGet, "/varykeyed", nil, nil).Body.String())
+		assert.Equal(t, "varykeyed 1", routertest.ServeRequest(r, http.MethodGet, "/varykeyed", nil, nil).Body.String())
+		require.Equal(t, 1, cache.Purge("varykeyed"))
+	})
+
+	t.Run("disabled by configuration", func(t *testing.T) {
+		calls := handlerCalls["items"]
+		require.NoError(t, cfg.Update(config.ResponseCacheConfig{Disabled: true}))
+		routertest.ServeRequest(r, http.MethodGet, "/items/1", nil, nil)
+		assert.Equal(t, calls+1, handlerCalls["items"])
+		require.NoError(t, cfg.Update(config.ResponseCacheConfig{MaxEntries: 3}))
+	})
+
+	t.Run("eviction and purge", func(t *testing.T) {
+		cache.Purge("")
+		evictions := registry.Meter("server.response.cache.evict", tags...).Count()
+		for i := 1; i <= 4; i++ {
+			routertest.ServeRequest(r, http.MethodGet, fmt.Sprintf("/items/%d", i), nil, nil)
+		}
+		routertest.ServeRequest(r, http.MethodGet, "/public", nil, nil)
+		assert.Equal(t, evictions+2, registry.Meter("server.response.cache.evict", tags...).Count())
+
+		assert.Equal(t, 1, cache.Purge("public"))
+		assert.Equal(t, 2, cache.Purge("items"))
+		assert.Equal(t, 0, cache.Purge(""))
+	})
+
+	assert.Equal(t, int64(4), registry.Meter("server.response.cache.hit", tags...).Count())
+	assert.Equal(t, int64(12), registry.Meter("server.response.cache.miss", tags...).Count())
+}
+
+func TestResponseCacheCallerIdentity(t *testing.T) {
+	cfg := refreshable.NewDefaultRefreshable(config.ResponseCacheConfig{})
+	cache := middleware.NewResponseCache(config.NewRefreshingResponseCacheConfig(cfg), metrics.NewRootMetricsRegistry())
+	r := wrouter.New(whttprouter.New())
+	r.AddRouteHandlerMiddleware(cache.Middleware())
+
+	var handlerCalls int
+	require.NoError(t, r.Get("/profile", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		handlerCalls++
+		_, _ = fmt.Fprintf(rw, "profile %d", handlerCalls)
+	}), wrouter.RouteCached(wrouter.RouteCache{Name: "profile"})))
+
+	for _, tc := range []struct {
+		header http.Header
+		want   string
+	}{
+		{header: http.Header{"Cookie": {"session=a"}}, want: "profile 1"},
+		{header: http.Header{"Cookie": {"session=b"}}, want: "profile 2"},
+		{header: http.Header{"Cookie": {"session=a"}}, want: "profile 1"},
+		{header: http.Header{"Cookie": {"session=a"}, "Authorization": {"Bearer a"}}, want: "profile 3"},
+		{header: nil, want: "profile 4"},
+	} {
+		assert.Equal(t, tc.want, routertest.ServeRequest(r, http.MethodGet, "/profile", tc.header, nil).Body.String(), tc.header)
+	}
+}
+
+func TestResponseCacheMaxTotalSize(t *testing.T) {
+	registry := metrics.NewRootMetricsRegistry()
+	cfg := refreshable.NewDefaultRefreshable(config.ResponseCacheConfig{MaxTotalSize: 2500})
+	cache := middleware.NewResponseCache(config.NewRefreshingResponseCacheConfig(cfg), registry)
+	r := wrouter.New(whttprouter.New())
+	r.AddRouteHandlerMiddleware(cache.Middleware())
+
+	tags := metrics.MustNewTags(map[string]string{"endpoint": "blobs"})
+	require.NoError(t, r.Get("/blobs/{size}", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		size, err := strconv.Atoi(wrouter.PathParams(req)["size"])
+		require.NoError(t, err)
+		_, _ = rw.Write(bytes.Repeat([]byte("a"), size))
+	}), wrouter.MetricTags(tags), wrouter.RouteCached(wrouter.RouteCache{Name: "blobs", Public: true})))
+
+	for _, size := range []string{"1000", "1001", "1002"} {
+		routertest.ServeRequest(r, http.MethodGet, "/blobs/"+size, nil, nil)
+	}
+	assert.Equal(t, int64(1), registry.Meter("server.response.cache.evict", tags...).Count())
+
+	// responses larger than the maximum total size are not cached
+	routertest.ServeRequest(r, http.MethodGet, "/blobs/3000", nil, nil)
+	assert.Equal(t, int64(1), registry.Meter("server.response.cache.evict", tags...).Count())
+	assert.Equal(t, 2, cache.Purge(""))
+}
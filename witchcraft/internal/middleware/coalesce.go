@@ -0,0 +1,158 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-tracing/wtracing"
+)
+
+const (
+	serverRequestCoalescedMetricName = "server.request.coalesced"
+
+	// maxCoalescedResponseSize is the maximum size in bytes of the response bodies that are recorded to be shared with
+	// coalesced requests.
+	maxCoalescedResponseSize = 1 << 20
+
+	// coalescedSpanName is the name of the child span of the span of a request that covers the time the request waits
+	// for an identical request to complete.
+	coalescedSpanName = "witchcraft-go-server coalesced request"
+	// coalescedTraceIDSpanTag and coalescedSpanIDSpanTag are the names of the tags of the coalesced request span that
+	// identify the span of the request that the waiting request follows.
+	coalescedTraceIDSpanTag = "coalescedTraceId"
+	coalescedSpanIDSpanTag  = "coalescedSpanId"
+)
+
+// coalescedCall is the execution of the handler for a request that identical requests wait for.
+type coalescedCall struct {
+	// done is closed once the handler has completed.
+	done chan struct{}
+	// leader is the span context of the request for which the handler runs.
+	leader wtracing.SpanContext
+	// response is the response of the handler. Set before done is closed, and only if the complete response was
+	// recorded and can be shared with the waiting requests.
+	response *recordedResponse
+}
+
+// NewRouteCoalescing returns a middleware that coalesces concurrent identical GET and HEAD requests to routes registered
+// with CoalesceRequests. The first request runs the handler while the response it writes is recorded, and identical
+// requests that arrive before the handler completes wait for it and receive a copy of the recorded response. Requests
+// are identical if they have the same route, method, path, query and Authorization and Cookie headers. If the handler
+// of the first request panics, hijacks the connection, writes a response that sets cookies or whose body is larger
+// than 1 MiB, or the client of the first request disconnects, waiting requests run the handler themselves. Coalesced
+// requests mark the coalesced request meter for the route. The time a request waits is recorded in a child span of its
+// span that is tagged with the trace and span IDs of the request that ran the handler, which links the trace of the
+// waiting request to the work that produced its response.
+func NewRouteCoalescing(mr metrics.RootRegistry) wrouter.RouteHandlerMiddleware {
+	var mu sync.Mutex
+	calls := make(map[string]*coalescedCall)
+	return func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+		if !reqVals.CoalesceRequests || (req.Method != http.MethodGet && req.Method != http.MethodHead) {
+			next(rw, req, reqVals)
+			return
+		}
+		key := coalescingKey(req, reqVals)
+
+		mu.Lock()
+		if call, ok := calls[key]; ok {
+			mu.Unlock()
+			span := startCoalescedSpan(req, call.leader)
+			select {
+			case <-call.done:
+				span.Finish()
+			case <-req.Context().Done():
+				// the client disconnected while waiting
+				span.Finish()
+				return
+			}
+			if call.response == nil {
+				next(rw, req, reqVals)
+				return
+			}
+			if !reqVals.DisableTelemetry {
+				mr.Meter(serverRequestCoalescedMetricName, reqVals.MetricTags...).Mark(1)
+			}
+			call.response.writeTo(rw)
+			return
+		}
+		call := &coalescedCall{done: make(chan struct{})}
+		if span := wtracing.SpanFromContext(req.Context()); span != nil {
+			call.leader = span.Context()
+		}
+		calls[key] = call
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			delete(calls, key)
+			mu.Unlock()
+			close(call.done)
+		}()
+		rrw := &recordingResponseWriter{
+			ResponseWriter: rw,
+			maxSize:        maxCoalescedResponseSize,
+			initialHeader:  rw.Header().Clone(),
+		}
+		next(rrw, req, reqVals)
+		// responses that set cookies are specific to the request that received them
+		if rrw.recorded() && rrw.header.Get("Set-Cookie") == "" && req.Context().Err() == nil {
+			response := rrw.response()
+			call.response = &response
+		}
+	}
+}
+
+// startCoalescedSpan starts the span that records that the provided request waits for the request with the provided
+// span context. The span is a child of the span of the request and is tagged with the trace and span IDs of the request
+// it waits for. Returns a no-op span if the request does not have a span or its context does not have a tracer.
+func startCoalescedSpan(req *http.Request, leader wtracing.SpanContext) wtracing.Span {
+	if wtracing.SpanFromContext(req.Context()) == nil {
+		return noopSpan{}
+	}
+	var options []wtracing.SpanOption
+	if leader.ID != "" {
+		options = append(options,
+			wtracing.WithSpanTag(coalescedTraceIDSpanTag, string(leader.TraceID)),
+			wtracing.WithSpanTag(coalescedSpanIDSpanTag, string(leader.ID)),
+		)
+	}
+	span, _ := wtracing.StartSpanFromTracerInContext(req.Context(), coalescedSpanName, options...)
+	return span
+}
+
+// noopSpan is a wtracing.Span that records nothing.
+type noopSpan struct{}
+
+func (noopSpan) Context() wtracing.SpanContext {
+	return wtracing.SpanContext{}
+}
+
+func (noopSpan) Tag(string, string) {}
+
+func (noopSpan) Finish() {}
+
+// coalescingKey returns the key that identifies the requests that are coalesced with the provided request.
+func coalescingKey(req *http.Request, reqVals wrouter.RequestVals) string {
+	hash := sha256.New()
+	writeKeyParts(hash, req.Method, reqVals.Spec.PathTemplate, reqVals.Conditions.String(), req.URL.Path, req.URL.Query().Encode())
+	writeRequestIdentity(hash, req)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/routertest"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/palantir/witchcraft-go-tracing/wtracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteCoalescing(t *testing.T) {
+	registry := metrics.NewRootMetricsRegistry()
+	tracer := &recordingTracer{}
+	r := wrouter.New(whttprouter.New())
+	// set a tracer and a span on the context of each request like the request and trace span middleware
+	r.AddRouteHandlerMiddleware(func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+		ctx := wtracing.ContextWithTracer(req.Context(), tracer)
+		ctx = wtracing.ContextWithSpan(ctx, tracer.StartSpan(req.Method))
+		next(rw, req.WithContext(ctx), reqVals)
+	})
+	r.AddRouteHandlerMiddleware(middleware.NewRouteCoalescing(registry))
+
+	var handlerCalls int32
+	release := make(chan struct{})
+	// buffered so that handlers whose start is not awaited do not block
+	started := make(chan struct{}, 10)
+	blockingHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls := atomic.AddInt32(&handlerCalls, 1)
+		started <- struct{}{}
+		<-release
+		rw.Header().Set("Content-Type", "text/plain")
+		if req.URL.Query().Get("cookie") != "" {
+			rw.Header().Set("Set-Cookie", "session="+req.URL.Query().Get("cookie"))
+		}
+		rw.WriteHeader(http.StatusAccepted)
+		if req.URL.Query().Get("large") != "" {
+			_, _ = rw.Write(bytes.Repeat([]byte("a"), 2<<20))
+		}
+		_, _ = fmt.Fprintf(rw, "%s %d", req.URL.RawQuery, calls)
+	})
+	tags := metrics.MustNewTags(map[string]string{"endpoint": "expensive"})
+	require.NoError(t, r.Get("/expensive", blockingHandler, wrouter.CoalesceRequests(), wrouter.MetricTags(tags)))
+	require.NoError(t, r.Get("/uncoalesced", blockingHandler))
+
+	// doRequestsWithHeaders sends the requests to the provided targets, the i-th of which has the i-th header if any.
+	doRequestsWithHeaders := func(headers []http.Header, targets ...string) []*httptest.ResponseRecorder {
+		// discard the starts of the handlers of previous requests
+		for len(started) > 0 {
+			<-started
+		}
+		responses := make([]*httptest.ResponseRecorder, len(targets))
+		var wg sync.WaitGroup
+		for i, target := range targets {
+			// start the first request before the others so that it runs the handler
+			if i == 1 {
+				<-started
+			}
+			wg.Add(1)
+			var header http.Header
+			if i < len(headers) {
+				header = headers[i]
+			}
+			go func(i int, target string) {
+				defer wg.Done()
+				responses[i] = routertest.ServeRequest(r, http.MethodGet, target, header, nil)
+			}(i, target)
+		}
+		// give the other requests time to wait for the first request
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		return responses
+	}
+	doRequests := func(targets ...string) []*httptest.ResponseRecorder {
+		return doRequestsWithHeaders(nil, targets...)
+	}
+
+	t.Run("identical requests", func(t *testing.T) {
+		responses := doRequests("/expensive?a=1", "/expensive?a=1", "/expensive?a=1")
+		for _, rw := range responses {
+			assert.Equal(t, http.StatusAccepted, rw.Code)
+			assert.Equal(t, "text/plain", rw.Header().Get("Content-Type"))
+			assert.Equal(t, "a=1 1", rw.Body.String())
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&handlerCalls))
+		assert.Equal(t, int64(2), registry.Meter("server.request.coalesced", tags...).Count())
+
+		spans := tracer.startedSpans()
+		var reqSpans, coalescedSpans []*recordingSpan
+		for _, span := range spans {
+			if span.name == "witchcraft-go-server coalesced request" {
+				coalescedSpans = append(coalescedSpans, span)
+			} else {
+				reqSpans = append(reqSpans, span)
+			}
+		}
+		require.Len(t, reqSpans, 3)
+		require.Len(t, coalescedSpans, 2)
+		leader := reqSpans[0]
+		for _, span := range reqSpans {
+			assert.Empty(t, span.tags)
+		}
+		followerIDs := map[wtracing.SpanID]struct{}{reqSpans[1].ctx.ID: {}, reqSpans[2].ctx.ID: {}}
+		for _, span := range coalescedSpans {
+			require.NotNil(t, span.parent)
+			assert.Contains(t, followerIDs, span.parent.ID)
+			delete(followerIDs, span.parent.ID)
+			assert.Equal(t, map[string]string{
+				"coalescedTraceId": "trace",
+				"coalescedSpanId":  string(leader.ctx.ID),
+			}, span.tags)
+			assert.True(t, span.finished)
+		}
+	})
+
+	t.Run("different requests", func(t *testing.T) {
+		atomic.StoreInt32(&handlerCalls, 0)
+		release = make(chan struct{})
+		doRequests("/expensive?a=1", "/expensive?a=2", "/uncoalesced", "/uncoalesced")
+		assert.Equal(t, int32(4), atomic.LoadInt32(&handlerCalls))
+	})
+
+	t.Run("different callers", func(t *testing.T) {
+		atomic.StoreInt32(&handlerCalls, 0)
+		release = make(chan struct{})
+		doRequestsWithHeaders([]http.Header{
+			{"Cookie": {"session=a"}},
+			{"Cookie": {"session=b"}},
+			{"Authorization": {"Bearer a"}},
+		}, "/expensive?a=1", "/expensive?a=1", "/expensive?a=1")
+		assert.Equal(t, int32(3), atomic.LoadInt32(&handlerCalls))
+	})
+
+	t.Run("responses that are not shared", func(t *testing.T) {
+		for _, target := range []string{"/expensive?cookie=a", "/expensive?large=1"} {
+			atomic.StoreInt32(&handlerCalls, 0)
+			release = make(chan struct{})
+			responses := doRequests(target, target)
+			assert.Equal(t, int32(2), atomic.LoadInt32(&handlerCalls), target)
+			for _, rw := range responses {
+				assert.Equal(t, http.StatusAccepted, rw.Code, target)
+			}
+		}
+	})
+}
+
+// recordingTracer records the spans that it starts in the order in which they are started.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) StartSpan(name string, options ...wtracing.SpanOption) wtracing.Span {
+	impl := wtracing.FromSpanOptions(options...)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &recordingSpan{
+		name:   name,
+		ctx:    wtracing.SpanContext{TraceID: "trace", ID: wtracing.SpanID(fmt.Sprint(len(t.spans) + 1))},
+		parent: impl.ParentSpan,
+		tags:   make(map[string]string),
+	}
+	for k, v := range impl.Tags {
+		span.tags[k] = v
+	}
+	t.spans = append(t.spans, span)
+	return span
+}
+
+func (t *recordingTracer) startedSpans() []*recordingSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*recordingSpan(nil), t.spans...)
+}
+
+type recordingSpan struct {
+	name   string
+	ctx    wtracing.SpanContext
+	parent *wtracing.SpanContext
+
+	mu       sync.Mutex
+	tags     map[string]string
+	finished bool
+}
+
+func (s *recordingSpan) Context() wtracing.SpanContext {
+	return s.ctx
+}
+
+func (s *recordingSpan) Tag(key string, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tags[key] = value
+}
+
+func (s *recordingSpan) Finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finished = true
+}
@@ -0,0 +1,340 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/refreshable"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+const (
+	serverResponseUncompressedSizeMetricName = "server.response.size.uncompressed"
+
+	defaultCompressionMinSize = 1024
+
+	gzipEncoding    = "gzip"
+	deflateEncoding = "deflate"
+)
+
+// compressibleContentTypes are the media types other than "text/*", "*+json" and "*+xml" whose responses are
+// compressed.
+var compressibleContentTypes = map[string]struct{}{
+	"application/javascript": {},
+	"application/json":       {},
+	"application/x-ndjson":   {},
+	"application/xml":        {},
+	"application/yaml":       {},
+	"image/svg+xml":          {},
+}
+
+// compressionEncoder is implemented by gzip.Writer and zlib.Writer.
+type compressionEncoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+var compressionEncoderPools = map[string]*sync.Pool{
+	gzipEncoding: {New: func() interface{} {
+		return gzip.NewWriter(io.Discard)
+	}},
+	deflateEncoding: {New: func() interface{} {
+		return zlib.NewWriter(io.Discard)
+	}},
+}
+
+// NewRouteCompression returns a middleware that, if enabled, compresses responses using the gzip or deflate encoding if
+// the request accepts one of them, the content type of the response is compressible and the body of the response is at
+// least the provided minimum size (or 1024 bytes if the minimum size is not positive). The response is buffered until
+// the minimum size is reached, the handler returns or the handler flushes the response, so flushed responses are
+// compressed regardless of their size. Responses with a Content-Encoding and partial content responses (including
+// responses with a Content-Range) are never compressed. The entity tag of a compressed response is weakened, since the
+// compressed representation is not byte-for-byte identical to the uncompressed one. The middleware records the
+// uncompressed size of responses in the uncompressed response size histogram for the route, while the response size
+// histogram records the size of the (possibly compressed) response that was written.
+func NewRouteCompression(enabled refreshable.Bool, minSize refreshable.Int64, mr metrics.RootRegistry) wrouter.RouteHandlerMiddleware {
+	return func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+		if reqVals.DisableCompression || enabled == nil || !enabled.CurrentBool() || req.Method == http.MethodHead {
+			next(rw, req, reqVals)
+			return
+		}
+		currMinSize := int64(defaultCompressionMinSize)
+		if minSize != nil && minSize.CurrentInt64() > 0 {
+			currMinSize = minSize.CurrentInt64()
+		}
+
+		crw := &compressionResponseWriter{
+			ResponseWriter: rw,
+			encoding:       negotiateCompressionEncoding(req.Header.Get("Accept-Encoding")),
+			minSize:        currMinSize,
+		}
+		defer crw.close()
+		next(crw, req, reqVals)
+		if !reqVals.DisableTelemetry {
+			mr.Histogram(serverResponseUncompressedSizeMetricName, reqVals.MetricTags...).Update(crw.uncompressedSize)
+		}
+	}
+}
+
+// negotiateCompressionEncoding returns the supported encoding with the highest quality value in the provided
+// Accept-Encoding header value, preferring gzip over deflate if their quality values are equal. Returns an empty string
+// if neither encoding is accepted.
+func negotiateCompressionEncoding(acceptEncoding string) string {
+	gzipQuality := AcceptEncodingQuality(acceptEncoding, gzipEncoding)
+	deflateQuality := AcceptEncodingQuality(acceptEncoding, deflateEncoding)
+	switch {
+	case gzipQuality <= 0 && deflateQuality <= 0:
+		return ""
+	case gzipQuality >= deflateQuality:
+		return gzipEncoding
+	default:
+		return deflateEncoding
+	}
+}
+
+// AcceptEncodingQuality returns the quality value of the provided content coding in the provided Accept-Encoding header
+// value. Codings are matched case-insensitively, and the "*" coding applies to codings that are not listed explicitly.
+// Returns 0 if the coding is not accepted.
+func AcceptEncodingQuality(acceptEncoding, coding string) float64 {
+	quality, wildcardQuality := -1.0, 0.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(part, ";")
+		partCoding := strings.TrimSpace(params[0])
+		isWildcard := partCoding == "*"
+		if !isWildcard && !strings.EqualFold(partCoding, coding) {
+			continue
+		}
+		partQuality := 1.0
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if !strings.EqualFold(strings.TrimSpace(name), "q") {
+				continue
+			}
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				partQuality = parsed
+			}
+		}
+		if isWildcard {
+			wildcardQuality = partQuality
+		} else {
+			quality = partQuality
+		}
+	}
+	if quality < 0 {
+		return wildcardQuality
+	}
+	return quality
+}
+
+// isCompressibleContentType returns true if responses with the provided Content-Type are compressed.
+func isCompressibleContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+	_, ok := compressibleContentTypes[mediaType]
+	return ok
+}
+
+// compressionResponseWriter compresses the response written to it using its encoding if the response is eligible for
+// compression. The status code and body of the response are buffered until it is decided whether to compress the
+// response so that the headers of the response can be updated.
+type compressionResponseWriter struct {
+	http.ResponseWriter
+	// encoding is the encoding negotiated for the request. Empty if the request does not accept a supported encoding.
+	encoding string
+	minSize  int64
+
+	status  int
+	buf     []byte
+	decided bool
+	// encoder compresses the response. Nil if the response is not compressed.
+	encoder          compressionEncoder
+	uncompressedSize int64
+	hijacked         bool
+}
+
+func (w *compressionResponseWriter) WriteHeader(status int) {
+	if w.decided || w.status != 0 {
+		// let the wrapped writer handle superfluous calls
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if status < http.StatusOK {
+		// informational responses are written immediately and do not affect the final response
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+	if status == http.StatusNotModified && w.encoding != "" {
+		// a 304 response carries the entity tag of the response that would have been compressed for the request
+		weakenETag(w.Header())
+	}
+	if !w.bodyCompressible() {
+		w.decide(false)
+		return
+	}
+	if contentLength := w.Header().Get("Content-Length"); contentLength != "" {
+		if size, err := strconv.ParseInt(contentLength, 10, 64); err == nil {
+			w.decide(size >= w.minSize && w.compressible())
+		}
+	}
+}
+
+func (w *compressionResponseWriter) Write(p []byte) (int, error) {
+	w.uncompressedSize += int64(len(p))
+	if !w.decided {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		w.buf = append(w.buf, p...)
+		if int64(len(w.buf)) < w.minSize {
+			return len(p), nil
+		}
+		if err := w.decide(w.bodyCompressible() && w.compressible()); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush writes the response that has been buffered and flushes the encoder and the wrapped writer. If it has not been
+// decided whether to compress the response, the response is compressed if it is eligible regardless of its size.
+func (w *compressionResponseWriter) Flush() {
+	if !w.decided {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		if err := w.decide(w.bodyCompressible() && w.compressible()); err != nil {
+			return
+		}
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *compressionResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("the ResponseWriter doesn't support the Hijacker interface")
+	}
+	w.hijacked = true
+	return hijacker.Hijack()
+}
+
+// bodyCompressible returns true if the status of the response allows a body that can be compressed.
+func (w *compressionResponseWriter) bodyCompressible() bool {
+	switch w.status {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+	return w.Header().Get("Content-Encoding") == "" && w.Header().Get("Content-Range") == ""
+}
+
+// compressible returns true if the content type of the response is compressible. If the response does not have a
+// Content-Type header, it is set to the content type detected from the buffered body, which the wrapped writer would
+// otherwise detect from the compressed body.
+func (w *compressionResponseWriter) compressible() bool {
+	contentType := w.Header().Get("Content-Type")
+	if contentType == "" && len(w.buf) > 0 {
+		contentType = http.DetectContentType(w.buf)
+		w.Header().Set("Content-Type", contentType)
+	}
+	return isCompressibleContentType(contentType)
+}
+
+// decide records whether the response is compressed, updates the headers of the response accordingly and writes the
+// status code and the buffered body to the wrapped writer.
+func (w *compressionResponseWriter) decide(compressible bool) error {
+	w.decided = true
+	if compressible {
+		// the response varies by the accepted encodings even if the request does not accept a supported encoding
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+	if compressible && w.encoding != "" {
+		w.Header().Del("Content-Length")
+		w.Header().Set("Content-Encoding", w.encoding)
+		weakenETag(w.Header())
+		w.encoder = compressionEncoderPools[w.encoding].Get().(compressionEncoder)
+		w.encoder.Reset(w.ResponseWriter)
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if len(w.buf) == 0 {
+		return nil
+	}
+	buf := w.buf
+	w.buf = nil
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// weakenETag marks the entity tag in the provided headers as weak if it is strong. A strong entity tag promises that the
+// representation is byte-for-byte identical, which does not hold once the response is compressed. Weak entity tags
+// still match If-None-Match headers, which use the weak comparison.
+func weakenETag(header http.Header) {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+}
+
+// close writes the response if it has been buffered and completes the compressed response.
+func (w *compressionResponseWriter) close() {
+	if w.hijacked {
+		return
+	}
+	if !w.decided {
+		// the body is smaller than the minimum size
+		_ = w.decide(false)
+	}
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+		w.encoder.Reset(io.Discard)
+		compressionEncoderPools[w.encoding].Put(w.encoder)
+		w.encoder = nil
+	}
+}
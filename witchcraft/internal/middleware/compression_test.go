@@ -0,0 +1,214 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware_test
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/refreshable"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/routertest"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteCompression(t *testing.T) {
+	registry := metrics.NewRootMetricsRegistry()
+	enabled := refreshable.NewDefaultRefreshable(true)
+	r := wrouter.New(whttprouter.New())
+	r.AddRouteHandlerMiddleware(middleware.NewRouteCompression(refreshable.NewBool(enabled), refreshable.NewInt64(refreshable.NewDefaultRefreshable(int64(64))), registry))
+
+	largeBody := strings.Repeat(`{"key":"value"}`, 100)
+	writeHandler := func(contentType, body string) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if contentType != "" {
+				rw.Header().Set("Content-Type", contentType)
+			}
+			_, _ = rw.Write([]byte(body))
+		})
+	}
+	tags := metrics.MustNewTags(map[string]string{"endpoint": "json"})
+	require.NoError(t, r.Get("/json", writeHandler("application/json", largeBody), wrouter.MetricTags(tags)))
+	require.NoError(t, r.Get("/small", writeHandler("application/json", `{"key":"value"}`)))
+	require.NoError(t, r.Get("/sniffed", writeHandler("", strings.Repeat("plain text ", 100))))
+	require.NoError(t, r.Get("/binary", writeHandler("application/octet-stream", largeBody)))
+	require.NoError(t, r.Get("/optout", writeHandler("application/json", largeBody), wrouter.DisableCompression()))
+	require.NoError(t, r.Get("/encoded", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("Content-Encoding", "br")
+		_, _ = rw.Write([]byte(largeBody))
+	})))
+	require.NoError(t, r.Get("/length", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("Content-Length", strconv.Itoa(len(largeBody)))
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write([]byte(largeBody))
+	})))
+	require.NoError(t, r.Get("/range", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("Content-Range", "bytes 0-1499/3000")
+		_, _ = rw.Write([]byte(largeBody))
+	})))
+	require.NoError(t, r.Get("/etag", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("ETag", `"v1"`)
+		if req.Header.Get("If-None-Match") != "" {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(largeBody))
+	})))
+	require.NoError(t, r.Get("/stream", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/event-stream")
+		_, _ = rw.Write([]byte("data: 1\n\n"))
+		rw.(http.Flusher).Flush()
+		_, _ = rw.Write([]byte("data: 2\n\n"))
+	})))
+
+	doRequest := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		return routertest.ServeRequest(r, http.MethodGet, path, http.Header{"Accept-Encoding": {acceptEncoding}}, nil)
+	}
+	decode := func(t *testing.T, rw *httptest.ResponseRecorder) string {
+		var reader io.Reader
+		var err error
+		switch encoding := rw.Header().Get("Content-Encoding"); encoding {
+		case "gzip":
+			reader, err = gzip.NewReader(rw.Body)
+		case "deflate":
+			reader, err = zlib.NewReader(rw.Body)
+		default:
+			t.Fatalf("unexpected Content-Encoding %q", encoding)
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		rw := doRequest("/json", "deflate, gzip")
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "gzip", rw.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", rw.Header().Get("Vary"))
+		assert.Less(t, rw.Body.Len(), len(largeBody))
+		assert.Equal(t, largeBody, decode(t, rw))
+	})
+
+	t.Run("deflate", func(t *testing.T) {
+		rw := doRequest("/json", "gzip;q=0.5, deflate")
+		assert.Equal(t, "deflate", rw.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, decode(t, rw))
+	})
+
+	t.Run("no accepted encoding", func(t *testing.T) {
+		for _, acceptEncoding := range []string{"", "br", "gzip;q=0"} {
+			rw := doRequest("/json", acceptEncoding)
+			assert.Empty(t, rw.Header().Get("Content-Encoding"), acceptEncoding)
+			assert.Equal(t, "Accept-Encoding", rw.Header().Get("Vary"), acceptEncoding)
+			assert.Equal(t, largeBody, rw.Body.String(), acceptEncoding)
+		}
+	})
+
+	t.Run("below minimum size", func(t *testing.T) {
+		rw := doRequest("/small", "gzip")
+		assert.Empty(t, rw.Header().Get("Content-Encoding"))
+		assert.Equal(t, `{"key":"value"}`, rw.Body.String())
+	})
+
+	t.Run("sniffed content type", func(t *testing.T) {
+		rw := doRequest("/sniffed", "gzip")
+		assert.Equal(t, "text/plain; charset=utf-8", rw.Header().Get("Content-Type"))
+		assert.Equal(t, strings.Repeat("plain text ", 100), decode(t, rw))
+	})
+
+	t.Run("not compressed", func(t *testing.T) {
+		for _, path := range []string{"/binary", "/optout", "/encoded", "/range"} {
+			rw := doRequest(path, "gzip")
+			assert.NotEqual(t, "gzip", rw.Header().Get("Content-Encoding"), path)
+			assert.Empty(t, rw.Header().Get("Vary"), path)
+			assert.Equal(t, largeBody, rw.Body.String(), path)
+		}
+	})
+
+	t.Run("content length", func(t *testing.T) {
+		rw := doRequest("/length", "gzip")
+		assert.Equal(t, http.StatusCreated, rw.Code)
+		assert.Empty(t, rw.Header().Get("Content-Length"))
+		assert.Equal(t, largeBody, decode(t, rw))
+	})
+
+	t.Run("weakened entity tag", func(t *testing.T) {
+		rw := doRequest("/etag", "gzip")
+		assert.Equal(t, `W/"v1"`, rw.Header().Get("ETag"))
+		assert.Equal(t, largeBody, decode(t, rw))
+
+		rw = doRequest("/etag", "")
+		assert.Empty(t, rw.Header().Get("Content-Encoding"))
+		assert.Equal(t, `"v1"`, rw.Header().Get("ETag"))
+
+		rw = routertest.ServeRequest(r, http.MethodGet, "/etag", http.Header{"Accept-Encoding": {"gzip"}, "If-None-Match": {`W/"v1"`}}, nil)
+		assert.Equal(t, http.StatusNotModified, rw.Code)
+		assert.Equal(t, `W/"v1"`, rw.Header().Get("ETag"))
+	})
+
+	t.Run("flushed response", func(t *testing.T) {
+		rw := doRequest("/stream", "gzip")
+		assert.True(t, rw.Flushed)
+		assert.Equal(t, "data: 1\n\ndata: 2\n\n", decode(t, rw))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		require.NoError(t, enabled.Update(false))
+		defer func() {
+			require.NoError(t, enabled.Update(true))
+		}()
+		rw := doRequest("/json", "gzip")
+		assert.Empty(t, rw.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, rw.Body.String())
+	})
+
+	uncompressedSize := registry.Histogram("server.response.size.uncompressed", tags...)
+	assert.Equal(t, int64(5), uncompressedSize.Count())
+	assert.Equal(t, int64(len(largeBody)), uncompressedSize.Max())
+}
+
+func TestAcceptEncodingQuality(t *testing.T) {
+	for _, tc := range []struct {
+		acceptEncoding string
+		coding         string
+		want           float64
+	}{
+		{acceptEncoding: "", coding: "gzip", want: 0},
+		{acceptEncoding: "gzip", coding: "gzip", want: 1},
+		{acceptEncoding: "deflate, GZIP;Q=0.5", coding: "gzip", want: 0.5},
+		{acceptEncoding: "gzip ; q=0.2", coding: "gzip", want: 0.2},
+		{acceptEncoding: "br, *;q=0.1", coding: "gzip", want: 0.1},
+		{acceptEncoding: "*, gzip;q=0", coding: "gzip", want: 0},
+		{acceptEncoding: "*;q=0, br", coding: "br", want: 1},
+		{acceptEncoding: "br;q=invalid", coding: "br", want: 1},
+	} {
+		assert.Equal(t, tc.want, middleware.AcceptEncodingQuality(tc.acceptEncoding, tc.coding), "%q %s", tc.acceptEncoding, tc.coding)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/palantir/witchcraft-go-server/v2/config"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+const (
+	corsOriginHeader           = "Origin"
+	corsRequestMethodHeader    = "Access-Control-Request-Method"
+	corsRequestHeadersHeader   = "Access-Control-Request-Headers"
+	corsAllowOriginHeader      = "Access-Control-Allow-Origin"
+	corsAllowMethodsHeader     = "Access-Control-Allow-Methods"
+	corsAllowHeadersHeader     = "Access-Control-Allow-Headers"
+	corsAllowCredentialsHeader = "Access-Control-Allow-Credentials"
+	corsExposeHeadersHeader    = "Access-Control-Expose-Headers"
+	corsMaxAgeHeader           = "Access-Control-Max-Age"
+	corsWildcard               = "*"
+)
+
+// NewCORS returns middleware that handles cross-origin requests based on the current value of the provided
+// configuration, so configuration changes take effect on the next request. Preflight requests from allowed origins
+// are answered directly using the methods of the routes returned by registeredRoutes whose path template matches the
+// request path. Does nothing for requests without an Origin header or if no allowed origins are configured.
+func NewCORS(cfg config.RefreshableCORSConfig, registeredRoutes func() []wrouter.RouteSpec) wrouter.RequestHandlerMiddleware {
+	templates := &pathTemplateCache{
+		templates: make(map[string]wrouter.PathTemplate),
+	}
+	return func(rw http.ResponseWriter, r *http.Request, next http.Handler) {
+		corsCfg := cfg.CurrentCORSConfig()
+		origin := r.Header.Get(corsOriginHeader)
+		if len(corsCfg.AllowedOrigins) == 0 || origin == "" {
+			next.ServeHTTP(rw, r)
+			return
+		}
+		rw.Header().Add("Vary", corsOriginHeader)
+
+		allowOrigin, allowCredentials, ok := corsAllowOrigin(corsCfg, origin)
+		if !ok {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get(corsRequestMethodHeader) != "" {
+			methods := templates.allowedMethods(registeredRoutes(), r.URL.Path, corsCfg.AllowedMethods)
+			if len(methods) == 0 {
+				// no route is registered for the path
+				next.ServeHTTP(rw, r)
+				return
+			}
+			if !containsFold(methods, r.Header.Get(corsRequestMethodHeader)) {
+				rw.WriteHeader(http.StatusNoContent)
+				return
+			}
+			requestedHeaders := r.Header.Get(corsRequestHeadersHeader)
+			if !corsHeadersAllowed(corsCfg.AllowedHeaders, requestedHeaders) {
+				rw.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			rw.Header().Set(corsAllowOriginHeader, allowOrigin)
+			rw.Header().Set(corsAllowMethodsHeader, strings.Join(methods, ", "))
+			if requestedHeaders != "" {
+				rw.Header().Set(corsAllowHeadersHeader, requestedHeaders)
+			}
+			if allowCredentials {
+				rw.Header().Set(corsAllowCredentialsHeader, "true")
+			}
+			if corsCfg.MaxAge > 0 {
+				rw.Header().Set(corsMaxAgeHeader, strconv.Itoa(int(corsCfg.MaxAge.Seconds())))
+			}
+			rw.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		rw.Header().Set(corsAllowOriginHeader, allowOrigin)
+		if allowCredentials {
+			rw.Header().Set(corsAllowCredentialsHeader, "true")
+		}
+		if len(corsCfg.ExposedHeaders) > 0 {
+			rw.Header().Set(corsExposeHeadersHeader, strings.Join(corsCfg.ExposedHeaders, ", "))
+		}
+		next.ServeHTTP(rw, r)
+	}
+}
+
+// corsAllowOrigin returns the value of the Access-Control-Allow-Origin header for the provided origin, whether requests
+// from the origin may include credentials and whether the origin is allowed. Origins that are only allowed because all
+// origins are allowed never allow credentials: echoing an arbitrary origin with credentials would allow any site to
+// make authenticated requests on behalf of the user, and browsers reject a wildcard for requests with credentials.
+func corsAllowOrigin(cfg config.CORSConfig, origin string) (string, bool, bool) {
+	allowsAll := false
+	for _, allowed := range cfg.AllowedOrigins {
+		if allowed == corsWildcard {
+			allowsAll = true
+			continue
+		}
+		if corsOriginMatches(allowed, origin) {
+			return origin, cfg.AllowCredentials, true
+		}
+	}
+	if allowsAll {
+		return corsWildcard, false, true
+	}
+	return "", false, false
+}
+
+// corsOriginMatches returns true if origin matches the allowed origin pattern. A "*" in the pattern matches one or more
+// subdomain labels.
+func corsOriginMatches(pattern, origin string) bool {
+	wildcardIdx := strings.Index(pattern, corsWildcard)
+	if wildcardIdx == -1 {
+		return strings.EqualFold(pattern, origin)
+	}
+	prefix, suffix := strings.ToLower(pattern[:wildcardIdx]), strings.ToLower(pattern[wildcardIdx+1:])
+	origin = strings.ToLower(origin)
+	if len(origin) <= len(prefix)+len(suffix) || !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+		return false
+	}
+	subdomain := origin[len(prefix) : len(origin)-len(suffix)]
+	return !strings.ContainsAny(subdomain, "/:")
+}
+
+// corsHeadersAllowed returns true if all of the headers in the comma-separated requestedHeaders are allowed.
+func corsHeadersAllowed(allowedHeaders []string, requestedHeaders string) bool {
+	if requestedHeaders == "" || containsFold(allowedHeaders, corsWildcard) {
+		return true
+	}
+	for _, header := range strings.Split(requestedHeaders, ",") {
+		if header = strings.TrimSpace(header); header != "" && !containsFold(allowedHeaders, header) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathTemplateCache stores parsed path templates so that preflight requests do not parse every registered template.
+type pathTemplateCache struct {
+	mu        sync.Mutex
+	templates map[string]wrouter.PathTemplate
+}
+
+// allowedMethods returns the sorted methods of the routes that match the provided path. If restrictTo is non-empty,
+// only the methods that it contains are returned.
+func (c *pathTemplateCache) allowedMethods(routes []wrouter.RouteSpec, path string, restrictTo []string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	methods := make(map[string]struct{})
+	for _, route := range routes {
+		template, ok := c.templates[route.PathTemplate]
+		if !ok {
+			var err error
+			if template, err = wrouter.NewPathTemplate(route.PathTemplate); err != nil {
+				continue
+			}
+			c.templates[route.PathTemplate] = template
+		}
+		if !wrouter.MatchesPathTemplate(template, path) {
+			continue
+		}
+		if len(restrictTo) > 0 && !containsFold(restrictTo, route.Method) {
+			continue
+		}
+		methods[route.Method] = struct{}{}
+	}
+	allowed := make([]string, 0, len(methods))
+	for method := range methods {
+		allowed = append(allowed, method)
+	}
+	sort.Strings(allowed)
+	return allowed
+}
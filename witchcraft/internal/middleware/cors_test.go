@@ -0,0 +1,212 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/palantir/pkg/refreshable"
+	"github.com/palantir/witchcraft-go-server/v2/config"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/routertest"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCORS(t *testing.T) {
+	cfg := refreshable.NewDefaultRefreshable(config.CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		ExposedHeaders:   []string{"X-Request-Id"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+	r := newCORSRouter(t, cfg)
+
+	for _, tc := range []struct {
+		name        string
+		method      string
+		path        string
+		headers     map[string]string
+		wantStatus  int
+		wantHeaders map[string]string
+	}{
+		{
+			name:       "no origin",
+			method:     http.MethodGet,
+			path:       "/datasets/ri.1",
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			name:       "allowed origin",
+			method:     http.MethodGet,
+			path:       "/datasets/ri.1",
+			headers:    map[string]string{"Origin": "https://app.example.com"},
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Request-Id",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name:       "allowed wildcard subdomain origin",
+			method:     http.MethodGet,
+			path:       "/datasets/ri.1",
+			headers:    map[string]string{"Origin": "https://a.b.example.org"},
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "https://a.b.example.org",
+			},
+		},
+		{
+			name:       "wildcard does not match parent domain",
+			method:     http.MethodGet,
+			path:       "/datasets/ri.1",
+			headers:    map[string]string{"Origin": "https://example.org"},
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			name:       "disallowed origin",
+			method:     http.MethodGet,
+			path:       "/datasets/ri.1",
+			headers:    map[string]string{"Origin": "https://evil.com"},
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+				"Vary":                        "Origin",
+			},
+		},
+		{
+			name:   "preflight",
+			method: http.MethodOptions,
+			path:   "/datasets/ri.1",
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  "PUT",
+				"Access-Control-Request-Headers": "content-type",
+			},
+			wantStatus: http.StatusNoContent,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Methods":     "GET, PUT",
+				"Access-Control-Allow-Headers":     "content-type",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Max-Age":           "600",
+			},
+		},
+		{
+			name:   "preflight for unregistered method",
+			method: http.MethodOptions,
+			path:   "/datasets/ri.1",
+			headers: map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": "DELETE",
+			},
+			wantStatus: http.StatusNoContent,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		{
+			name:   "preflight with disallowed header",
+			method: http.MethodOptions,
+			path:   "/datasets/ri.1",
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  "GET",
+				"Access-Control-Request-Headers": "X-Custom",
+			},
+			wantStatus: http.StatusNoContent,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		{
+			name:   "preflight for unregistered path",
+			method: http.MethodOptions,
+			path:   "/unknown",
+			headers: map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": "GET",
+			},
+			wantStatus: http.StatusNotFound,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, "http://localhost"+tc.path, nil)
+			require.NoError(t, err)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			rw := httptest.NewRecorder()
+			r.ServeHTTP(rw, req)
+			assert.Equal(t, tc.wantStatus, rw.Code)
+			for k, v := range tc.wantHeaders {
+				assert.Equal(t, v, rw.Header().Get(k), "header %s", k)
+			}
+		})
+	}
+}
+
+func TestCORSConfigUpdates(t *testing.T) {
+	cfg := refreshable.NewDefaultRefreshable(config.CORSConfig{})
+	r := newCORSRouter(t, cfg)
+
+	doRequest := func() *httptest.ResponseRecorder {
+		return routertest.ServeRequest(r, http.MethodGet, "http://localhost/datasets/ri.1", http.Header{"Origin": {"https://app.example.com"}}, nil)
+	}
+	assert.Equal(t, "", doRequest().Header().Get("Access-Control-Allow-Origin"))
+
+	require.NoError(t, cfg.Update(config.CORSConfig{AllowedOrigins: []string{"*"}}))
+	assert.Equal(t, "*", doRequest().Header().Get("Access-Control-Allow-Origin"))
+
+	require.NoError(t, cfg.Update(config.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}))
+	rw := doRequest()
+	assert.Equal(t, "*", rw.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "", rw.Header().Get("Access-Control-Allow-Credentials"))
+
+	require.NoError(t, cfg.Update(config.CORSConfig{AllowedOrigins: []string{"*", "https://*.example.com"}, AllowCredentials: true}))
+	rw = doRequest()
+	assert.Equal(t, "https://app.example.com", rw.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rw.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func newCORSRouter(t *testing.T, cfg refreshable.Refreshable) wrouter.RootRouter {
+	r := wrouter.New(whttprouter.New())
+	r.AddRequestHandlerMiddleware(middleware.NewCORS(config.NewRefreshingCORSConfig(cfg), r.RegisteredRoutes))
+	okHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+	require.NoError(t, r.Get("/datasets/{rid}", okHandler))
+	require.NoError(t, r.Put("/datasets/{rid}", okHandler))
+	return r
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/errors"
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-server/httpserver"
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/refreshable"
+	wparams "github.com/palantir/witchcraft-go-params"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+const (
+	serverRequestUncompressedSizeMetricName = "server.request.size.uncompressed"
+
+	defaultMaxDecompressedBodySize = 100 << 20
+)
+
+// requestBodyDecoders returns readers that decompress request bodies by their Content-Encoding.
+var requestBodyDecoders = map[string]func(io.Reader) (io.ReadCloser, error){
+	"gzip": func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	},
+	"x-gzip": func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	},
+	"deflate": zlib.NewReader,
+}
+
+// NewRouteDecompression returns a middleware that decompresses the bodies of requests to routes registered with
+// DecompressRequestBody, or of requests to all routes if enabled is true, whose Content-Encoding is gzip or deflate.
+// The handler receives a request without the Content-Encoding and Content-Length headers whose body is the
+// decompressed body. Bodies with other encodings are passed to the handler as is. Requests whose body does not start
+// with a valid header for its encoding are rejected with an invalid argument error. The decompressed size of the body
+// read by the handler is recorded in the uncompressed request size histogram for the route, while the request size
+// histogram records the size of the compressed body.
+//
+// Decompressed bodies are limited to the provided maximum size (or 100 MiB if the maximum size is not positive), so
+// small compressed bodies cannot decompress to arbitrarily large bodies even if no maximum body size is configured.
+// Requests whose decompressed body exceeds the maximum are handled like requests that exceed the maximum body size of
+// the route: the handler's read fails with an *http.MaxBytesError and the client receives a 413 response. The
+// middleware must run outside of the body limit middleware so that the maximum body size of the route also applies to
+// the decompressed body.
+func NewRouteDecompression(enabled refreshable.Bool, maxSize refreshable.Int64, mr metrics.RootRegistry) wrouter.RouteHandlerMiddleware {
+	return func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+		if !reqVals.DecompressRequestBody && (enabled == nil || !enabled.CurrentBool()) {
+			next(rw, req, reqVals)
+			return
+		}
+		encoding := strings.ToLower(strings.TrimSpace(req.Header.Get("Content-Encoding")))
+		newDecoder, ok := requestBodyDecoders[encoding]
+		if !ok || req.Body == nil || req.Body == http.NoBody {
+			next(rw, req, reqVals)
+			return
+		}
+		decoder, err := newDecoder(req.Body)
+		if err != nil {
+			cErr := errors.WrapWithInvalidArgument(err, wparams.NewSafeParam("contentEncoding", encoding))
+			httpserver.ErrHandler(req.Context(), http.StatusBadRequest, cErr)
+			httpserver.WriteJSONResponse(rw, cErr, http.StatusBadRequest)
+			return
+		}
+
+		body := &decompressedBody{decoder: decoder, body: req.Body}
+		// clone the request so that middleware running outside of this middleware observes the compressed request
+		decompressedReq := req.Clone(req.Context())
+		decompressedReq.Body = body
+		decompressedReq.ContentLength = -1
+		decompressedReq.Header.Del("Content-Encoding")
+		decompressedReq.Header.Del("Content-Length")
+		currMaxSize := int64(defaultMaxDecompressedBodySize)
+		if maxSize != nil && maxSize.CurrentInt64() > 0 {
+			currMaxSize = maxSize.CurrentInt64()
+		}
+		serveLimitedBody(rw, decompressedReq, reqVals, currMaxSize, mr, next)
+		if !reqVals.DisableTelemetry {
+			mr.Histogram(serverRequestUncompressedSizeMetricName, reqVals.MetricTags...).Update(body.size)
+		}
+	}
+}
+
+// decompressedBody reads the decompressed body of a request and records the number of decompressed bytes read.
+type decompressedBody struct {
+	decoder io.ReadCloser
+	body    io.ReadCloser
+	size    int64
+}
+
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	n, err := b.decoder.Read(p)
+	b.size += int64(n)
+	return n, err
+}
+
+func (b *decompressedBody) Close() error {
+	_ = b.decoder.Close()
+	return b.body.Close()
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/errors"
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/refreshable"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/routertest"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteDecompression(t *testing.T) {
+	registry := metrics.NewRootMetricsRegistry()
+	enabled := refreshable.NewDefaultRefreshable(false)
+	maxSize := refreshable.NewDefaultRefreshable(int64(0))
+	r := wrouter.New(whttprouter.New())
+	r.AddRouteHandlerMiddleware(middleware.NewRouteDecompression(refreshable.NewBool(enabled), refreshable.NewInt64(maxSize), registry))
+	r.AddRouteHandlerMiddleware(middleware.NewRouteBodyLimit(nil, registry))
+
+	echoHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Content-Encoding", req.Header.Get("Content-Encoding"))
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_, _ = rw.Write(body)
+	})
+	tags := metrics.MustNewTags(map[string]string{"endpoint": "echo"})
+	require.NoError(t, r.Post("/echo", echoHandler, wrouter.DecompressRequestBody(), wrouter.MetricTags(tags)))
+	require.NoError(t, r.Post("/limited", echoHandler, wrouter.DecompressRequestBody(), wrouter.RouteMaxBodySize(100)))
+	require.NoError(t, r.Post("/default", echoHandler))
+	require.NoError(t, r.Post("/unlimited", echoHandler, wrouter.DecompressRequestBody()))
+
+	gzipBody := func(body string) []byte {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, _ = w.Write([]byte(body))
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+	deflateBody := func(body string) []byte {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		_, _ = w.Write([]byte(body))
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+	doRequest := func(path, contentEncoding string, body []byte) *httptest.ResponseRecorder {
+		return routertest.ServeRequest(r, http.MethodPost, path, http.Header{"Content-Encoding": {contentEncoding}}, bytes.NewReader(body))
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		rw := doRequest("/echo", "gzip", gzipBody("hello"))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "hello", rw.Body.String())
+		assert.Empty(t, rw.Header().Get("X-Content-Encoding"))
+	})
+
+	t.Run("deflate", func(t *testing.T) {
+		rw := doRequest("/echo", "deflate", deflateBody("hello"))
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "hello", rw.Body.String())
+	})
+
+	t.Run("unsupported encoding", func(t *testing.T) {
+		rw := doRequest("/echo", "br", []byte("hello"))
+		assert.Equal(t, "hello", rw.Body.String())
+		assert.Equal(t, "br", rw.Header().Get("X-Content-Encoding"))
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		rw := doRequest("/echo", "gzip", []byte("hello"))
+		assert.Equal(t, http.StatusBadRequest, rw.Code)
+		var serializableErr errors.SerializableError
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &serializableErr))
+		assert.Equal(t, errors.DefaultInvalidArgument.Name(), serializableErr.ErrorName)
+	})
+
+	t.Run("decompressed body exceeds limit", func(t *testing.T) {
+		body := gzipBody(strings.Repeat("a", 1000))
+		require.Less(t, len(body), 100)
+		rw := doRequest("/limited", "gzip", body)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+	})
+
+	t.Run("decompressed body exceeds maximum decompressed size", func(t *testing.T) {
+		body := gzipBody(strings.Repeat("a", 1000))
+		rw := doRequest("/unlimited", "gzip", body)
+		assert.Equal(t, http.StatusOK, rw.Code, "default maximum decompressed size should apply")
+
+		tooLarge := registry.Meter("server.request.tooLarge")
+		tooLargeCount := tooLarge.Count()
+		require.NoError(t, maxSize.Update(int64(500)))
+		defer func() {
+			require.NoError(t, maxSize.Update(int64(0)))
+		}()
+		rw = doRequest("/unlimited", "gzip", body)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+		var serializableErr errors.SerializableError
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &serializableErr))
+		assert.Equal(t, "Witchcraft:RequestEntityTooLarge", serializableErr.ErrorName)
+		assert.Equal(t, tooLargeCount+1, tooLarge.Count())
+	})
+
+	t.Run("not enabled", func(t *testing.T) {
+		body := gzipBody("hello")
+		rw := doRequest("/default", "gzip", body)
+		assert.Equal(t, body, rw.Body.Bytes())
+		assert.Equal(t, "gzip", rw.Header().Get("X-Content-Encoding"))
+
+		require.NoError(t, enabled.Update(true))
+		defer func() {
+			require.NoError(t, enabled.Update(false))
+		}()
+		rw = doRequest("/default", "gzip", body)
+		assert.Equal(t, "hello", rw.Body.String())
+	})
+
+	uncompressedSize := registry.Histogram("server.request.size.uncompressed", tags...)
+	assert.Equal(t, int64(2), uncompressedSize.Count())
+	assert.Equal(t, int64(len("hello")), uncompressedSize.Max())
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+	wparams "github.com/palantir/witchcraft-go-params"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+const (
+	serverRequestDeprecatedMetricName = "server.request.deprecated"
+	userAgentTagName                  = "useragent"
+
+	// deprecationLogInterval is the minimum interval between the warnings logged for calls by the same caller to the
+	// same deprecated route.
+	deprecationLogInterval = 10 * time.Minute
+	// maxDeprecationLogCallers is the maximum number of callers for which the time of the last warning is tracked.
+	maxDeprecationLogCallers = 1000
+	// maxUserAgentTagValues is the maximum number of distinct User-Agent product names used as the value of the
+	// useragent tag. Requests from other products are tagged with otherUserAgentTagValue.
+	maxUserAgentTagValues  = 100
+	otherUserAgentTagValue = "other"
+)
+
+// endpointSunsetErrorType is the type of the error returned for requests to deprecated routes whose sunset has passed.
+var endpointSunsetErrorType = NewStatusErrorType("Witchcraft:EndpointSunset", http.StatusGone)
+
+// NewRouteDeprecation returns a middleware for routes marked as deprecated using wrouter.RouteDeprecated. The middleware
+// sets the Deprecation, Sunset and Link headers on responses, marks the deprecated request meter for the route tagged
+// with the product name of the caller's User-Agent (the first 100 distinct product names are used as tag values, and
+// requests from other products are tagged "other"), and logs a warning at most once per caller and route every 10
+// minutes. If the route rejects requests after its sunset and the sunset has passed, the middleware responds with a 410
+// error instead of invoking the handler.
+func NewRouteDeprecation(mr metrics.RootRegistry) wrouter.RouteHandlerMiddleware {
+	limiter := &deprecationLogLimiter{
+		lastLogged: make(map[deprecationLogKey]time.Time),
+	}
+	userAgentTags := &userAgentTagValues{
+		values: make(map[string]struct{}),
+	}
+	return func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+		deprecation := reqVals.Deprecation
+		if deprecation == nil {
+			next(rw, req, reqVals)
+			return
+		}
+
+		header := rw.Header()
+		if deprecation.Date.IsZero() {
+			header.Set("Deprecation", "true")
+		} else {
+			header.Set("Deprecation", "@"+strconv.FormatInt(deprecation.Date.Unix(), 10))
+		}
+		if !deprecation.Sunset.IsZero() {
+			header.Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if deprecation.Link != "" {
+			header.Add("Link", "<"+deprecation.Link+`>; rel="deprecation"`)
+		}
+
+		userAgent := req.Header.Get("User-Agent")
+		if !reqVals.DisableTelemetry {
+			tags := append(append(metrics.Tags{}, reqVals.MetricTags...),
+				metrics.NewTagWithFallbackValue(userAgentTagName, userAgentTags.value(userAgentProduct(userAgent)), "unknown"))
+			mr.Meter(serverRequestDeprecatedMetricName, tags...).Mark(1)
+			if limiter.allow(deprecationLogKey{spec: reqVals.Spec, userAgent: userAgent}) {
+				params := map[string]interface{}{
+					"method":       reqVals.Spec.Method,
+					"pathTemplate": reqVals.Spec.PathTemplate,
+					"userAgent":    userAgent,
+				}
+				if !deprecation.Sunset.IsZero() {
+					params["sunset"] = deprecation.Sunset.UTC().Format(time.RFC3339)
+				}
+				svc1log.FromContext(req.Context()).Warn("Received request for deprecated route", svc1log.SafeParams(params))
+			}
+		}
+
+		if deprecation.RejectAfterSunset && deprecation.IsSunset(time.Now()) {
+			endpointSunsetErrorType.Write(req.Context(), rw, nil,
+				wparams.NewSafeParam("sunset", deprecation.Sunset.UTC().Format(time.RFC3339)))
+			return
+		}
+		next(rw, req, reqVals)
+	}
+}
+
+// userAgentProduct returns the product name of the first product in the provided User-Agent, which identifies the
+// caller without the version.
+func userAgentProduct(userAgent string) string {
+	product, _, _ := strings.Cut(strings.TrimSpace(userAgent), " ")
+	product, _, _ = strings.Cut(product, "/")
+	return product
+}
+
+// userAgentTagValues bounds the cardinality of the useragent tag. Callers control their User-Agent, so the product names
+// used as tag values are limited to the first maxUserAgentTagValues distinct ones that are seen.
+type userAgentTagValues struct {
+	mu     sync.Mutex
+	values map[string]struct{}
+}
+
+// value returns the tag value for the provided product name: the name itself if it is one of the first
+// maxUserAgentTagValues distinct names, and otherUserAgentTagValue otherwise. Empty names are returned unchanged.
+func (v *userAgentTagValues) value(product string) string {
+	if product == "" {
+		return product
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.values[product]; ok {
+		return product
+	}
+	if len(v.values) >= maxUserAgentTagValues {
+		return otherUserAgentTagValue
+	}
+	v.values[product] = struct{}{}
+	return product
+}
+
+type deprecationLogKey struct {
+	spec      wrouter.RouteSpec
+	userAgent string
+}
+
+// deprecationLogLimiter limits the warnings logged for calls to deprecated routes to one per caller and route every
+// deprecationLogInterval.
+type deprecationLogLimiter struct {
+	mu         sync.Mutex
+	lastLogged map[deprecationLogKey]time.Time
+}
+
+func (l *deprecationLogLimiter) allow(key deprecationLogKey) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if last, ok := l.lastLogged[key]; ok && now.Sub(last) < deprecationLogInterval {
+		return false
+	}
+	if len(l.lastLogged) >= maxDeprecationLogCallers {
+		for k, last := range l.lastLogged {
+			if now.Sub(last) >= deprecationLogInterval {
+				delete(l.lastLogged, k)
+			}
+		}
+		if len(l.lastLogged) >= maxDeprecationLogCallers {
+			// all tracked callers were logged recently, so stop tracking them rather than growing without bound
+			l.lastLogged = make(map[deprecationLogKey]time.Time)
+		}
+	}
+	l.lastLogged[key] = now
+	return true
+}
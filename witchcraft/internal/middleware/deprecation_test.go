@@ -0,0 +1,157 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/errors"
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/witchcraft-go-logging/wlog"
+	wlogzap "github.com/palantir/witchcraft-go-logging/wlog-zap"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/routertest"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteDeprecation(t *testing.T) {
+	var svcOutput bytes.Buffer
+	svcLog := svc1log.NewFromCreator(&svcOutput, wlog.InfoLevel, wlogzap.LoggerProvider().NewLeveledLogger)
+	registry := metrics.NewRootMetricsRegistry()
+	r := wrouter.New(
+		whttprouter.New(),
+		wrouter.RootRouterParamAddRequestHandlerMiddleware(
+			middleware.NewRequestContextLoggers(svcLog, nil, nil, nil, nil, nil),
+		),
+		wrouter.RootRouterParamAddRouteHandlerMiddleware(
+			middleware.NewRouteDeprecation(registry),
+		),
+	)
+
+	deprecationDate := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pastSunset := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	futureSunset := time.Now().Add(24 * time.Hour)
+	var handlerCalls int
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		handlerCalls++
+		rw.WriteHeader(http.StatusOK)
+	})
+	tags := metrics.MustNewTags(map[string]string{"endpoint": "old"})
+	require.NoError(t, r.Get("/old", handler, wrouter.MetricTags(tags), wrouter.RouteDeprecated(wrouter.RouteDeprecation{
+		Date:              deprecationDate,
+		Sunset:            futureSunset,
+		RejectAfterSunset: true,
+		Link:              "https://example.com/docs",
+	})))
+	require.NoError(t, r.Get("/undated", handler, wrouter.RouteDeprecated(wrouter.RouteDeprecation{})))
+	require.NoError(t, r.Get("/sunset", handler, wrouter.RouteDeprecated(wrouter.RouteDeprecation{
+		Sunset:            pastSunset,
+		RejectAfterSunset: true,
+	})))
+	require.NoError(t, r.Get("/sunset-allowed", handler, wrouter.RouteDeprecated(wrouter.RouteDeprecation{
+		Sunset: pastSunset,
+	})))
+	require.NoError(t, r.Get("/current", handler))
+
+	doRequest := func(path, userAgent string) *httptest.ResponseRecorder {
+		return routertest.ServeRequest(r, http.MethodGet, path, http.Header{"User-Agent": {userAgent}}, nil)
+	}
+
+	t.Run("deprecated", func(t *testing.T) {
+		rw := doRequest("/old", "caller/1.2.3 (go)")
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "@1767225600", rw.Header().Get("Deprecation"))
+		assert.Equal(t, futureSunset.UTC().Format(http.TimeFormat), rw.Header().Get("Sunset"))
+		assert.Equal(t, `<https://example.com/docs>; rel="deprecation"`, rw.Header().Get("Link"))
+	})
+
+	t.Run("deprecated without date", func(t *testing.T) {
+		rw := doRequest("/undated", "caller/1.2.3")
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "true", rw.Header().Get("Deprecation"))
+		assert.Empty(t, rw.Header().Get("Sunset"))
+		assert.Empty(t, rw.Header().Get("Link"))
+	})
+
+	t.Run("not deprecated", func(t *testing.T) {
+		rw := doRequest("/current", "caller/1.2.3")
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Empty(t, rw.Header().Get("Deprecation"))
+	})
+
+	t.Run("rejected after sunset", func(t *testing.T) {
+		handlerCalls = 0
+		rw := doRequest("/sunset", "caller/1.2.3")
+		assert.Equal(t, http.StatusGone, rw.Code)
+		assert.Equal(t, pastSunset.Format(http.TimeFormat), rw.Header().Get("Sunset"))
+		var serializableErr errors.SerializableError
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &serializableErr))
+		assert.Equal(t, "Witchcraft:EndpointSunset", serializableErr.ErrorName)
+		assert.Equal(t, 0, handlerCalls, "handler should not be invoked")
+	})
+
+	t.Run("allowed after sunset", func(t *testing.T) {
+		rw := doRequest("/sunset-allowed", "caller/1.2.3")
+		assert.Equal(t, http.StatusOK, rw.Code)
+	})
+
+	t.Run("metered and logged per caller", func(t *testing.T) {
+		svcOutput.Reset()
+		doRequest("/old", "other-caller/2.0.0")
+		doRequest("/old", "other-caller/2.0.1")
+		doRequest("/old", "other-caller/2.0.1")
+
+		callerTags := append(append(metrics.Tags{}, tags...), metrics.MustNewTag("useragent", "other-caller"))
+		assert.Equal(t, int64(3), registry.Meter("server.request.deprecated", callerTags...).Count())
+		callerTags = append(append(metrics.Tags{}, tags...), metrics.MustNewTag("useragent", "caller"))
+		assert.Equal(t, int64(1), registry.Meter("server.request.deprecated", callerTags...).Count())
+
+		lines := strings.Split(strings.TrimSpace(svcOutput.String()), "\n")
+		require.Len(t, lines, 2, "expected one warning per distinct caller")
+		for i, userAgent := range []string{"other-caller/2.0.0", "other-caller/2.0.1"} {
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(lines[i]), &entry))
+			assert.Equal(t, "WARN", entry["level"])
+			params := entry["params"].(map[string]interface{})
+			assert.Equal(t, userAgent, params["userAgent"])
+			assert.Equal(t, "/old", params["pathTemplate"])
+		}
+	})
+
+	t.Run("bounded useragent tag values", func(t *testing.T) {
+		// "caller" and "other-caller" were already seen by the previous subtests
+		for i := 0; i < 120; i++ {
+			doRequest("/old", fmt.Sprintf("caller-%d/1.0.0", i))
+		}
+
+		callerTags := append(append(metrics.Tags{}, tags...), metrics.MustNewTag("useragent", "caller-97"))
+		assert.Equal(t, int64(1), registry.Meter("server.request.deprecated", callerTags...).Count())
+		callerTags = append(append(metrics.Tags{}, tags...), metrics.MustNewTag("useragent", "caller-98"))
+		assert.Equal(t, int64(0), registry.Meter("server.request.deprecated", callerTags...).Count())
+		otherTags := append(append(metrics.Tags{}, tags...), metrics.MustNewTag("useragent", "other"))
+		assert.Equal(t, int64(22), registry.Meter("server.request.deprecated", otherTags...).Count())
+	})
+}
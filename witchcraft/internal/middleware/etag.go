@@ -0,0 +1,271 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-server/httpserver"
+	wparams "github.com/palantir/witchcraft-go-params"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+// maxETagResponseSize is the maximum size in bytes of the response bodies that are buffered to compute their entity tag.
+const maxETagResponseSize = 1 << 20
+
+// preconditionFailedErrorType is the type of the error returned for requests whose If-Match or If-None-Match
+// precondition fails.
+var preconditionFailedErrorType = NewStatusErrorType("Witchcraft:PreconditionFailed", http.StatusPreconditionFailed)
+
+// NewRouteETag returns a middleware that implements ETags and conditional requests for routes registered with
+// RouteETag or RouteCurrentETag. For routes with a current entity tag function, the If-Match and If-None-Match
+// preconditions of requests are evaluated before the handler is invoked. Otherwise, successful responses to GET
+// requests are buffered so that a weak entity tag can be computed over their body if the handler does not set one, and
+// the response is replaced by a 304 (Not Modified) response if it matches the If-None-Match header. Responses whose body
+// exceeds 1 MiB are written without an entity tag once the limit is reached. HEAD requests are passed to the handler as
+// is, since the entity tag of a response without a body cannot be computed.
+func NewRouteETag() wrouter.RouteHandlerMiddleware {
+	return func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+		if !reqVals.ETag {
+			next(rw, req, reqVals)
+			return
+		}
+		isGet := req.Method == http.MethodGet || req.Method == http.MethodHead
+
+		if reqVals.CurrentETag != nil {
+			etag, err := reqVals.CurrentETag(req)
+			if err != nil {
+				httpserver.NewJSONHandler(func(http.ResponseWriter, *http.Request) error {
+					return err
+				}, httpserver.StatusCodeMapper, httpserver.ErrHandler).ServeHTTP(rw, req)
+				return
+			}
+			etag = quoteETag(etag)
+			if ifMatch := req.Header.Get("If-Match"); ifMatch != "" && !etagListMatches(ifMatch, etag, false) {
+				writePreconditionFailed(rw, req, "If-Match", etag)
+				return
+			}
+			if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" && etagListMatches(ifNoneMatch, etag, true) {
+				if isGet {
+					rw.Header().Set("ETag", etag)
+					rw.WriteHeader(http.StatusNotModified)
+					return
+				}
+				writePreconditionFailed(rw, req, "If-None-Match", etag)
+				return
+			}
+			if isGet && etag != "" {
+				rw.Header().Set("ETag", etag)
+			}
+			next(rw, req, reqVals)
+			return
+		}
+
+		if req.Method != http.MethodGet {
+			next(rw, req, reqVals)
+			return
+		}
+		erw := &etagResponseWriter{
+			ResponseWriter: rw,
+			ifNoneMatch:    req.Header.Get("If-None-Match"),
+			maxSize:        maxETagResponseSize,
+		}
+		next(erw, req, reqVals)
+		erw.close()
+	}
+}
+
+func writePreconditionFailed(rw http.ResponseWriter, req *http.Request, header, etag string) {
+	preconditionFailedErrorType.Write(req.Context(), rw, nil,
+		wparams.NewSafeParam("precondition", header),
+		wparams.NewSafeParam("etag", etag),
+	)
+}
+
+// quoteETag returns the provided entity tag quoted if it is not empty and not already quoted.
+func quoteETag(etag string) string {
+	if etag == "" || strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// etagListMatches returns true if the provided value of an If-Match or If-None-Match header is "*" or contains the
+// provided entity tag. Entity tags are compared using the weak comparison if weak is true and the strong comparison
+// otherwise (see RFC 9110 section 8.8.3.2). Returns false if the entity tag is empty.
+func etagListMatches(list, etag string, weak bool) bool {
+	if etag == "" {
+		return false
+	}
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			if strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		} else if candidate == etag && !strings.HasPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+type etagResponseWriterState int
+
+const (
+	// etagStatePending is the state before the status code of the response has been written.
+	etagStatePending etagResponseWriterState = iota
+	// etagStateBuffering is the state in which the body of a successful response without an entity tag is buffered.
+	etagStateBuffering
+	// etagStateNotModified is the state in which the body of a response is discarded because a 304 response was
+	// written in its place.
+	etagStateNotModified
+	// etagStatePassthrough is the state in which the response is written to the wrapped writer as is.
+	etagStatePassthrough
+)
+
+// etagResponseWriter buffers a successful response without an entity tag, up to a maximum body size, to compute its
+// entity tag and replaces responses whose entity tag matches the If-None-Match header of the request with a 304
+// response.
+type etagResponseWriter struct {
+	http.ResponseWriter
+	ifNoneMatch string
+	maxSize     int64
+
+	state    etagResponseWriterState
+	buf      bytes.Buffer
+	hijacked bool
+}
+
+func (w *etagResponseWriter) WriteHeader(status int) {
+	if w.state != etagStatePending || status < http.StatusOK {
+		// let the wrapped writer handle superfluous calls and informational responses
+		if w.state != etagStateNotModified {
+			w.ResponseWriter.WriteHeader(status)
+		}
+		return
+	}
+	switch etag := w.Header().Get("ETag"); {
+	case status != http.StatusOK:
+		w.state = etagStatePassthrough
+		w.ResponseWriter.WriteHeader(status)
+	case etag != "":
+		w.writeHeaderWithETag(etag)
+	default:
+		w.state = etagStateBuffering
+	}
+}
+
+func (w *etagResponseWriter) Write(p []byte) (int, error) {
+	if w.state == etagStatePending {
+		w.WriteHeader(http.StatusOK)
+	}
+	switch w.state {
+	case etagStateBuffering:
+		if int64(w.buf.Len()+len(p)) <= w.maxSize {
+			return w.buf.Write(p)
+		}
+		// the response is too large to be buffered: write it without an entity tag
+		if err := w.writeBuffered(); err != nil {
+			return 0, err
+		}
+		return w.ResponseWriter.Write(p)
+	case etagStateNotModified:
+		return len(p), nil
+	default:
+		return w.ResponseWriter.Write(p)
+	}
+}
+
+// Flush writes the buffered response without an entity tag, since a response that is flushed is streamed to the
+// client before its entity tag is known.
+func (w *etagResponseWriter) Flush() {
+	if w.state == etagStatePending {
+		w.WriteHeader(http.StatusOK)
+	}
+	switch w.state {
+	case etagStateNotModified:
+		return
+	case etagStateBuffering:
+		if err := w.writeBuffered(); err != nil {
+			return
+		}
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *etagResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("the ResponseWriter doesn't support the Hijacker interface")
+	}
+	w.hijacked = true
+	return hijacker.Hijack()
+}
+
+// writeBuffered writes the status code and the buffered body of a response without an entity tag and passes the rest of
+// the response through.
+func (w *etagResponseWriter) writeBuffered() error {
+	w.state = etagStatePassthrough
+	w.ResponseWriter.WriteHeader(http.StatusOK)
+	buf := w.buf.Bytes()
+	w.buf = bytes.Buffer{}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// writeHeaderWithETag writes a 304 response if the provided entity tag of a successful response matches the
+// If-None-Match header and writes the status code of the successful response otherwise.
+func (w *etagResponseWriter) writeHeaderWithETag(etag string) {
+	if w.ifNoneMatch == "" || !etagListMatches(w.ifNoneMatch, etag, true) {
+		w.state = etagStatePassthrough
+		w.ResponseWriter.WriteHeader(http.StatusOK)
+		return
+	}
+	w.state = etagStateNotModified
+	// a 304 response does not have a body, so it must not describe one
+	w.Header().Del("Content-Length")
+	w.Header().Del("Content-Type")
+	w.ResponseWriter.WriteHeader(http.StatusNotModified)
+}
+
+// close computes the entity tag of a buffered response and writes the response.
+func (w *etagResponseWriter) close() {
+	if w.hijacked || w.state != etagStateBuffering {
+		return
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		sum := sha256.Sum256(w.buf.Bytes())
+		etag = `W/"` + hex.EncodeToString(sum[:16]) + `"`
+		w.Header().Set("ETag", etag)
+	}
+	w.writeHeaderWithETag(etag)
+	if w.state == etagStatePassthrough {
+		_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+}
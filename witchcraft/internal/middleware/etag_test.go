@@ -0,0 +1,168 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/errors"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/routertest"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteETag(t *testing.T) {
+	r := wrouter.New(whttprouter.New())
+	r.AddRouteHandlerMiddleware(middleware.NewRouteETag())
+
+	var handlerCalls int
+	bodyHandler := func(body string) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			handlerCalls++
+			rw.Header().Set("Content-Type", "application/json")
+			_, _ = rw.Write([]byte(body))
+		})
+	}
+	require.NoError(t, r.Get("/computed", bodyHandler(`{"value":1}`), wrouter.RouteETag()))
+	require.NoError(t, r.Head("/computed", bodyHandler(""), wrouter.RouteETag()))
+	require.NoError(t, r.Get("/disabled", bodyHandler(`{"value":1}`)))
+	require.NoError(t, r.Get("/handler", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("ETag", `"v1"`)
+		_, _ = rw.Write([]byte("handler"))
+	}), wrouter.RouteETag()))
+	require.NoError(t, r.Get("/error", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		http.Error(rw, "not found", http.StatusNotFound)
+	}), wrouter.RouteETag()))
+	require.NoError(t, r.Get("/stream", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte("first"))
+		rw.(http.Flusher).Flush()
+		_, _ = rw.Write([]byte("second"))
+	}), wrouter.RouteETag()))
+	largeBody := strings.Repeat("a", 1<<20)
+	require.NoError(t, r.Get("/large", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(largeBody[:1024]))
+		_, _ = rw.Write([]byte(largeBody[1024:]))
+		_, _ = rw.Write([]byte("b"))
+	}), wrouter.RouteETag()))
+
+	currentETag := "v1"
+	currentETagFn := func(req *http.Request) (string, error) {
+		if currentETag == "error" {
+			return "", errors.NewNotFound()
+		}
+		return currentETag, nil
+	}
+	require.NoError(t, r.Get("/resource", bodyHandler("resource"), wrouter.RouteCurrentETag(currentETagFn)))
+	require.NoError(t, r.Put("/resource", bodyHandler("updated"), wrouter.RouteCurrentETag(currentETagFn)))
+
+	ifNoneMatch := func(etag string) http.Header {
+		return http.Header{"If-None-Match": []string{etag}}
+	}
+	ifMatch := func(etag string) http.Header {
+		return http.Header{"If-Match": []string{etag}}
+	}
+	assertPreconditionFailed := func(t *testing.T, rw *httptest.ResponseRecorder) {
+		assert.Equal(t, http.StatusPreconditionFailed, rw.Code)
+		var serializableErr errors.SerializableError
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &serializableErr))
+		assert.Equal(t, "Witchcraft:PreconditionFailed", serializableErr.ErrorName)
+	}
+
+	t.Run("computed ETag", func(t *testing.T) {
+		rw := routertest.ServeRequest(r, http.MethodGet, "/computed", nil, nil)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, `{"value":1}`, rw.Body.String())
+		etag := rw.Header().Get("ETag")
+		assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+
+		rw = routertest.ServeRequest(r, http.MethodGet, "/computed", ifNoneMatch(`"other", `+etag), nil)
+		assert.Equal(t, http.StatusNotModified, rw.Code)
+		assert.Empty(t, rw.Body.String())
+		assert.Equal(t, etag, rw.Header().Get("ETag"))
+		assert.Empty(t, rw.Header().Get("Content-Type"))
+
+		rw = routertest.ServeRequest(r, http.MethodGet, "/computed", ifNoneMatch(`"other"`), nil)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, `{"value":1}`, rw.Body.String())
+	})
+
+	t.Run("no computed ETag for HEAD", func(t *testing.T) {
+		rw := routertest.ServeRequest(r, http.MethodHead, "/computed", ifNoneMatch("*"), nil)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Empty(t, rw.Header().Get("ETag"))
+	})
+
+	t.Run("handler ETag", func(t *testing.T) {
+		rw := routertest.ServeRequest(r, http.MethodGet, "/handler", ifNoneMatch(`W/"v1"`), nil)
+		assert.Equal(t, http.StatusNotModified, rw.Code)
+		assert.Empty(t, rw.Body.String())
+
+		rw = routertest.ServeRequest(r, http.MethodGet, "/handler", nil, nil)
+		assert.Equal(t, `"v1"`, rw.Header().Get("ETag"))
+		assert.Equal(t, "handler", rw.Body.String())
+	})
+
+	t.Run("no ETag", func(t *testing.T) {
+		for _, path := range []string{"/disabled", "/error", "/stream", "/large"} {
+			rw := routertest.ServeRequest(r, http.MethodGet, path, ifNoneMatch("*"), nil)
+			assert.NotEqual(t, http.StatusNotModified, rw.Code, path)
+			assert.Empty(t, rw.Header().Get("ETag"), path)
+		}
+		assert.Equal(t, "firstsecond", routertest.ServeRequest(r, http.MethodGet, "/stream", nil, nil).Body.String())
+		assert.Equal(t, largeBody+"b", routertest.ServeRequest(r, http.MethodGet, "/large", nil, nil).Body.String())
+	})
+
+	t.Run("current ETag", func(t *testing.T) {
+		rw := routertest.ServeRequest(r, http.MethodGet, "/resource", nil, nil)
+		assert.Equal(t, `"v1"`, rw.Header().Get("ETag"))
+		assert.Equal(t, "resource", rw.Body.String())
+
+		handlerCalls = 0
+		rw = routertest.ServeRequest(r, http.MethodGet, "/resource", ifNoneMatch(`"v1"`), nil)
+		assert.Equal(t, http.StatusNotModified, rw.Code)
+		assert.Equal(t, `"v1"`, rw.Header().Get("ETag"))
+		assert.Equal(t, 0, handlerCalls, "handler should not be invoked")
+	})
+
+	t.Run("if match", func(t *testing.T) {
+		rw := routertest.ServeRequest(r, http.MethodPut, "/resource", ifMatch(`"v1"`), nil)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "updated", rw.Body.String())
+		assert.Empty(t, rw.Header().Get("ETag"))
+
+		handlerCalls = 0
+		assertPreconditionFailed(t, routertest.ServeRequest(r, http.MethodPut, "/resource", ifMatch(`"v0"`), nil))
+		assertPreconditionFailed(t, routertest.ServeRequest(r, http.MethodPut, "/resource", ifMatch(`W/"v1"`), nil))
+		assertPreconditionFailed(t, routertest.ServeRequest(r, http.MethodPut, "/resource", ifNoneMatch("*"), nil))
+		assert.Equal(t, 0, handlerCalls, "handler should not be invoked")
+
+		currentETag = ""
+		assertPreconditionFailed(t, routertest.ServeRequest(r, http.MethodPut, "/resource", ifMatch("*"), nil))
+		assert.Equal(t, http.StatusOK, routertest.ServeRequest(r, http.MethodPut, "/resource", ifNoneMatch("*"), nil).Code)
+	})
+
+	t.Run("current ETag error", func(t *testing.T) {
+		currentETag = "error"
+		rw := routertest.ServeRequest(r, http.MethodPut, "/resource", nil, nil)
+		assert.Equal(t, http.StatusNotFound, rw.Code)
+	})
+}
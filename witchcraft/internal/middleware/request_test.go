@@ -34,6 +34,7 @@ import (
 	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
 	"github.com/palantir/witchcraft-go-logging/wlog/trclog/trc1log"
 	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/requestlog"
 	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wresource"
 	"github.com/palantir/witchcraft-go-server/v2/wrouter"
 	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
@@ -347,3 +348,99 @@ func TestRequestDisableTelemetry(t *testing.T) {
 	assert.Empty(t, reqOutput.Bytes(), "expected request log to be empty when DisableTelemetry is true")
 	assert.Empty(t, spanOutput.Bytes(), "expected trace span log to be empty when DisableTelemetry is true")
 }
+
+func TestRouteRequestLogHostRoute(t *testing.T) {
+	var reqOutput bytes.Buffer
+	reqLog := requestlog.NewLogger(&reqOutput, req2log.Creator(wlogzap.LoggerProvider().NewLogger))
+	r := wrouter.New(whttprouter.New())
+	r.AddRequestHandlerMiddleware(func(rw http.ResponseWriter, req *http.Request, next http.Handler) {
+		next.ServeHTTP(rw, req.WithContext(req2log.WithLogger(req.Context(), reqLog)))
+	})
+	r.AddRouteHandlerMiddleware(middleware.NewRouteRequestLog())
+	require.NoError(t, r.Get("/items/{id}", http.NotFoundHandler(), wrouter.MatchHost("*.example.com"), wrouter.SafePathParams("id")))
+
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Host = "api.example.com"
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var reqLogEntry map[string]interface{}
+	require.NoError(t, json.Unmarshal(reqOutput.Bytes(), &reqLogEntry))
+	assert.Equal(t, map[string]interface{}{"id": "1", "hostRoute": "*.example.com"}, reqLogEntry["params"])
+}
+
+func TestRouteRequestLogHandlerParams(t *testing.T) {
+	var reqOutput bytes.Buffer
+	reqLog := requestlog.NewLogger(&reqOutput, req2log.Creator(wlogzap.LoggerProvider().NewLogger))
+	r := wrouter.New(whttprouter.New())
+	r.AddRequestHandlerMiddleware(func(rw http.ResponseWriter, req *http.Request, next http.Handler) {
+		next.ServeHTTP(rw, req.WithContext(req2log.WithLogger(req.Context(), reqLog)))
+	})
+	r.AddRouteHandlerMiddleware(middleware.NewRouteRequestLog())
+	require.NoError(t, r.Get("/items/{id}", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requestlog.SetParam(req.Context(), "upstreamService", "items")
+		rw.WriteHeader(http.StatusNoContent)
+	})))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/1", nil))
+
+	var reqLogEntry map[string]interface{}
+	require.NoError(t, json.Unmarshal(reqOutput.Bytes(), &reqLogEntry))
+	assert.Equal(t, map[string]interface{}{"upstreamService": "items"}, reqLogEntry["params"])
+	assert.Equal(t, map[string]interface{}{"id": "1"}, reqLogEntry["unsafeParams"])
+}
+
+func TestRouteRequestLogHandlerParamsCannotOverridePathParams(t *testing.T) {
+	var reqOutput bytes.Buffer
+	reqLog := requestlog.NewLogger(&reqOutput, req2log.Creator(wlogzap.LoggerProvider().NewLogger))
+	r := wrouter.New(whttprouter.New())
+	r.AddRequestHandlerMiddleware(func(rw http.ResponseWriter, req *http.Request, next http.Handler) {
+		next.ServeHTTP(rw, req.WithContext(req2log.WithLogger(req.Context(), reqLog)))
+	})
+	r.AddRouteHandlerMiddleware(middleware.NewRouteRequestLog())
+	require.NoError(t, r.Get("/accounts/{token}/items/{id}", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requestlog.SetParam(req.Context(), "Token", "leaked")
+		requestlog.SetParam(req.Context(), "id", "replaced")
+		requestlog.SetParam(req.Context(), "upstreamService", "items")
+		rw.WriteHeader(http.StatusNoContent)
+	}), wrouter.ForbiddenPathParams("token")))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/accounts/secret/items/1", nil))
+
+	var reqLogEntry map[string]interface{}
+	require.NoError(t, json.Unmarshal(reqOutput.Bytes(), &reqLogEntry))
+	assert.Equal(t, map[string]interface{}{"upstreamService": "items"}, reqLogEntry["params"])
+	assert.Equal(t, map[string]interface{}{"id": "1"}, reqLogEntry["unsafeParams"])
+	assert.NotContains(t, reqOutput.String(), "secret")
+	assert.NotContains(t, reqOutput.String(), "leaked")
+}
+
+func TestRouteRequestLogHandlerParamsWrapped(t *testing.T) {
+	var reqOutput bytes.Buffer
+	reqLog := requestlog.NewWrappedLogger(&reqOutput, "my-product", "1.0.0", req2log.Creator(wlogzap.LoggerProvider().NewLogger))
+	r := wrouter.New(whttprouter.New())
+	r.AddRequestHandlerMiddleware(func(rw http.ResponseWriter, req *http.Request, next http.Handler) {
+		next.ServeHTTP(rw, req.WithContext(req2log.WithLogger(req.Context(), reqLog)))
+	})
+	r.AddRouteHandlerMiddleware(middleware.NewRouteRequestLog())
+	require.NoError(t, r.Get("/items/{id}", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requestlog.SetParam(req.Context(), "upstreamService", "items")
+		rw.WriteHeader(http.StatusNoContent)
+	}), wrouter.MatchHost("*.example.com"), wrouter.SafePathParams("id")))
+
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Host = "api.example.com"
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var reqLogEntry map[string]interface{}
+	require.NoError(t, json.Unmarshal(reqOutput.Bytes(), &reqLogEntry))
+	assert.Equal(t, "wrapped.1", reqLogEntry["type"])
+	assert.Equal(t, "my-product", reqLogEntry["entityName"])
+	assert.Equal(t, "1.0.0", reqLogEntry["entityVersion"])
+	payload, ok := reqLogEntry["payload"].(map[string]interface{})
+	require.True(t, ok, "expected payload to be an object: %v", reqLogEntry["payload"])
+	assert.Equal(t, "requestLogV2", payload["type"])
+	requestLogV2, ok := payload["requestLogV2"].(map[string]interface{})
+	require.True(t, ok, "expected requestLogV2 to be an object: %v", payload["requestLogV2"])
+	assert.Equal(t, "request.2", requestLogV2["type"])
+	assert.Equal(t, map[string]interface{}{"id": "1", "hostRoute": "*.example.com", "upstreamService": "items"}, requestLogV2["params"])
+}
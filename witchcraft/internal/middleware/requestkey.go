@@ -0,0 +1,45 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// requestIdentityHeaders are the headers of a request that identify its caller. Responses recorded for a request are
+// only shared with requests that have the same values of these headers unless the route serves the same response to
+// all callers.
+var requestIdentityHeaders = []string{"Authorization", "Cookie"}
+
+// writeKeyParts writes the provided parts of a key to the provided writer, prefixing each part with its length so that
+// the boundaries between parts are unambiguous.
+func writeKeyParts(w io.Writer, parts ...string) {
+	for _, part := range parts {
+		_, _ = fmt.Fprintf(w, "%d:%s", len(part), part)
+	}
+}
+
+// writeRequestIdentity writes the values of the headers of the provided request that identify its caller to the
+// provided writer.
+func writeRequestIdentity(w io.Writer, req *http.Request) {
+	for _, header := range requestIdentityHeaders {
+		values := req.Header.Values(header)
+		writeKeyParts(w, header, strconv.Itoa(len(values)))
+		writeKeyParts(w, values...)
+	}
+}
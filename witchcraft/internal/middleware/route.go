@@ -26,6 +26,7 @@ import (
 	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
 	"github.com/palantir/witchcraft-go-logging/wlog/wapp"
 	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/negroni"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/requestlog"
 	"github.com/palantir/witchcraft-go-server/v2/wrouter"
 	"github.com/palantir/witchcraft-go-tracing/wtracing"
 	"github.com/palantir/witchcraft-go-tracing/wtracing/propagation/b3"
@@ -39,10 +40,22 @@ func NewRouteRequestLog() wrouter.RouteHandlerMiddleware {
 		}
 
 		lrw := toLoggingResponseWriter(rw)
+		ctx, timeout := contextWithTimeoutState(req.Context())
+		ctx = requestlog.ContextWithParams(ctx)
+		req = req.WithContext(ctx)
 		start := time.Now()
 		next(lrw, req, reqVals)
 		duration := time.Since(start)
 
+		// parameters set by the server are set after the handler has returned so that they override the parameters
+		// set by the handler.
+		if reqVals.Conditions.Host != "" {
+			requestlog.SetParam(ctx, hostRouteParamName, reqVals.Conditions.Host)
+		}
+		if timeout.timedOut.Load() {
+			requestlog.SetParam(ctx, timedOutParamName, "true")
+		}
+
 		req2log.FromContext(req.Context()).Request(req2log.Request{
 			Request: req,
 			RouteInfo: req2log.RouteInfo{
@@ -59,6 +72,10 @@ func NewRouteRequestLog() wrouter.RouteHandlerMiddleware {
 	}
 }
 
+// hostRouteParamName is the name of the request log parameter that stores the host pattern of the route that matched
+// the request (if the route was registered with one).
+const hostRouteParamName = "hostRoute"
+
 func toLoggingResponseWriter(rw http.ResponseWriter) loggingResponseWriter {
 	if lrw, ok := rw.(loggingResponseWriter); ok {
 		return lrw
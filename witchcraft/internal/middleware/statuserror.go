@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/errors"
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-server/httpserver"
+	wparams "github.com/palantir/witchcraft-go-params"
+)
+
+// StatusErrorType is a conjure error type for errors that the server responds with using an HTTP status code for which
+// conjure does not define a default error type, such as 405, 410, 412, 413 and 502. Such error types use the
+// CUSTOM_CLIENT or CUSTOM_SERVER error code, whose status codes are 400 and 500, so the status code of the response
+// does not match the error code in its body. Clients that deserialize these errors should identify them using the
+// errorName in the body (for example, "Witchcraft:EndpointSunset") and the status code of the response rather than
+// the errorCode.
+type StatusErrorType struct {
+	errorType errors.ErrorType
+	status    int
+}
+
+// NewStatusErrorType returns a StatusErrorType with the provided name whose errors are written with the provided status
+// code. The error code is CUSTOM_SERVER for 5xx status codes and CUSTOM_CLIENT otherwise. Panics if the name is not a
+// valid conjure error name.
+func NewStatusErrorType(name string, status int) StatusErrorType {
+	code := errors.CustomClient
+	if status >= http.StatusInternalServerError {
+		code = errors.CustomServer
+	}
+	return StatusErrorType{
+		errorType: errors.MustErrorType(code, name),
+		status:    status,
+	}
+}
+
+// Write writes an error of this type with the provided parameters to the provided response writer using the status code
+// of the type, and logs it using the conjure error handler. If cause is non-nil, the error wraps it.
+func (t StatusErrorType) Write(ctx context.Context, rw http.ResponseWriter, cause error, params ...wparams.ParamStorer) {
+	var err errors.Error
+	if cause != nil {
+		err = errors.WrapWithNewError(cause, t.errorType, params...)
+	} else {
+		err = errors.NewError(t.errorType, params...)
+	}
+	httpserver.ErrHandler(ctx, t.status, err)
+	httpserver.WriteJSONResponse(rw, err, t.status)
+}
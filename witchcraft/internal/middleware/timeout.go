@@ -0,0 +1,198 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/errors"
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-server/httpserver"
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/refreshable"
+	wparams "github.com/palantir/witchcraft-go-params"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-tracing/wtracing"
+)
+
+const (
+	serverResponseTimeoutMetricName = "server.response.timeout"
+
+	// timedOutParamName is the name of the request log parameter and trace span tag that is set on requests that timed
+	// out.
+	timedOutParamName = "timedOut"
+)
+
+// NewRouteTimeout returns a middleware that enforces the timeout configured for the route, or the provided default
+// timeout if the route does not configure one. The handler is invoked on the goroutine serving the request with a
+// context that has the timeout as its deadline, and writes that it makes once the deadline has passed fail with
+// http.ErrHandlerTimeout. If the handler returns after the deadline, the middleware marks the timeout meter for the
+// route, tags the request log and trace span for the request and, if the handler did not write a response before the
+// deadline, responds with a conjure timeout error. Since the handler is not abandoned, it must return once its context
+// is done for the timeout response to be written, and panics that occur after the deadline propagate to the panic
+// recovery middleware, which logs them.
+func NewRouteTimeout(defaultTimeout refreshable.Duration, mr metrics.RootRegistry) wrouter.RouteHandlerMiddleware {
+	return func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+		timeout := reqVals.Timeout
+		if timeout == 0 && defaultTimeout != nil {
+			timeout = defaultTimeout.CurrentDuration()
+		}
+		if timeout <= 0 {
+			next(rw, req, reqVals)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+
+		tw := &timeoutResponseWriter{
+			ResponseWriter: rw,
+			ctx:            ctx,
+			header:         rw.Header().Clone(),
+		}
+		returned := false
+		defer func() {
+			if returned || ctx.Err() != context.DeadlineExceeded {
+				return
+			}
+			// the handler panicked after the deadline passed: record the timeout and let the panic propagate
+			recordTimeout(req, reqVals, mr)
+		}()
+		next(tw, req.WithContext(ctx), reqVals)
+		returned = true
+
+		if ctx.Err() != context.DeadlineExceeded {
+			// the handler returned before the deadline or the request was canceled by the client
+			return
+		}
+		recordTimeout(req, reqVals, mr)
+		if tw.wroteHeader {
+			// the response was committed before the deadline passed
+			return
+		}
+		cerr := errors.NewTimeout(wparams.NewSafeParam("timeout", timeout.String()))
+		httpserver.ErrHandler(req.Context(), cerr.Code().StatusCode(), cerr)
+		errors.WriteErrorResponse(rw, cerr)
+	}
+}
+
+// recordTimeout marks the timeout meter for the route of the provided request and tags its trace span and request log
+// unless telemetry is disabled for the route.
+func recordTimeout(req *http.Request, reqVals wrouter.RequestVals, mr metrics.RootRegistry) {
+	if reqVals.DisableTelemetry {
+		return
+	}
+	mr.Meter(serverResponseTimeoutMetricName, reqVals.MetricTags...).Mark(1)
+	if span := wtracing.SpanFromContext(req.Context()); span != nil {
+		span.Tag(timedOutParamName, "true")
+	}
+	if state, ok := req.Context().Value(timeoutStateContextKey).(*timeoutState); ok {
+		state.timedOut.Store(true)
+	}
+}
+
+// timeoutResponseWriter passes the response written by a handler that runs with a timeout through to the wrapped
+// writer until the deadline of the provided context passes. Once it has passed, writes fail with
+// http.ErrHandlerTimeout and calls to WriteHeader and Flush are ignored. The handler modifies a copy of the header that
+// is only applied to the wrapped writer when the response is committed, so headers that are set by a handler that does
+// not commit its response before the deadline are not added to the timeout response. It is only used by the goroutine
+// serving the request.
+type timeoutResponseWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	header      http.Header
+	wroteHeader bool
+}
+
+func (w *timeoutResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutResponseWriter) WriteHeader(status int) {
+	if w.timedOut() || w.wroteHeader {
+		return
+	}
+	w.commitHeader()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *timeoutResponseWriter) Write(p []byte) (int, error) {
+	if w.timedOut() {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !w.wroteHeader {
+		w.commitHeader()
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *timeoutResponseWriter) Flush() {
+	if w.timedOut() {
+		return
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		if !w.wroteHeader {
+			w.commitHeader()
+		}
+		flusher.Flush()
+	}
+}
+
+func (w *timeoutResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("the ResponseWriter doesn't support the Hijacker interface")
+	}
+	// the connection is no longer managed by the server, so no timeout response is written for it
+	w.commitHeader()
+	return hijacker.Hijack()
+}
+
+// commitHeader replaces the header of the wrapped writer with the header modified by the handler.
+func (w *timeoutResponseWriter) commitHeader() {
+	w.wroteHeader = true
+	dst := w.ResponseWriter.Header()
+	for k := range dst {
+		if _, ok := w.header[k]; !ok {
+			delete(dst, k)
+		}
+	}
+	for k, v := range w.header {
+		dst[k] = v
+	}
+}
+
+func (w *timeoutResponseWriter) timedOut() bool {
+	return w.ctx.Err() == context.DeadlineExceeded
+}
+
+// timeoutState records whether the request timed out so that the request log middleware, which runs before the timeout
+// middleware, can tag the request log.
+type timeoutState struct {
+	timedOut atomic.Bool
+}
+
+type timeoutStateContextKeyType struct{}
+
+var timeoutStateContextKey = timeoutStateContextKeyType{}
+
+func contextWithTimeoutState(ctx context.Context) (context.Context, *timeoutState) {
+	state := &timeoutState{}
+	return context.WithValue(ctx, timeoutStateContextKey, state), state
+}
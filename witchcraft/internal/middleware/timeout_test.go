@@ -0,0 +1,184 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/errors"
+	"github.com/palantir/pkg/metrics"
+	"github.com/palantir/pkg/refreshable"
+	wlogzap "github.com/palantir/witchcraft-go-logging/wlog-zap"
+	"github.com/palantir/witchcraft-go-logging/wlog/reqlog/req2log"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/requestlog"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteTimeout(t *testing.T) {
+	var reqOutput bytes.Buffer
+	reqLog := requestlog.NewLogger(&reqOutput, req2log.Creator(wlogzap.LoggerProvider().NewLogger))
+	registry := metrics.NewRootMetricsRegistry()
+	defaultTimeout := refreshable.NewDefaultRefreshable(time.Duration(0))
+
+	r := wrouter.New(whttprouter.New())
+	r.AddRequestHandlerMiddleware(func(rw http.ResponseWriter, req *http.Request, next http.Handler) {
+		next.ServeHTTP(rw, req.WithContext(req2log.WithLogger(req.Context(), reqLog)))
+	})
+	r.AddRouteHandlerMiddleware(
+		middleware.NewRouteRequestLog(),
+		middleware.NewRouteTimeout(refreshable.NewDuration(defaultTimeout), registry),
+	)
+
+	// slowHandler ignores the cancellation of its context and writes its response after the deadline has passed
+	slowHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+		time.Sleep(10 * time.Millisecond)
+		rw.Header().Set("X-Slow", "true")
+		_, _ = rw.Write([]byte("late"))
+	})
+	fastHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, hasDeadline := req.Context().Deadline()
+		rw.Header().Set("X-Has-Deadline", strconv.FormatBool(hasDeadline))
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write([]byte("ok"))
+	})
+	require.NoError(t, r.Get("/slow", slowHandler, wrouter.RouteTimeout(10*time.Millisecond), wrouter.MetricTags(metrics.MustNewTags(map[string]string{"endpoint": "slow"}))))
+	require.NoError(t, r.Get("/fast", fastHandler, wrouter.RouteTimeout(time.Minute)))
+	require.NoError(t, r.Get("/default", fastHandler))
+
+	t.Run("timed out", func(t *testing.T) {
+		reqOutput.Reset()
+		rw := httptest.NewRecorder()
+		r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rw.Code)
+		assert.Empty(t, rw.Header().Get("X-Slow"))
+		var serializableErr errors.SerializableError
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &serializableErr))
+		assert.Equal(t, errors.DefaultTimeout.Name(), serializableErr.ErrorName)
+
+		var reqLogEntry map[string]interface{}
+		require.NoError(t, json.Unmarshal(reqOutput.Bytes(), &reqLogEntry))
+		assert.Equal(t, map[string]interface{}{"timedOut": "true"}, reqLogEntry["params"])
+		assert.Equal(t, float64(http.StatusInternalServerError), reqLogEntry["status"])
+
+		assert.Equal(t, int64(1), registry.Meter("server.response.timeout", metrics.MustNewTag("endpoint", "slow")).Count())
+	})
+
+	t.Run("completed", func(t *testing.T) {
+		reqOutput.Reset()
+		rw := httptest.NewRecorder()
+		r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+		assert.Equal(t, http.StatusCreated, rw.Code)
+		assert.Equal(t, "true", rw.Header().Get("X-Has-Deadline"))
+		assert.Equal(t, "ok", rw.Body.String())
+		assert.NotContains(t, reqOutput.String(), "timedOut")
+	})
+
+	t.Run("default timeout", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/default", nil))
+		assert.Equal(t, "false", rw.Header().Get("X-Has-Deadline"))
+
+		require.NoError(t, defaultTimeout.Update(time.Minute))
+		rw = httptest.NewRecorder()
+		r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/default", nil))
+		assert.Equal(t, "true", rw.Header().Get("X-Has-Deadline"))
+	})
+}
+
+func TestRouteTimeoutPanic(t *testing.T) {
+	timeoutMiddleware := middleware.NewRouteTimeout(nil, metrics.NewRootMetricsRegistry())
+	assert.PanicsWithValue(t, "handler panic", func() {
+		timeoutMiddleware(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), wrouter.RequestVals{Timeout: time.Minute},
+			func(rw http.ResponseWriter, r *http.Request, reqVals wrouter.RequestVals) {
+				panic("handler panic")
+			},
+		)
+	})
+}
+
+func TestRouteTimeoutPanicAfterDeadline(t *testing.T) {
+	registry := metrics.NewRootMetricsRegistry()
+	timeoutMiddleware := middleware.NewRouteTimeout(nil, registry)
+	assert.PanicsWithValue(t, "late panic", func() {
+		timeoutMiddleware(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil), wrouter.RequestVals{Timeout: time.Millisecond},
+			func(rw http.ResponseWriter, r *http.Request, reqVals wrouter.RequestVals) {
+				<-r.Context().Done()
+				panic("late panic")
+			},
+		)
+	})
+	assert.Equal(t, int64(1), registry.Meter("server.response.timeout").Count())
+}
+
+func TestRouteTimeoutStreaming(t *testing.T) {
+	registry := metrics.NewRootMetricsRegistry()
+	timeoutMiddleware := middleware.NewRouteTimeout(nil, registry)
+
+	rw := httptest.NewRecorder()
+	var lateWriteErr error
+	timeoutMiddleware(rw, httptest.NewRequest(http.MethodGet, "/", nil), wrouter.RequestVals{Timeout: 10 * time.Millisecond},
+		func(rw http.ResponseWriter, r *http.Request, reqVals wrouter.RequestVals) {
+			rw.Header().Set("X-Streamed", "true")
+			_, _ = rw.Write([]byte("partial"))
+			rw.(http.Flusher).Flush()
+			<-r.Context().Done()
+			_, lateWriteErr = rw.Write([]byte("late"))
+		},
+	)
+	assert.True(t, rw.Flushed)
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "true", rw.Header().Get("X-Streamed"))
+	assert.Equal(t, "partial", rw.Body.String())
+	assert.Equal(t, http.ErrHandlerTimeout, lateWriteErr)
+	assert.Equal(t, int64(1), registry.Meter("server.response.timeout").Count())
+}
+
+func TestRouteTimeoutHijack(t *testing.T) {
+	timeoutMiddleware := middleware.NewRouteTimeout(nil, metrics.NewRootMetricsRegistry())
+	rw := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder()}
+	timeoutMiddleware(rw, httptest.NewRequest(http.MethodGet, "/", nil), wrouter.RequestVals{Timeout: time.Minute},
+		func(rw http.ResponseWriter, r *http.Request, reqVals wrouter.RequestVals) {
+			hijacker, ok := rw.(http.Hijacker)
+			require.True(t, ok)
+			_, _, _ = hijacker.Hijack()
+		},
+	)
+	assert.True(t, rw.hijacked)
+}
+
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (r *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	return nil, nil, nil
+}
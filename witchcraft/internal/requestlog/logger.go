@@ -0,0 +1,240 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package requestlog
+
+import (
+	"io"
+	"strings"
+
+	"github.com/palantir/witchcraft-go-logging/wlog"
+	"github.com/palantir/witchcraft-go-logging/wlog/extractor"
+	"github.com/palantir/witchcraft-go-logging/wlog/reqlog/req2log"
+	"github.com/palantir/witchcraft-go-logging/wlog/wrappedlog/wrapped1log"
+)
+
+// paramsKey is the key of the safe parameters of a request.2 log entry.
+const paramsKey = "params"
+
+// NewLogger returns a request.2 logger that behaves like the logger returned by req2log.New and that also records the
+// parameters set using SetParam as safe parameters of the entry.
+func NewLogger(w io.Writer, params ...req2log.LoggerCreatorParam) req2log.Logger {
+	return newLoggerBuilder(params).build(w)
+}
+
+// NewWrappedLogger returns a request.2 logger that emits entries in the wrapped.1 format like the logger returned by
+// wrapped1log.Logger.Request and that also records the parameters set using SetParam as safe parameters of the entry.
+func NewWrappedLogger(w io.Writer, name, version string, params ...req2log.LoggerCreatorParam) req2log.Logger {
+	l := newLoggerBuilder(params).build(w)
+	l.wrapped = true
+	l.name = name
+	l.version = version
+	return l
+}
+
+type logger struct {
+	logger       wlog.Logger
+	idsExtractor extractor.IDsFromRequest
+
+	pathParamPerms   req2log.ParamPerms
+	queryParamPerms  req2log.ParamPerms
+	headerParamPerms req2log.ParamPerms
+
+	wrapped bool
+	name    string
+	version string
+}
+
+func (l *logger) Request(r req2log.Request) {
+	params := l.toParams(r)
+	if l.wrapped {
+		params = []wlog.Param{
+			wlog.StringParam(wlog.TypeKey, wrapped1log.TypeValue),
+			wlog.StringParam(wrapped1log.WrappedEntityNameKey, l.name),
+			wlog.StringParam(wrapped1log.WrappedEntityVersionKey, l.version),
+			wrappedPayloadParam(params),
+		}
+	}
+	l.logger.Log(params...)
+}
+
+func (l *logger) PathParamPerms() req2log.ParamPerms {
+	return l.pathParamPerms
+}
+
+func (l *logger) QueryParamPerms() req2log.ParamPerms {
+	return l.queryParamPerms
+}
+
+func (l *logger) HeaderParamPerms() req2log.ParamPerms {
+	return l.headerParamPerms
+}
+
+// toParams returns the parameters of the request.2 log entry for the provided request, which include the parameters set
+// using SetParam for the request in the safe parameters of the entry. Parameters set using SetParam never replace the
+// path, query or header parameters of the request that are logged as safe.
+func (l *logger) toParams(r req2log.Request) []wlog.Param {
+	params := req2log.ToParams(r, l.idsExtractor, l.pathParamPerms, l.queryParamPerms, l.headerParamPerms)
+	extraParams := l.extraParams(r)
+	if len(extraParams) == 0 {
+		return params
+	}
+	return []wlog.Param{wlog.NewParam(func(entry wlog.LogEntry) {
+		safeEntry := &safeParamsEntry{
+			LogEntry: entry,
+			params:   extraParams,
+		}
+		wlog.ApplyParams(safeEntry, params)
+		if !safeEntry.written {
+			entry.AnyMapValue(paramsKey, extraParams)
+		}
+	})}
+}
+
+// extraParams returns the parameters set using SetParam for the provided request that can be logged. Parameters whose
+// key matches the name of a path parameter of the request (ignoring case) or a forbidden path parameter are dropped so
+// that they can neither hide the value of a path parameter nor log a value under a key that is forbidden.
+func (l *logger) extraParams(r req2log.Request) map[string]interface{} {
+	if r.Request == nil {
+		return nil
+	}
+	params := Params(r.Request.Context())
+	if len(params) == 0 {
+		return nil
+	}
+	pathParamNames := make(map[string]struct{}, len(r.RouteInfo.PathParams))
+	for k := range r.RouteInfo.PathParams {
+		pathParamNames[strings.ToLower(k)] = struct{}{}
+	}
+	extraParams := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		lowerK := strings.ToLower(k)
+		if _, ok := pathParamNames[lowerK]; ok {
+			continue
+		}
+		if l.pathParamPerms != nil && l.pathParamPerms.Forbidden(lowerK) {
+			continue
+		}
+		if r.PathParamPerms != nil && r.PathParamPerms.Forbidden(lowerK) {
+			continue
+		}
+		extraParams[k] = v
+	}
+	return extraParams
+}
+
+// safeParamsEntry is a wlog.LogEntry that adds params to the safe parameters written to the wrapped entry without
+// replacing the values of existing safe parameters.
+type safeParamsEntry struct {
+	wlog.LogEntry
+	params  map[string]interface{}
+	written bool
+}
+
+func (e *safeParamsEntry) AnyMapValue(k string, v map[string]interface{}) {
+	if k != paramsKey {
+		e.LogEntry.AnyMapValue(k, v)
+		return
+	}
+	merged := make(map[string]interface{}, len(v)+len(e.params))
+	for paramKey, paramVal := range e.params {
+		merged[paramKey] = paramVal
+	}
+	for paramKey, paramVal := range v {
+		merged[paramKey] = paramVal
+	}
+	e.LogEntry.AnyMapValue(k, merged)
+	e.written = true
+}
+
+// wrappedPayloadParam returns the parameter that writes the provided request.2 parameters as the payload of a wrapped.1
+// log entry.
+func wrappedPayloadParam(params []wlog.Param) wlog.Param {
+	return wlog.NewParam(func(entry wlog.LogEntry) {
+		req2Log := wlog.NewMapLogEntry()
+		wlog.ApplyParams(req2Log, params)
+		payload := wlog.NewMapLogEntry()
+		payload.StringValue(wrapped1log.PayloadTypeKey, wrapped1log.PayloadRequestLogV2)
+		payload.AnyMapValue(wrapped1log.PayloadRequestLogV2, req2Log.AllValues())
+		entry.AnyMapValue(wrapped1log.PayloadKey, payload.AllValues())
+	})
+}
+
+type loggerBuilder struct {
+	loggerCreator wlog.LoggerCreator
+	idsExtractor  extractor.IDsFromRequest
+
+	safePathParams      []string
+	forbiddenPathParams []string
+
+	safeQueryParams      []string
+	forbiddenQueryParams []string
+
+	safeHeaderParams      []string
+	forbiddenHeaderParams []string
+}
+
+func newLoggerBuilder(params []req2log.LoggerCreatorParam) *loggerBuilder {
+	b := &loggerBuilder{
+		loggerCreator: wlog.DefaultLoggerProvider().NewLogger,
+		idsExtractor:  extractor.NewDefaultIDsExtractor(),
+	}
+	for _, p := range params {
+		p.Apply(b)
+	}
+	return b
+}
+
+func (b *loggerBuilder) LoggerCreator(creator wlog.LoggerCreator) {
+	b.loggerCreator = creator
+}
+
+func (b *loggerBuilder) IdsExtractor(idsExtractor extractor.IDsFromRequest) {
+	b.idsExtractor = idsExtractor
+}
+
+func (b *loggerBuilder) SafePathParams(safePathParams []string) {
+	b.safePathParams = append(b.safePathParams, safePathParams...)
+}
+
+func (b *loggerBuilder) ForbiddenPathParams(forbiddenPathParams []string) {
+	b.forbiddenPathParams = append(b.forbiddenPathParams, forbiddenPathParams...)
+}
+
+func (b *loggerBuilder) SafeQueryParams(safeQueryParams []string) {
+	b.safeQueryParams = append(b.safeQueryParams, safeQueryParams...)
+}
+
+func (b *loggerBuilder) ForbiddenQueryParams(forbiddenQueryParams []string) {
+	b.forbiddenQueryParams = append(b.forbiddenQueryParams, forbiddenQueryParams...)
+}
+
+func (b *loggerBuilder) SafeHeaderParams(safeHeaderParams []string) {
+	b.safeHeaderParams = append(b.safeHeaderParams, safeHeaderParams...)
+}
+
+func (b *loggerBuilder) ForbiddenHeaderParams(forbiddenHeaderParams []string) {
+	b.forbiddenHeaderParams = append(b.forbiddenHeaderParams, forbiddenHeaderParams...)
+}
+
+func (b *loggerBuilder) build(w io.Writer) *logger {
+	defaultParams := req2log.DefaultRequestParamPerms()
+	return &logger{
+		logger:           b.loggerCreator(w),
+		idsExtractor:     b.idsExtractor,
+		pathParamPerms:   req2log.CombinedParamPerms(defaultParams.PathParamPerms(), req2log.NewParamPerms(b.safePathParams, b.forbiddenPathParams)),
+		queryParamPerms:  req2log.CombinedParamPerms(defaultParams.QueryParamPerms(), req2log.NewParamPerms(b.safeQueryParams, b.forbiddenQueryParams)),
+		headerParamPerms: req2log.CombinedParamPerms(defaultParams.HeaderParamPerms(), req2log.NewParamPerms(b.safeHeaderParams, b.forbiddenHeaderParams)),
+	}
+}
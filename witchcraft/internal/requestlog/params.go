@@ -0,0 +1,78 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package requestlog records parameters set by the server and the handlers of a request as safe parameters of the
+// request log entry of the request. The parameters are collected in the context of the request and are recorded by the
+// request loggers returned by NewLogger and NewWrappedLogger separately from the path, query and header parameters of
+// the request.
+package requestlog
+
+import (
+	"context"
+	"sync"
+)
+
+type paramsContextKeyType struct{}
+
+var paramsContextKey = paramsContextKeyType{}
+
+// params stores the parameters set using SetParam for a single request.
+type params struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+// ContextWithParams returns a context that collects the parameters set using SetParam. It is called by the middleware
+// that writes the request log entry of a route before invoking the route handler.
+func ContextWithParams(ctx context.Context) context.Context {
+	return context.WithValue(ctx, paramsContextKey, &params{})
+}
+
+// SetParam sets a parameter that is recorded as a safe parameter of the request log entry of the request with the
+// provided context. The value is logged as is, so callers are responsible for ensuring that it never contains unsafe
+// data such as user input, identifiers of user data or credentials. Parameters whose key matches the name of a path
+// parameter of the route (ignoring case), a forbidden path parameter or a safe query or header parameter are not logged
+// (see NewLogger). Does nothing if the context was not created using ContextWithParams (for example, if telemetry is
+// disabled for the route).
+func SetParam(ctx context.Context, key, value string) {
+	p, ok := ctx.Value(paramsContextKey).(*params)
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.values == nil {
+		p.values = make(map[string]string)
+	}
+	p.values[key] = value
+}
+
+// Params returns a copy of the parameters set using SetParam for the request with the provided context. Returns nil if
+// no parameters were set.
+func Params(ctx context.Context) map[string]string {
+	p, ok := ctx.Value(paramsContextKey).(*params)
+	if !ok {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.values) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(p.values))
+	for k, v := range p.values {
+		values[k] = v
+	}
+	return values
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wdebug
+
+import (
+	"net/http"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-server/httpserver"
+	"github.com/palantir/pkg/refreshable"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wresource"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+// PurgeResponseCacheResponse is the response of the route that purges the response cache.
+type PurgeResponseCacheResponse struct {
+	// Purged is the number of cached responses that were removed.
+	Purged int `json:"purged"`
+}
+
+// RegisterPurgeResponseCacheRoute registers a route on the provided router that removes responses from the response
+// cache of the server using the provided purge function. The route removes the responses cached for the cache name
+// provided in the "name" query parameter, or all responses if the parameter is not provided. Requests must provide the
+// shared secret as their bearer token if it is not empty.
+func RegisterPurgeResponseCacheRoute(router wrouter.Router, sharedSecret refreshable.String, purge func(name string) int) error {
+	if err := wresource.New("witchcraftdebugservice", router).
+		Delete("PurgeResponseCache", "/debug/response-cache",
+			httpserver.NewJSONHandler(func(rw http.ResponseWriter, req *http.Request) error {
+				if err := authorize(req, sharedSecret); err != nil {
+					return err
+				}
+				purged := purge(req.URL.Query().Get("name"))
+				httpserver.WriteJSONResponse(rw, PurgeResponseCacheResponse{Purged: purged}, http.StatusOK)
+				return nil
+			}, httpserver.StatusCodeMapper, httpserver.ErrHandler),
+			wrouter.SafeQueryParams("name"),
+		); err != nil {
+		return werror.Wrap(err, "failed to register response cache purge route")
+	}
+	return nil
+}
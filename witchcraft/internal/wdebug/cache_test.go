@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wdebug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/palantir/pkg/refreshable"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/routertest"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeResponseCacheRoute(t *testing.T) {
+	r := wrouter.New(whttprouter.New())
+	var purgedNames []string
+	require.NoError(t, RegisterPurgeResponseCacheRoute(r, refreshable.NewString(refreshable.NewDefaultRefreshable("secret")), func(name string) int {
+		purgedNames = append(purgedNames, name)
+		return 2
+	}))
+
+	doRequest := func(target, token string) *httptest.ResponseRecorder {
+		var header http.Header
+		if token != "" {
+			header = http.Header{"Authorization": {"Bearer " + token}}
+		}
+		return routertest.ServeRequest(r, http.MethodDelete, target, header, nil)
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, doRequest("/debug/response-cache", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, doRequest("/debug/response-cache", "other").Code)
+	assert.Empty(t, purgedNames)
+
+	rw := doRequest("/debug/response-cache?name=reports", "secret")
+	require.Equal(t, http.StatusOK, rw.Code)
+	var resp PurgeResponseCacheResponse
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+	assert.Equal(t, PurgeResponseCacheResponse{Purged: 2}, resp)
+
+	require.Equal(t, http.StatusOK, doRequest("/debug/response-cache", "secret").Code)
+	assert.Equal(t, []string{"reports", ""}, purgedNames)
+}
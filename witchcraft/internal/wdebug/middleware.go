@@ -0,0 +1,97 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wdebug
+
+import (
+	"context"
+	"io"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+const (
+	DiagnosticTypeMiddlewareV1 DiagnosticType = "http.middleware.v1"
+
+	MiddlewareKindRequest = "request"
+	MiddlewareKindRoute   = "route"
+)
+
+// MiddlewareStageDiagnostic describes a stage of the middleware installed by a server.
+type MiddlewareStageDiagnostic struct {
+	Name string `json:"name"`
+	// Kind is "request" for request middleware stages and "route" for route middleware stages.
+	Kind string `json:"kind"`
+	// Custom is true if the stage was inserted by the server's configuration rather than built in.
+	Custom bool `json:"custom"`
+	// Replaced is true if the middleware of the stage was replaced by the server's configuration.
+	Replaced bool `json:"replaced"`
+	// Disabled is true if the stage is not installed.
+	Disabled bool `json:"disabled"`
+	// Middleware stores the function names of the middleware of the stage.
+	Middleware []string `json:"middleware"`
+}
+
+// NewMiddlewareDiagnosticHandler returns a DiagnosticHandler that lists the provided middleware stages in the order in
+// which they run.
+func NewMiddlewareDiagnosticHandler(stages []MiddlewareStageDiagnostic) DiagnosticHandler {
+	return handlerMiddlewareV1{
+		stages: stages,
+	}
+}
+
+// MiddlewareFuncNames returns the function names of the provided middleware functions.
+func MiddlewareFuncNames[T any](middleware []T) []string {
+	names := make([]string, 0, len(middleware))
+	for _, m := range middleware {
+		names = append(names, funcName(m))
+	}
+	return names
+}
+
+type handlerMiddlewareV1 struct {
+	stages []MiddlewareStageDiagnostic
+}
+
+func (h handlerMiddlewareV1) Type() DiagnosticType {
+	return DiagnosticTypeMiddlewareV1
+}
+
+func (h handlerMiddlewareV1) ContentType() string {
+	return codecs.JSON.ContentType()
+}
+
+func (h handlerMiddlewareV1) Documentation() string {
+	return `Lists the middleware stages installed on the server in the order in which they run`
+}
+
+func (h handlerMiddlewareV1) SafeLoggable() bool {
+	return true
+}
+
+func (h handlerMiddlewareV1) Extension() string {
+	return "json"
+}
+
+func (h handlerMiddlewareV1) WriteDiagnostic(ctx context.Context, w io.Writer) error {
+	stages := h.stages
+	if stages == nil {
+		stages = []MiddlewareStageDiagnostic{}
+	}
+	if err := codecs.JSON.Encode(w, stages); err != nil {
+		return werror.WrapWithContextParams(ctx, err, "failed to write middleware stages")
+	}
+	return nil
+}
@@ -35,10 +35,20 @@ type DiagnosticType string
 
 type debugResource struct {
 	SharedSecret refreshable.String
+	Handlers     map[DiagnosticType]DiagnosticHandler
 }
 
-func RegisterRoute(router wrouter.Router, sharedSecret refreshable.String) error {
-	r := &debugResource{SharedSecret: sharedSecret}
+// RegisterRoute registers the debug diagnostic route on the provided router. The route serves the built-in diagnostics
+// and the provided additional diagnostics, which take precedence over built-in diagnostics of the same type.
+func RegisterRoute(router wrouter.Router, sharedSecret refreshable.String, additionalHandlers ...DiagnosticHandler) error {
+	handlers := make(map[DiagnosticType]DiagnosticHandler, len(diagnosticHandlers)+len(additionalHandlers))
+	for diagnosticType, handler := range diagnosticHandlers {
+		handlers[diagnosticType] = handler
+	}
+	for _, handler := range additionalHandlers {
+		handlers[handler.Type()] = handler
+	}
+	r := &debugResource{SharedSecret: sharedSecret, Handlers: handlers}
 	if err := wresource.New("witchcraftdebugservice", router).
 		Get("GetDiagnostic", "/debug/diagnostic/{diagnosticType}",
 			httpserver.NewJSONHandler(r.ServeHTTP, httpserver.StatusCodeMapper, httpserver.ErrHandler),
@@ -50,14 +60,8 @@ func RegisterRoute(router wrouter.Router, sharedSecret refreshable.String) error
 
 func (r *debugResource) ServeHTTP(rw http.ResponseWriter, req *http.Request) error {
 	ctx := req.Context()
-	if sharedSecret := r.SharedSecret.CurrentString(); sharedSecret != "" {
-		token, err := httpserver.ParseBearerTokenHeader(req)
-		if err != nil {
-			return errors.WrapWithUnauthorized(err)
-		}
-		if !httpserver.SecretStringEqual(sharedSecret, token) {
-			return errors.NewUnauthorized()
-		}
+	if err := authorize(req, r.SharedSecret); err != nil {
+		return err
 	}
 
 	pathParams := wrouter.PathParams(req)
@@ -70,7 +74,7 @@ func (r *debugResource) ServeHTTP(rw http.ResponseWriter, req *http.Request) err
 	}
 	diagnosticType := DiagnosticType(diagnosticTypeStr)
 
-	handler, ok := diagnosticHandlers[diagnosticType]
+	handler, ok := r.Handlers[diagnosticType]
 	if !ok {
 		return errors.WrapWithInvalidArgument(werror.ErrorWithContextParams(ctx, "unsupported diagnosticType", werror.SafeParam("diagnosticType", diagnosticType)))
 	}
@@ -79,3 +83,18 @@ func (r *debugResource) ServeHTTP(rw http.ResponseWriter, req *http.Request) err
 	rw.Header().Set(headerKeySafeLoggable, strconv.FormatBool(handler.SafeLoggable()))
 	return handler.WriteDiagnostic(ctx, rw)
 }
+
+// authorize returns an unauthorized error if the shared secret is not empty and the request does not provide it as its
+// bearer token.
+func authorize(req *http.Request, sharedSecret refreshable.String) error {
+	if secret := sharedSecret.CurrentString(); secret != "" {
+		token, err := httpserver.ParseBearerTokenHeader(req)
+		if err != nil {
+			return errors.WrapWithUnauthorized(err)
+		}
+		if !httpserver.SecretStringEqual(secret, token) {
+			return errors.NewUnauthorized()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wdebug
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"runtime"
+	"time"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/codecs"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wresource"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+const (
+	DiagnosticTypeRoutesV1 DiagnosticType = "http.routes.v1"
+
+	ServerMain       = "main"
+	ServerManagement = "management"
+)
+
+// RouteDiagnostic describes a route registered on a server.
+type RouteDiagnostic struct {
+	// Servers are the servers that serve the route: "main", "management" or both if the servers share a port.
+	Servers           []string          `json:"servers"`
+	Method            string            `json:"method"`
+	PathTemplate      string            `json:"pathTemplate"`
+	Resource          string            `json:"resource,omitempty"`
+	Endpoint          string            `json:"endpoint,omitempty"`
+	MetricTags        map[string]string `json:"metricTags,omitempty"`
+	SafeParams        RouteParamNames   `json:"safeParams"`
+	ForbiddenParams   RouteParamNames   `json:"forbiddenParams"`
+	TelemetryDisabled bool              `json:"telemetryDisabled"`
+	// Timeout is the timeout configured for the route. Empty if the route uses the server's default timeout.
+	Timeout string `json:"timeout,omitempty"`
+	// MaxBodySize is the maximum request body size configured for the route. 0 if the route uses the server's default.
+	MaxBodySize int64 `json:"maxBodySize,omitempty"`
+	// Host is the host pattern that requests must match to be routed to the route. Empty if the route matches any host.
+	Host string `json:"host,omitempty"`
+	// Headers maps the names of the headers that requests must have to be routed to the route to their values.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Deprecated is true if the route is deprecated.
+	Deprecated bool `json:"deprecated,omitempty"`
+	// Sunset is the sunset of the route in RFC 3339 format. Empty if the route is not deprecated or has no sunset.
+	Sunset string `json:"sunset,omitempty"`
+	// Cache is the name of the cache of the route. Empty if the responses of the route are not cached.
+	Cache string `json:"cache,omitempty"`
+	// Metadata stores the formatted values of the metadata attached to the route keyed by the names of their keys.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Middleware stores the function names of the middleware registered for the route.
+	Middleware []string `json:"middleware"`
+}
+
+// RouteParamNames stores parameter names by the part of the request in which they appear.
+type RouteParamNames struct {
+	Path   []string `json:"path"`
+	Query  []string `json:"query"`
+	Header []string `json:"header"`
+}
+
+// NewRoutesDiagnosticHandler returns a DiagnosticHandler that lists the routes registered on the provided main and
+// management routers at the time the diagnostic is requested. If the routers are the same, each route is reported as
+// served by both servers.
+func NewRoutesDiagnosticHandler(mainRouter, mgmtRouter wrouter.RootRouter) DiagnosticHandler {
+	return handlerRoutesV1{
+		mainRouter: mainRouter,
+		mgmtRouter: mgmtRouter,
+	}
+}
+
+type handlerRoutesV1 struct {
+	mainRouter wrouter.RootRouter
+	mgmtRouter wrouter.RootRouter
+}
+
+func (h handlerRoutesV1) Type() DiagnosticType {
+	return DiagnosticTypeRoutesV1
+}
+
+func (h handlerRoutesV1) ContentType() string {
+	return codecs.JSON.ContentType()
+}
+
+func (h handlerRoutesV1) Documentation() string {
+	return `Lists the routes registered on the main and management servers with their endpoint names, parameter permissions and middleware`
+}
+
+func (h handlerRoutesV1) SafeLoggable() bool {
+	return true
+}
+
+func (h handlerRoutesV1) Extension() string {
+	return "json"
+}
+
+func (h handlerRoutesV1) WriteDiagnostic(ctx context.Context, w io.Writer) error {
+	var result []RouteDiagnostic
+	if h.mainRouter == h.mgmtRouter {
+		result = routeDiagnostics(h.mainRouter, ServerMain, ServerManagement)
+	} else {
+		result = append(routeDiagnostics(h.mainRouter, ServerMain), routeDiagnostics(h.mgmtRouter, ServerManagement)...)
+	}
+	if err := codecs.JSON.Encode(w, result); err != nil {
+		return werror.WrapWithContextParams(ctx, err, "failed to write routes")
+	}
+	return nil
+}
+
+func routeDiagnostics(router wrouter.RootRouter, servers ...string) []RouteDiagnostic {
+	routeInfos := wrouter.RegisteredRouteInfos(router)
+	result := make([]RouteDiagnostic, 0, len(routeInfos))
+	for _, info := range routeInfos {
+		route := RouteDiagnostic{
+			Servers:           servers,
+			Method:            info.Spec.Method,
+			PathTemplate:      info.Spec.PathTemplate,
+			TelemetryDisabled: info.DisableTelemetry,
+			Middleware:        make([]string, 0, len(info.Middleware)),
+		}
+		if info.Timeout > 0 {
+			route.Timeout = info.Timeout.String()
+		}
+		route.MaxBodySize = info.MaxBodySize
+		route.Host = info.Conditions.Host
+		route.Headers = info.Conditions.Headers
+		if info.Deprecation != nil {
+			route.Deprecated = true
+			if !info.Deprecation.Sunset.IsZero() {
+				route.Sunset = info.Deprecation.Sunset.UTC().Format(time.RFC3339)
+			}
+		}
+		if info.Cache != nil {
+			route.Cache = info.Cache.Name
+		}
+		if len(info.MetricTags) > 0 {
+			route.MetricTags = info.MetricTags.ToMap()
+			route.Resource = route.MetricTags[wresource.ResourceTagName]
+			route.Endpoint = route.MetricTags[wresource.EndpointTagName]
+		}
+		if info.Metadata.Len() > 0 {
+			route.Metadata = info.Metadata.Strings()
+		}
+		if info.ParamPerms != nil {
+			route.SafeParams.Path, route.ForbiddenParams.Path = wrouter.ParamPermsNames(info.ParamPerms.PathParamPerms())
+			route.SafeParams.Query, route.ForbiddenParams.Query = wrouter.ParamPermsNames(info.ParamPerms.QueryParamPerms())
+			route.SafeParams.Header, route.ForbiddenParams.Header = wrouter.ParamPermsNames(info.ParamPerms.HeaderParamPerms())
+		}
+		for _, middleware := range info.Middleware {
+			route.Middleware = append(route.Middleware, funcName(middleware))
+		}
+		result = append(result, route)
+	}
+	return result
+}
+
+func funcName(fn interface{}) string {
+	if f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()); f != nil {
+		return f.Name()
+	}
+	return "unknown"
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wdebug
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wresource"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutesDiagnostic(t *testing.T) {
+	mainRouter := wrouter.New(whttprouter.New())
+	require.NoError(t, wresource.New("datasets", mainRouter).Get("getDataset", "/datasets/{rid}", http.NotFoundHandler(),
+		wrouter.SafePathParams("rid"),
+		wrouter.ForbiddenHeaderParams("Authorization"),
+		wrouter.RouteMiddleware(testRouteMiddleware),
+		wrouter.RouteTimeout(30*time.Second),
+		wrouter.RouteMaxBodySize(1024),
+		wrouter.RouteDeprecated(wrouter.RouteDeprecation{Sunset: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)}),
+		wrouter.RouteMetadata(wrouter.NewMetadataKey[int]("sloClass"), 1),
+	))
+	mgmtRouter := wrouter.New(whttprouter.New())
+	require.NoError(t, mgmtRouter.Get("/status/liveness", http.NotFoundHandler(), wrouter.DisableTelemetry(), wrouter.MatchHeader("x-probe", "true")))
+
+	t.Run("distinct routers", func(t *testing.T) {
+		routes := writeRoutesDiagnostic(t, NewRoutesDiagnosticHandler(mainRouter, mgmtRouter))
+		require.Len(t, routes, 2)
+		assert.Equal(t, RouteDiagnostic{
+			Servers:      []string{ServerMain},
+			Method:       http.MethodGet,
+			PathTemplate: "/datasets/{rid}",
+			Resource:     "datasets",
+			// metric tag values are normalized to lowercase
+			Endpoint: "getdataset",
+			MetricTags: map[string]string{
+				wresource.ResourceTagName: "datasets",
+				wresource.MethodTagName:   "get",
+				wresource.EndpointTagName: "getdataset",
+			},
+			SafeParams:      RouteParamNames{Path: []string{"rid"}},
+			ForbiddenParams: RouteParamNames{Header: []string{"authorization"}},
+			Timeout:         "30s",
+			MaxBodySize:     1024,
+			Deprecated:      true,
+			Sunset:          "2027-01-01T00:00:00Z",
+			Metadata:        map[string]string{"sloClass": "1"},
+			Middleware:      []string{"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/wdebug.testRouteMiddleware"},
+		}, routes[0])
+		assert.Equal(t, RouteDiagnostic{
+			Servers:           []string{ServerManagement},
+			Method:            http.MethodGet,
+			PathTemplate:      "/status/liveness",
+			TelemetryDisabled: true,
+			Headers:           map[string]string{"X-Probe": "true"},
+			Middleware:        []string{},
+		}, routes[1])
+	})
+
+	t.Run("shared router", func(t *testing.T) {
+		routes := writeRoutesDiagnostic(t, NewRoutesDiagnosticHandler(mainRouter, mainRouter))
+		require.Len(t, routes, 1)
+		assert.Equal(t, []string{ServerMain, ServerManagement}, routes[0].Servers)
+	})
+}
+
+func writeRoutesDiagnostic(t *testing.T, handler DiagnosticHandler) []RouteDiagnostic {
+	var buf bytes.Buffer
+	require.NoError(t, handler.WriteDiagnostic(context.Background(), &buf))
+	var routes []RouteDiagnostic
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &routes))
+	return routes
+}
+
+func testRouteMiddleware(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+	next(rw, req, reqVals)
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/palantir/pkg/metrics"
+	werror "github.com/palantir/witchcraft-go-error"
 	"github.com/palantir/witchcraft-go-logging/wlog"
 	"github.com/palantir/witchcraft-go-logging/wlog/auditlog/audit2log"
 	"github.com/palantir/witchcraft-go-logging/wlog/diaglog/diag1log"
@@ -31,6 +32,7 @@ import (
 	"github.com/palantir/witchcraft-go-logging/wlog/trclog/trc1log"
 	"github.com/palantir/witchcraft-go-logging/wlog/wrappedlog/wrapped1log"
 	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/metricloggers"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/requestlog"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
@@ -61,8 +63,9 @@ func (s *Server) initDefaultLoggers(useConsoleLog bool, logLevel wlog.LogLevel,
 		loggerStdoutWriter = s.loggerStdoutWriter
 	}
 
+	s.logFileWriters = nil
 	logWriterFn := func(slsFilename string) io.Writer {
-		internalWriter := newDefaultLogOutputWriter(slsFilename, useConsoleLog, loggerStdoutWriter)
+		internalWriter := s.newLogOutputWriter(slsFilename, useConsoleLog, loggerStdoutWriter)
 		if s.asyncLogWriter != nil {
 			internalWriter = io.MultiWriter(internalWriter, s.asyncLogWriter)
 		}
@@ -80,7 +83,7 @@ func (s *Server) initDefaultLoggers(useConsoleLog bool, logLevel wlog.LogLevel,
 	s.auditLogger = metricloggers.NewAudit2Logger(
 		audit2log.New(logWriterFn("audit")), registry)
 	s.diagLogger = metricloggers.NewDiag1Logger(diag1log.New(logWriterFn("diagnostic")), registry)
-	s.reqLogger = metricloggers.NewReq2Logger(req2log.New(logWriterFn("request"),
+	s.reqLogger = metricloggers.NewReq2Logger(requestlog.NewLogger(logWriterFn("request"),
 		req2log.Extractor(s.idsExtractor),
 		req2log.SafePathParams(s.safePathParams...),
 		req2log.SafeHeaderParams(s.safeHeaderParams...),
@@ -112,8 +115,9 @@ func (s *Server) initWrappedLoggers(useConsoleLog bool, productName, productVers
 		loggerStdoutWriter = s.loggerStdoutWriter
 	}
 
+	s.logFileWriters = nil
 	logWriterFn := func(slsFilename string) io.Writer {
-		internalWriter := newDefaultLogOutputWriter(slsFilename, useConsoleLog, loggerStdoutWriter)
+		internalWriter := s.newLogOutputWriter(slsFilename, useConsoleLog, loggerStdoutWriter)
 		return metricloggers.NewMetricWriter(internalWriter, registry, slsFilename)
 	}
 
@@ -130,7 +134,7 @@ func (s *Server) initWrappedLoggers(useConsoleLog bool, productName, productVers
 		wrapped1log.New(logWriterFn("audit"), logLevel, productName, productVersion).Audit(), registry)
 	s.diagLogger = metricloggers.NewDiag1Logger(
 		wrapped1log.New(logWriterFn("diagnostic"), logLevel, productName, productVersion).Diagnostic(), registry)
-	s.reqLogger = metricloggers.NewReq2Logger(wrapped1log.New(logWriterFn("request"), logLevel, productName, productVersion).Request(
+	s.reqLogger = metricloggers.NewReq2Logger(requestlog.NewWrappedLogger(logWriterFn("request"), productName, productVersion,
 		req2log.Extractor(s.idsExtractor),
 		req2log.SafePathParams(s.safePathParams...),
 		req2log.SafeHeaderParams(s.safeHeaderParams...),
@@ -138,6 +142,30 @@ func (s *Server) initWrappedLoggers(useConsoleLog bool, productName, productVers
 	), registry)
 }
 
+// newLogOutputWriter returns the writer provided by newDefaultLogOutputWriter. If the returned writer writes to a log
+// file, it is also recorded in s.logFileWriters so that the file can be reopened later (for example, on SIGHUP).
+func (s *Server) newLogOutputWriter(slsFilename string, logToStdout bool, stdoutWriter io.Writer) io.Writer {
+	w := newDefaultLogOutputWriter(slsFilename, logToStdout, stdoutWriter)
+	if fileWriter, ok := w.(*lumberjack.Logger); ok {
+		s.logFileWriters = append(s.logFileWriters, fileWriter)
+	}
+	return w
+}
+
+// reopenLogFiles closes all of the log files written by the server loggers. Each file is reopened (or recreated if it
+// was moved or removed) on the next write to it, which allows external tools such as logrotate to rotate the files.
+// Returns the names of the files that were closed.
+func (s *Server) reopenLogFiles() ([]string, error) {
+	var filenames []string
+	for _, fileWriter := range s.logFileWriters {
+		if err := fileWriter.Close(); err != nil {
+			return filenames, werror.Wrap(err, "failed to close log file", werror.SafeParam("filename", fileWriter.Filename))
+		}
+		filenames = append(filenames, fileWriter.Filename)
+	}
+	return filenames, nil
+}
+
 // Returns a io.Writer that can be used as the underlying writer for a logger.
 // If either logToStdout or logToStdoutBasedOnEnv() is true, then stdoutWriter is returned.
 // Otherwise, a default writer that writes to slsFilename is returned.
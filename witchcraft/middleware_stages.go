@@ -0,0 +1,247 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witchcraft
+
+import (
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/wdebug"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+// MiddlewareStage is the name of a stage of the middleware installed by the server. Request stages run on every request
+// before it is routed, while route stages run on requests that match a route after the request stages.
+type MiddlewareStage string
+
+// The built-in middleware stages in the order in which they run.
+const (
+	// MiddlewareStageRequestPanicRecovery recovers from panics in request middleware.
+	MiddlewareStageRequestPanicRecovery MiddlewareStage = "request-panic-recovery"
+	// MiddlewareStageMetricsRegistry sets the metrics registry on the request context.
+	MiddlewareStageMetricsRegistry MiddlewareStage = "metrics-registry"
+	// MiddlewareStageLoggers sets the loggers on the request context.
+	MiddlewareStageLoggers MiddlewareStage = "loggers"
+	// MiddlewareStageExtractIDs extracts the UID, SID, TokenID and trace IDs from the request and starts the root span.
+	MiddlewareStageExtractIDs MiddlewareStage = "extract-ids"
+	// MiddlewareStageHSTS sets the Strict-Transport-Security header.
+	MiddlewareStageHSTS MiddlewareStage = "hsts"
+	// MiddlewareStageCORS handles cross-origin requests.
+	MiddlewareStageCORS MiddlewareStage = "cors"
+	// MiddlewareStageUser runs the middleware provided using WithMiddleware.
+	MiddlewareStageUser MiddlewareStage = "user"
+
+	// MiddlewareStageRequestMeter records request metrics.
+	MiddlewareStageRequestMeter MiddlewareStage = "request-meter"
+	// MiddlewareStageRequestLog writes the request log.
+	MiddlewareStageRequestLog MiddlewareStage = "request-log"
+	// MiddlewareStageTraceSpan creates the span for the route.
+	MiddlewareStageTraceSpan MiddlewareStage = "trace-span"
+	// MiddlewareStageCompression compresses responses using the encoding accepted by the request.
+	MiddlewareStageCompression MiddlewareStage = "compression"
+	// MiddlewareStageRoutePanicRecovery recovers from panics in route middleware and handlers.
+	MiddlewareStageRoutePanicRecovery MiddlewareStage = "route-panic-recovery"
+	// MiddlewareStageDeprecation sets the deprecation headers of deprecated routes, records calls to them and rejects
+	// calls after their sunset if configured.
+	MiddlewareStageDeprecation MiddlewareStage = "deprecation"
+	// MiddlewareStageTimeout enforces the timeout of the route.
+	MiddlewareStageTimeout MiddlewareStage = "timeout"
+	// MiddlewareStageDecompression decompresses the bodies of requests to routes that enable decompression.
+	MiddlewareStageDecompression MiddlewareStage = "decompression"
+	// MiddlewareStageBodyLimit enforces the maximum request body size of the route.
+	MiddlewareStageBodyLimit MiddlewareStage = "body-limit"
+	// MiddlewareStageETag sets the ETags of responses and evaluates the conditional headers of requests.
+	MiddlewareStageETag MiddlewareStage = "etag"
+	// MiddlewareStageResponseCache serves the responses of routes registered with RouteCached from the response cache.
+	MiddlewareStageResponseCache MiddlewareStage = "response-cache"
+	// MiddlewareStageCoalescing coalesces concurrent identical requests to routes registered with CoalesceRequests.
+	MiddlewareStageCoalescing MiddlewareStage = "coalescing"
+)
+
+// NamedMiddleware is request or route middleware that can be installed relative to a MiddlewareStage. Exactly one of
+// Request and Route must be set. Once installed, the middleware is a stage with the provided name, so other middleware
+// can be installed relative to it.
+type NamedMiddleware struct {
+	Name    MiddlewareStage
+	Request wrouter.RequestHandlerMiddleware
+	Route   wrouter.RouteHandlerMiddleware
+}
+
+// NewNamedRequestMiddleware returns a NamedMiddleware for the provided request middleware.
+func NewNamedRequestMiddleware(name MiddlewareStage, middleware wrouter.RequestHandlerMiddleware) NamedMiddleware {
+	return NamedMiddleware{Name: name, Request: middleware}
+}
+
+// NewNamedRouteMiddleware returns a NamedMiddleware for the provided route middleware.
+func NewNamedRouteMiddleware(name MiddlewareStage, middleware wrouter.RouteHandlerMiddleware) NamedMiddleware {
+	return NamedMiddleware{Name: name, Route: middleware}
+}
+
+// WithMiddlewareBefore configures the server to install the provided middleware immediately before the provided stage.
+// The middleware must be of the same kind (request or route) as the stage. Stage configuration is applied in the order
+// in which it is provided, and Start returns an error if it cannot be applied.
+func (s *Server) WithMiddlewareBefore(stage MiddlewareStage, middleware NamedMiddleware) *Server {
+	s.middlewareStageOps = append(s.middlewareStageOps, middlewareStageOp{typ: middlewareStageOpBefore, stage: stage, middleware: middleware})
+	return s
+}
+
+// WithMiddlewareAfter configures the server to install the provided middleware immediately after the provided stage.
+// The middleware must be of the same kind (request or route) as the stage.
+func (s *Server) WithMiddlewareAfter(stage MiddlewareStage, middleware NamedMiddleware) *Server {
+	s.middlewareStageOps = append(s.middlewareStageOps, middlewareStageOp{typ: middlewareStageOpAfter, stage: stage, middleware: middleware})
+	return s
+}
+
+// WithMiddlewareReplaced configures the server to install the provided middleware instead of the middleware of the
+// provided stage. The middleware must be of the same kind (request or route) as the stage. The stage keeps its name, so
+// the name of the provided middleware is ignored.
+func (s *Server) WithMiddlewareReplaced(stage MiddlewareStage, middleware NamedMiddleware) *Server {
+	s.middlewareStageOps = append(s.middlewareStageOps, middlewareStageOp{typ: middlewareStageOpReplace, stage: stage, middleware: middleware})
+	return s
+}
+
+// WithMiddlewareDisabled configures the server to not install the middleware of the provided stage. Disabling built-in
+// stages may remove functionality that other stages depend on (for example, the request log stage requires the loggers
+// stage), so stages should only be disabled if they are replaced by equivalent middleware.
+func (s *Server) WithMiddlewareDisabled(stage MiddlewareStage) *Server {
+	s.middlewareStageOps = append(s.middlewareStageOps, middlewareStageOp{typ: middlewareStageOpDisable, stage: stage})
+	return s
+}
+
+type middlewareStageOpType string
+
+const (
+	middlewareStageOpBefore  middlewareStageOpType = "before"
+	middlewareStageOpAfter   middlewareStageOpType = "after"
+	middlewareStageOpReplace middlewareStageOpType = "replace"
+	middlewareStageOpDisable middlewareStageOpType = "disable"
+)
+
+type middlewareStageOp struct {
+	typ        middlewareStageOpType
+	stage      MiddlewareStage
+	middleware NamedMiddleware
+}
+
+// middlewareStage is a stage of the middleware installed by the server. A stage is either a request stage or a route
+// stage and may contain any number of middleware of its kind.
+type middlewareStage struct {
+	name     MiddlewareStage
+	isRoute  bool
+	custom   bool
+	replaced bool
+	disabled bool
+	request  []wrouter.RequestHandlerMiddleware
+	route    []wrouter.RouteHandlerMiddleware
+}
+
+func newRequestMiddlewareStage(name MiddlewareStage, middleware ...wrouter.RequestHandlerMiddleware) middlewareStage {
+	return middlewareStage{name: name, request: middleware}
+}
+
+func newRouteMiddlewareStage(name MiddlewareStage, middleware ...wrouter.RouteHandlerMiddleware) middlewareStage {
+	return middlewareStage{name: name, isRoute: true, route: middleware}
+}
+
+// applyMiddlewareStageOps returns the result of applying the provided operations to the provided stages in order.
+func applyMiddlewareStageOps(stages []middlewareStage, ops []middlewareStageOp) ([]middlewareStage, error) {
+	for _, op := range ops {
+		idx := -1
+		for i, stage := range stages {
+			if stage.name == op.stage {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return nil, werror.Error("unknown middleware stage",
+				werror.SafeParam("stage", op.stage),
+				werror.SafeParam("operation", op.typ))
+		}
+		if op.typ == middlewareStageOpDisable {
+			stages[idx].disabled = true
+			continue
+		}
+
+		newStage, err := op.middleware.toStage(stages, op.typ != middlewareStageOpReplace)
+		if err != nil {
+			return nil, err
+		}
+		if newStage.isRoute != stages[idx].isRoute {
+			return nil, werror.Error("middleware must be of the same kind as the middleware stage",
+				werror.SafeParam("stage", op.stage),
+				werror.SafeParam("middleware", op.middleware.Name),
+				werror.SafeParam("operation", op.typ))
+		}
+		switch op.typ {
+		case middlewareStageOpReplace:
+			newStage.name = stages[idx].name
+			newStage.custom = stages[idx].custom
+			newStage.replaced = true
+			stages[idx] = newStage
+		case middlewareStageOpAfter:
+			idx++
+			fallthrough
+		default:
+			stages = append(stages[:idx], append([]middlewareStage{newStage}, stages[idx:]...)...)
+		}
+	}
+	return stages, nil
+}
+
+// toStage returns a stage for the middleware. If checkName is true, returns an error if the middleware does not have a
+// name or has the name of one of the provided stages.
+func (m NamedMiddleware) toStage(existing []middlewareStage, checkName bool) (middlewareStage, error) {
+	if (m.Request == nil) == (m.Route == nil) {
+		return middlewareStage{}, werror.Error("exactly one of request or route middleware must be provided",
+			werror.SafeParam("middleware", m.Name))
+	}
+	if checkName {
+		if m.Name == "" {
+			return middlewareStage{}, werror.Error("inserted middleware must have a name")
+		}
+		for _, stage := range existing {
+			if stage.name == m.Name {
+				return middlewareStage{}, werror.Error("middleware stage already exists",
+					werror.SafeParam("middleware", m.Name))
+			}
+		}
+	}
+	stage := newRequestMiddlewareStage(m.Name, m.Request)
+	if m.Route != nil {
+		stage = newRouteMiddlewareStage(m.Name, m.Route)
+	}
+	stage.custom = true
+	return stage, nil
+}
+
+func middlewareStageDiagnostics(stages []middlewareStage) []wdebug.MiddlewareStageDiagnostic {
+	diagnostics := make([]wdebug.MiddlewareStageDiagnostic, 0, len(stages))
+	for _, stage := range stages {
+		diagnostic := wdebug.MiddlewareStageDiagnostic{
+			Name:     string(stage.name),
+			Kind:     wdebug.MiddlewareKindRequest,
+			Custom:   stage.custom,
+			Replaced: stage.replaced,
+			Disabled: stage.disabled,
+		}
+		if stage.isRoute {
+			diagnostic.Kind = wdebug.MiddlewareKindRoute
+			diagnostic.Middleware = wdebug.MiddlewareFuncNames(stage.route)
+		} else {
+			diagnostic.Middleware = wdebug.MiddlewareFuncNames(stage.request)
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witchcraft
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyMiddlewareStageOps(t *testing.T) {
+	reqMiddleware := NewNamedRequestMiddleware("custom-request", func(rw http.ResponseWriter, r *http.Request, next http.Handler) {
+		next.ServeHTTP(rw, r)
+	})
+	routeMiddleware := NewNamedRouteMiddleware("custom-route", func(rw http.ResponseWriter, r *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+		next(rw, r, reqVals)
+	})
+	baseStages := func() []middlewareStage {
+		return []middlewareStage{
+			newRequestMiddlewareStage(MiddlewareStageLoggers),
+			newRequestMiddlewareStage(MiddlewareStageUser),
+			newRouteMiddlewareStage(MiddlewareStageRequestLog),
+		}
+	}
+
+	for _, test := range []struct {
+		name           string
+		ops            []middlewareStageOp
+		expectedStages []MiddlewareStage
+		expectedErr    string
+	}{
+		{
+			name:           "no ops",
+			expectedStages: []MiddlewareStage{MiddlewareStageLoggers, MiddlewareStageUser, MiddlewareStageRequestLog},
+		},
+		{
+			name: "insert before and after",
+			ops: []middlewareStageOp{
+				{typ: middlewareStageOpBefore, stage: MiddlewareStageLoggers, middleware: reqMiddleware},
+				{typ: middlewareStageOpAfter, stage: MiddlewareStageRequestLog, middleware: routeMiddleware},
+			},
+			expectedStages: []MiddlewareStage{"custom-request", MiddlewareStageLoggers, MiddlewareStageUser, MiddlewareStageRequestLog, "custom-route"},
+		},
+		{
+			name: "insert relative to inserted stage",
+			ops: []middlewareStageOp{
+				{typ: middlewareStageOpAfter, stage: MiddlewareStageLoggers, middleware: reqMiddleware},
+				{typ: middlewareStageOpAfter, stage: "custom-request", middleware: NewNamedRequestMiddleware("other", reqMiddleware.Request)},
+			},
+			expectedStages: []MiddlewareStage{MiddlewareStageLoggers, "custom-request", "other", MiddlewareStageUser, MiddlewareStageRequestLog},
+		},
+		{
+			name: "replace and disable keep stages",
+			ops: []middlewareStageOp{
+				{typ: middlewareStageOpReplace, stage: MiddlewareStageUser, middleware: reqMiddleware},
+				{typ: middlewareStageOpDisable, stage: MiddlewareStageRequestLog},
+			},
+			expectedStages: []MiddlewareStage{MiddlewareStageLoggers, MiddlewareStageUser, MiddlewareStageRequestLog},
+		},
+		{
+			name:        "unknown stage",
+			ops:         []middlewareStageOp{{typ: middlewareStageOpDisable, stage: "unknown"}},
+			expectedErr: "unknown middleware stage",
+		},
+		{
+			name:        "duplicate name",
+			ops:         []middlewareStageOp{{typ: middlewareStageOpBefore, stage: MiddlewareStageUser, middleware: NewNamedRequestMiddleware(MiddlewareStageLoggers, reqMiddleware.Request)}},
+			expectedErr: "middleware stage already exists",
+		},
+		{
+			name:        "missing name",
+			ops:         []middlewareStageOp{{typ: middlewareStageOpBefore, stage: MiddlewareStageUser, middleware: NewNamedRequestMiddleware("", reqMiddleware.Request)}},
+			expectedErr: "inserted middleware must have a name",
+		},
+		{
+			name:        "kind mismatch",
+			ops:         []middlewareStageOp{{typ: middlewareStageOpBefore, stage: MiddlewareStageUser, middleware: routeMiddleware}},
+			expectedErr: "middleware must be of the same kind as the middleware stage",
+		},
+		{
+			name:        "no middleware",
+			ops:         []middlewareStageOp{{typ: middlewareStageOpReplace, stage: MiddlewareStageUser}},
+			expectedErr: "exactly one of request or route middleware must be provided",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			stages, err := applyMiddlewareStageOps(baseStages(), test.ops)
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			var names []MiddlewareStage
+			for _, stage := range stages {
+				names = append(names, stage.name)
+			}
+			assert.Equal(t, test.expectedStages, names)
+		})
+	}
+
+	t.Run("replaced and disabled stages are marked", func(t *testing.T) {
+		stages, err := applyMiddlewareStageOps(baseStages(), []middlewareStageOp{
+			{typ: middlewareStageOpReplace, stage: MiddlewareStageUser, middleware: reqMiddleware},
+			{typ: middlewareStageOpDisable, stage: MiddlewareStageRequestLog},
+		})
+		require.NoError(t, err)
+		assert.False(t, stages[0].replaced || stages[0].disabled)
+		assert.True(t, stages[1].replaced)
+		assert.False(t, stages[1].custom, "replaced built-in stages are not custom")
+		assert.Len(t, stages[1].request, 1)
+		assert.True(t, stages[2].disabled)
+	})
+}
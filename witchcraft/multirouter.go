@@ -15,8 +15,10 @@
 package witchcraft
 
 import (
+	"fmt"
 	"net/http"
 
+	werror "github.com/palantir/witchcraft-go-error"
 	"github.com/palantir/witchcraft-go-server/v2/wrouter"
 )
 
@@ -46,6 +48,14 @@ func (m *multiRouterImpl) Register(method, path string, handler http.Handler, pa
 	return m.mainRouter.Register(method, path, handler, params...)
 }
 
+func (m *multiRouterImpl) Unregister(method, path string, params ...wrouter.RouteParam) error {
+	mainRouter, ok := m.mainRouter.(wrouter.UnregisteringRouter)
+	if !ok {
+		return werror.Error("router does not support removing routes", werror.SafeParam("routerType", fmt.Sprintf("%T", m.mainRouter)))
+	}
+	return mainRouter.Unregister(method, path, params...)
+}
+
 func (m *multiRouterImpl) RegisteredRoutes() []wrouter.RouteSpec {
 	return m.mainRouter.RegisteredRoutes()
 }
@@ -74,6 +84,17 @@ func (m *multiRouterImpl) Delete(path string, handler http.Handler, params ...wr
 	return m.mainRouter.Delete(path, handler, params...)
 }
 
+func (m *multiRouterImpl) AddRouteHandlerMiddleware(handlers ...wrouter.RouteHandlerMiddleware) {
+	if mainRouter, ok := m.mainRouter.(wrouter.MiddlewareRouter); ok {
+		mainRouter.AddRouteHandlerMiddleware(handlers...)
+	}
+
+	// register middleware for the management router as well only if it differs from the main one
+	if mgmtRouter, ok := m.mgmtRouter.(wrouter.MiddlewareRouter); ok && m.mainRouter != m.mgmtRouter {
+		mgmtRouter.AddRouteHandlerMiddleware(handlers...)
+	}
+}
+
 func (m *multiRouterImpl) Subrouter(path string, params ...wrouter.RouteParam) wrouter.Router {
 	return &multiRouterImpl{
 		mainRouter: m.mainRouter.Subrouter(path, params...),
@@ -118,6 +139,10 @@ func (m *multiRootRouterImpl) ServeHTTP(rw http.ResponseWriter, req *http.Reques
 	m.mainRouter.ServeHTTP(rw, req)
 }
 
+func (m *multiRootRouterImpl) RegisteredRouteInfos() []wrouter.RouteInfo {
+	return wrouter.RegisteredRouteInfos(m.mainRouter)
+}
+
 func (m *multiRootRouterImpl) AddRequestHandlerMiddleware(handlers ...wrouter.RequestHandlerMiddleware) {
 	m.mainRouter.AddRequestHandlerMiddleware(handlers...)
 
@@ -144,3 +169,14 @@ func (m *multiRootRouterImpl) RegisterNotFoundHandler(handler http.Handler) {
 		m.mgmtRouter.RegisterNotFoundHandler(handler)
 	}
 }
+
+func (m *multiRootRouterImpl) RegisterMethodNotAllowedHandler(handler http.Handler) {
+	if mainRouter, ok := m.mainRouter.(wrouter.MethodNotAllowedRouter); ok {
+		mainRouter.RegisterMethodNotAllowedHandler(handler)
+	}
+
+	// register handler for the management router as well only if it differs from the main one
+	if mgmtRouter, ok := m.mgmtRouter.(wrouter.MethodNotAllowedRouter); ok && m.mainRouter != m.mgmtRouter {
+		mgmtRouter.RegisterMethodNotAllowedHandler(handler)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"io/ioutil"
+	"sync"
 	"time"
 
 	"github.com/palantir/pkg/refreshable"
@@ -27,10 +28,20 @@ import (
 	wparams "github.com/palantir/witchcraft-go-params"
 )
 
+// Reloader is implemented by Refreshables whose values are read from an external source and that support re-reading
+// that source on demand rather than waiting for the next periodic check.
+type Reloader interface {
+	// Reload re-reads the source of the Refreshable and updates its value if the source has changed.
+	Reload(ctx context.Context) error
+}
+
 type fileRefreshable struct {
 	innerRefreshable *refreshable.DefaultRefreshable
 
-	filePath     string
+	filePath string
+
+	// mu guards fileChecksum, which is accessed by both the polling goroutine and callers of Reload.
+	mu           sync.Mutex
 	fileChecksum [sha256.Size]byte
 }
 
@@ -82,21 +93,45 @@ func (d *fileRefreshable) watchForChanges(ctx context.Context, duration time.Dur
 }
 
 func (d *fileRefreshable) evaluateFileOnDisk(ctx context.Context) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	fileBytes, err := ioutil.ReadFile(d.filePath)
 	if err != nil {
 		svc1log.FromContext(ctx).Warn("Failed to read file bytes to update refreshable", svc1log.Stacktrace(err))
 		return
 	}
+	if err := d.updateFileBytes(ctx, fileBytes); err != nil {
+		svc1log.FromContext(ctx).Error("Failed to update refreshable with new file bytes", svc1log.Stacktrace(err))
+	}
+}
+
+// Reload reads the file on disk immediately and updates the value of the refreshable if the content of the file has
+// changed since it was last read. Returns an error if the file cannot be read or if the update fails.
+func (d *fileRefreshable) Reload(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	fileBytes, err := ioutil.ReadFile(d.filePath)
+	if err != nil {
+		return werror.WrapWithContextParams(ctx, err, "failed to read file bytes to reload refreshable", werror.SafeParam("filePath", d.filePath))
+	}
+	return d.updateFileBytes(ctx, fileBytes)
+}
+
+// updateFileBytes updates the inner refreshable with the provided bytes if their checksum differs from the checksum of
+// the current value. Callers must hold d.mu.
+func (d *fileRefreshable) updateFileBytes(ctx context.Context, fileBytes []byte) error {
 	loadedChecksum := sha256.Sum256(fileBytes)
 	if loadedChecksum == d.fileChecksum {
-		return
+		return nil
 	}
 	svc1log.FromContext(ctx).Info("Attempting to update file refreshable")
 	if err := d.innerRefreshable.Update(fileBytes); err != nil {
-		svc1log.FromContext(ctx).Error("Failed to update refreshable with new file bytes", svc1log.Stacktrace(err))
-		return
+		return err
 	}
 	d.fileChecksum = loadedChecksum
+	return nil
 }
 
 func (d *fileRefreshable) Current() interface{} {
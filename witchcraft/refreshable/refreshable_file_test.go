@@ -171,6 +171,36 @@ func TestRefreshableFileCanFollowMovingSymLink(t *testing.T) {
 	assert.Equal(t, str, "renderConf2")
 }
 
+// Verifies that Reload updates the value of a RefreshableFile without waiting for the file to be polled
+func TestRefreshableFileReload(t *testing.T) {
+	tempDir, cleanup, err := dirs.TempDir("", "")
+	require.NoError(t, err)
+	defer cleanup()
+	fileToWrite := filepath.Join(tempDir, "file")
+	writeFileHelper(t, fileToWrite, testStr1)
+	r, err := NewFileRefreshableWithDuration(context.Background(), fileToWrite, time.Hour)
+	require.NoError(t, err)
+	var count int32
+	r.Subscribe(func(interface{}) {
+		atomic.AddInt32(&count, 1)
+	})
+	reloader, ok := r.(Reloader)
+	require.True(t, ok, "file refreshable does not implement Reloader")
+
+	// reloading an unchanged file does not update the refreshable
+	require.NoError(t, reloader.Reload(context.Background()))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&count))
+
+	writeFileHelper(t, fileToWrite, testStr2)
+	require.NoError(t, reloader.Reload(context.Background()))
+	assert.Equal(t, "renderConf2", getStringFromRefreshable(t, r))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+
+	require.NoError(t, os.Remove(fileToWrite))
+	assert.Error(t, reloader.Reload(context.Background()))
+	assert.Equal(t, "renderConf2", getStringFromRefreshable(t, r))
+}
+
 func writeFileHelper(t *testing.T, path, value string) {
 	err := ioutil.WriteFile(path, []byte(value), 0644)
 	assert.NoError(t, err)
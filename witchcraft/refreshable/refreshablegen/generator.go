@@ -0,0 +1,276 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+const refreshableImportPath = "github.com/palantir/pkg/refreshable"
+
+// builtinRefreshables maps the types that have a typed refreshable in the refreshable package to the name of that type.
+var builtinRefreshables = map[string]string{
+	"string":         "String",
+	"*string":        "StringPtr",
+	"[]string":       "StringSlice",
+	"int":            "Int",
+	"*int":           "IntPtr",
+	"int64":          "Int64",
+	"*int64":         "Int64Ptr",
+	"float64":        "Float64",
+	"*float64":       "Float64Ptr",
+	"bool":           "Bool",
+	"*bool":          "BoolPtr",
+	"time.Duration":  "Duration",
+	"*time.Duration": "DurationPtr",
+}
+
+// Generate returns the source of a file in the package in dir that declares Refreshable<Type> interfaces and
+// Refreshing<Type> implementations for each of the provided types and for all of the types reachable through their
+// exported fields. Files in the package whose name is excludeFile are ignored so that a stale generated file does not
+// prevent the package from being loaded.
+func Generate(dir string, typeNames []string, excludeFile string) ([]byte, error) {
+	pkg, err := loadPackage(dir, excludeFile)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{
+		pkg:     pkg,
+		imports: map[string]string{refreshableImportPath: "refreshable"},
+		seen:    map[string]bool{},
+	}
+	for _, typeName := range typeNames {
+		obj := pkg.Scope().Lookup(typeName)
+		if obj == nil {
+			return nil, werror.Error("type not found in package",
+				werror.SafeParam("type", typeName),
+				werror.SafeParam("package", pkg.Path()))
+		}
+		if _, ok := obj.(*types.TypeName); !ok {
+			return nil, werror.Error("object is not a type",
+				werror.SafeParam("type", typeName),
+				werror.SafeParam("package", pkg.Path()))
+		}
+		g.generate(obj.Type())
+	}
+
+	out := &bytes.Buffer{}
+	_, _ = fmt.Fprintf(out, "// Generated by refreshablegen: do not edit.\n\npackage %s\n\nimport (\n", pkg.Name())
+	importPaths := make([]string, 0, len(g.imports))
+	for importPath := range g.imports {
+		importPaths = append(importPaths, importPath)
+	}
+	sort.Strings(importPaths)
+	for _, importPath := range importPaths {
+		_, _ = fmt.Fprintf(out, "\t%s %q\n", g.imports[importPath], importPath)
+	}
+	_, _ = fmt.Fprint(out, ")\n")
+	_, _ = out.Write(g.body.Bytes())
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, werror.Wrap(err, "failed to format generated source")
+	}
+	return formatted, nil
+}
+
+func loadPackage(dir, excludeFile string) (*types.Package, error) {
+	buildPkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, werror.Wrap(err, "failed to find package", werror.SafeParam("dir", dir))
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, fileName := range buildPkg.GoFiles {
+		if fileName == excludeFile {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, fileName), nil, 0)
+		if err != nil {
+			return nil, werror.Wrap(err, "failed to parse file", werror.SafeParam("file", fileName))
+		}
+		files = append(files, file)
+	}
+	cfg := &types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+	}
+	pkg, err := cfg.Check(buildPkg.ImportPath, fset, files, nil)
+	if err != nil {
+		return nil, werror.Wrap(err, "failed to type-check package", werror.SafeParam("package", buildPkg.ImportPath))
+	}
+	return pkg, nil
+}
+
+type generator struct {
+	pkg *types.Package
+	// imports maps the import path of every package referenced by the generated code to the name it is imported as.
+	imports map[string]string
+	// seen records the names of the refreshable types that have already been generated.
+	seen map[string]bool
+	body bytes.Buffer
+}
+
+type field struct {
+	name string
+	typ  types.Type
+}
+
+// generate writes the refreshable type for t followed by the refreshable types for all of the types reachable from it.
+func (g *generator) generate(t types.Type) {
+	if _, ok := builtinRefreshable(t); ok {
+		return
+	}
+	name, ok := refreshableName(t)
+	if !ok || g.seen[name] {
+		return
+	}
+	g.seen[name] = true
+
+	fields := exportedFields(t)
+	g.writeRefreshable(name, t, fields)
+
+	if ptr, ok := t.(*types.Pointer); ok {
+		g.generate(ptr.Elem())
+	}
+	for _, f := range fields {
+		g.generate(f.typ)
+	}
+}
+
+func (g *generator) writeRefreshable(name string, t types.Type, fields []field) {
+	typeStr := g.typeString(t)
+	var accessors []string
+	for _, f := range fields {
+		returnType, _ := accessorTypes(f.typ)
+		accessors = append(accessors, fmt.Sprintf("%s() %s", f.name, returnType))
+	}
+
+	w := &g.body
+	_, _ = fmt.Fprintf(w, "\ntype Refreshable%s interface {\n", name)
+	_, _ = fmt.Fprintf(w, "\trefreshable.Refreshable\n")
+	_, _ = fmt.Fprintf(w, "\tCurrent%s() %s\n", name, typeStr)
+	_, _ = fmt.Fprintf(w, "\tMap%s(func(%s) interface{}) refreshable.Refreshable\n", name, typeStr)
+	_, _ = fmt.Fprintf(w, "\tSubscribeTo%s(func(%s)) (unsubscribe func())\n", name, typeStr)
+	if len(accessors) > 0 {
+		_, _ = fmt.Fprint(w, "\n")
+		for _, accessor := range accessors {
+			_, _ = fmt.Fprintf(w, "\t%s\n", accessor)
+		}
+	}
+	_, _ = fmt.Fprint(w, "}\n")
+
+	_, _ = fmt.Fprintf(w, "\ntype Refreshing%s struct {\n\trefreshable.Refreshable\n}\n", name)
+	_, _ = fmt.Fprintf(w, "\nfunc NewRefreshing%[1]s(in refreshable.Refreshable) Refreshing%[1]s {\n\treturn Refreshing%[1]s{Refreshable: in}\n}\n", name)
+	_, _ = fmt.Fprintf(w, "\nfunc (r Refreshing%[1]s) Current%[1]s() %[2]s {\n\treturn r.Current().(%[2]s)\n}\n", name, typeStr)
+	_, _ = fmt.Fprintf(w, "\nfunc (r Refreshing%[1]s) Map%[1]s(mapFn func(%[2]s) interface{}) refreshable.Refreshable {\n"+
+		"\treturn r.Map(func(i interface{}) interface{} {\n\t\treturn mapFn(i.(%[2]s))\n\t})\n}\n", name, typeStr)
+	_, _ = fmt.Fprintf(w, "\nfunc (r Refreshing%[1]s) SubscribeTo%[1]s(consumer func(%[2]s)) (unsubscribe func()) {\n"+
+		"\treturn r.Subscribe(func(i interface{}) {\n\t\tconsumer(i.(%[2]s))\n\t})\n}\n", name, typeStr)
+
+	for _, f := range fields {
+		returnType, constructor := accessorTypes(f.typ)
+		_, _ = fmt.Fprintf(w, "\nfunc (r Refreshing%[1]s) %[3]s() %[4]s {\n"+
+			"\treturn %[5]s(r.Map%[1]s(func(i %[2]s) interface{} {\n\t\treturn i.%[3]s\n\t}))\n}\n",
+			name, typeStr, f.name, returnType, constructor)
+	}
+}
+
+// accessorTypes returns the type returned by the accessor for a field of type t and the function used to construct it.
+func accessorTypes(t types.Type) (returnType string, constructor string) {
+	if builtin, ok := builtinRefreshable(t); ok {
+		return "refreshable." + builtin, "refreshable.New" + builtin
+	}
+	name, _ := refreshableName(t)
+	return "Refreshable" + name, "NewRefreshing" + name
+}
+
+// builtinRefreshable returns the name of the type in the refreshable package that wraps t, if one exists.
+func builtinRefreshable(t types.Type) (string, bool) {
+	name, ok := builtinRefreshables[types.TypeString(t, func(pkg *types.Package) string {
+		return pkg.Path()
+	})]
+	return name, ok
+}
+
+// typeString returns the representation of t in the generated file, recording any packages it references as imports.
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg.Path() == g.pkg.Path() {
+			return ""
+		}
+		g.imports[pkg.Path()] = pkg.Name()
+		return pkg.Name()
+	})
+}
+
+// refreshableName returns the name used for the refreshable type that wraps t. Returns false if refreshables cannot be
+// generated for t.
+func refreshableName(t types.Type) (string, bool) {
+	switch typ := t.(type) {
+	case *types.Named:
+		return typ.Obj().Name(), true
+	case *types.Basic:
+		return strings.ToUpper(typ.Name()[:1]) + typ.Name()[1:], true
+	case *types.Pointer:
+		name, ok := refreshableName(typ.Elem())
+		return name + "Ptr", ok
+	case *types.Slice:
+		name, ok := refreshableName(typ.Elem())
+		return name + "Slice", ok
+	case *types.Map:
+		keyName, keyOK := refreshableName(typ.Key())
+		valName, valOK := refreshableName(typ.Elem())
+		return keyName + "To" + valName, keyOK && valOK
+	default:
+		return "", false
+	}
+}
+
+// exportedFields returns the exported fields of t if t is a struct or a pointer to a struct. Fields whose type cannot be
+// wrapped in a refreshable are omitted.
+func exportedFields(t types.Type) []field {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	structType, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	var fields []field
+	for i := 0; i < structType.NumFields(); i++ {
+		f := structType.Field(i)
+		if !f.Exported() {
+			continue
+		}
+		if _, ok := refreshableName(f.Type()); !ok {
+			continue
+		}
+		fields = append(fields, field{name: f.Name(), typ: f.Type()})
+	}
+	return fields
+}
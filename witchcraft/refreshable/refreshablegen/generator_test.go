@@ -0,0 +1,113 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerate_ConfigRuntime verifies that the generated refreshables for config.Runtime match the ones generated by
+// godel-refreshable-plugin, ignoring the header that identifies the generator.
+func TestGenerate_ConfigRuntime(t *testing.T) {
+	setVendorGoFlags(t)
+	const generatedFile = "zz_generated_refreshables.go"
+	configDir := filepath.Join("..", "..", "..", "config")
+
+	got, err := Generate(configDir, []string{"Runtime"}, generatedFile)
+	require.NoError(t, err)
+	want, err := ioutil.ReadFile(filepath.Join(configDir, generatedFile))
+	require.NoError(t, err)
+	assert.Equal(t, string(withoutHeader(want)), string(withoutHeader(got)))
+}
+
+// TestGenerate_UserType generates refreshables for a package and type-checks the package with the generated file. The
+// package is written to a directory inside of this module so that its imports resolve against the vendored modules.
+func TestGenerate_UserType(t *testing.T) {
+	setVendorGoFlags(t)
+	dir, err := ioutil.TempDir(".", "_refreshablegen")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "config.go"), []byte(`package appconfig
+
+import "time"
+
+type AppConfig struct {
+	Name     string
+	Timeout  *time.Duration
+	Limits   map[string]int
+	Backends []Backend
+	Nested   *Nested
+	internal string
+}
+
+type Backend struct {
+	URL string
+}
+
+type Nested struct {
+	Enabled bool
+	Weights []float64
+}
+`), 0644))
+	// a stale generated file must not prevent generation
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "zz_generated_refreshables.go"), []byte(`package appconfig
+
+var _ = undefinedIdentifier
+`), 0644))
+
+	require.NoError(t, run(dir, []string{"AppConfig"}, "zz_generated_refreshables.go"))
+	got, err := ioutil.ReadFile(filepath.Join(dir, "zz_generated_refreshables.go"))
+	require.NoError(t, err)
+	src := string(got)
+
+	assert.Contains(t, src, "package appconfig")
+	assert.Contains(t, src, "\tName() refreshable.String\n")
+	assert.Contains(t, src, "\tTimeout() refreshable.DurationPtr\n")
+	assert.Contains(t, src, "\tLimits() RefreshableStringToInt\n")
+	assert.Contains(t, src, "\tBackends() RefreshableBackendSlice\n")
+	assert.Contains(t, src, "\tNested() RefreshableNestedPtr\n")
+	assert.Contains(t, src, "type RefreshableNested interface {")
+	assert.Contains(t, src, "\tWeights() RefreshableFloat64Slice\n")
+	assert.Contains(t, src, "func (r RefreshingNestedPtr) Enabled() refreshable.Bool {")
+	assert.NotContains(t, src, "internal()")
+	assert.NotContains(t, src, "\"time\"")
+
+	pkg, err := loadPackage(dir, "")
+	require.NoError(t, err, "generated file does not type-check")
+	for _, name := range []string{"RefreshableAppConfig", "RefreshingAppConfig", "NewRefreshingAppConfig", "RefreshingNestedPtr"} {
+		assert.NotNil(t, pkg.Scope().Lookup(name), "generated package does not declare %s", name)
+	}
+
+	_, err = Generate(dir, []string{"Missing"}, "zz_generated_refreshables.go")
+	assert.EqualError(t, err, "type not found in package")
+}
+
+// setVendorGoFlags configures the go command invoked when resolving imports to use the vendored modules.
+func setVendorGoFlags(t *testing.T) {
+	t.Setenv("GOFLAGS", "-mod=vendor")
+}
+
+func withoutHeader(src []byte) []byte {
+	return src[bytes.IndexByte(src, '\n'):]
+}
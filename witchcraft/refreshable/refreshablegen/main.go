@@ -0,0 +1,54 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// refreshablegen generates Refreshable<Type> interfaces and Refreshing<Type> implementations for struct types in the
+// same style as the refreshables generated for the types in the config package. It is intended to be invoked using
+// "go generate" from the directory of the package that declares the types:
+//
+//	//go:generate go run github.com/palantir/witchcraft-go-server/v2/witchcraft/refreshable/refreshablegen -type AppRuntimeConfig
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typesFlag := flag.String("type", "", "comma-separated list of the names of the types to generate refreshables for")
+	outputFlag := flag.String("output", "zz_generated_refreshables.go", "name of the generated file")
+	dirFlag := flag.String("dir", ".", "directory of the package that declares the types")
+	flag.Parse()
+
+	if *typesFlag == "" {
+		_, _ = fmt.Fprintln(os.Stderr, "refreshablegen: -type must be specified")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*dirFlag, strings.Split(*typesFlag, ","), *outputFlag); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "refreshablegen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir string, typeNames []string, output string) error {
+	src, err := Generate(dir, typeNames, output)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, output), src, 0644)
+}
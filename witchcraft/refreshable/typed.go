@@ -0,0 +1,140 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package refreshable
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/palantir/pkg/refreshable"
+	werror "github.com/palantir/witchcraft-go-error"
+)
+
+// Typed is a refreshable value of type T. It provides the same functionality as refreshable.Refreshable without
+// requiring callers to cast values.
+type Typed[T any] interface {
+	// Current returns the current value.
+	Current() T
+
+	// Subscribe registers the provided consumer to be called with the new value whenever the value changes. Updates
+	// that result in a value that is deeply equal to the current value do not invoke subscribers.
+	Subscribe(consumer func(T)) (unsubscribe func())
+}
+
+// DefaultTyped is a Typed whose value is set using Update.
+type DefaultTyped[T any] struct {
+	// updateMu serializes calls to Update so that subscribers observe updates in order. It is held while subscribers
+	// are called, so subscribers must not call Update on the same refreshable.
+	updateMu sync.Mutex
+
+	mu          sync.RWMutex // protects current and subscribers
+	current     T
+	subscribers []*func(T)
+}
+
+// NewDefaultTyped returns a new DefaultTyped with the provided initial value.
+func NewDefaultTyped[T any](val T) *DefaultTyped[T] {
+	return &DefaultTyped[T]{
+		current: val,
+	}
+}
+
+// Update sets the value of the refreshable to the provided value and calls all of the subscribers with it. Does
+// nothing if the provided value is deeply equal to the current value. Subscribers are called without holding the lock
+// that protects the value and the subscribers, so they may call Current, Subscribe and unsubscribe.
+func (d *DefaultTyped[T]) Update(val T) {
+	d.updateMu.Lock()
+	defer d.updateMu.Unlock()
+
+	d.mu.Lock()
+	if reflect.DeepEqual(d.current, val) {
+		d.mu.Unlock()
+		return
+	}
+	d.current = val
+	subscribers := make([]*func(T), len(d.subscribers))
+	copy(subscribers, d.subscribers)
+	d.mu.Unlock()
+
+	for _, sub := range subscribers {
+		(*sub)(val)
+	}
+}
+
+func (d *DefaultTyped[T]) Current() T {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.current
+}
+
+func (d *DefaultTyped[T]) Subscribe(consumer func(T)) (unsubscribe func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	consumerFnPtr := &consumer
+	d.subscribers = append(d.subscribers, consumerFnPtr)
+	return func() {
+		d.unsubscribe(consumerFnPtr)
+	}
+}
+
+func (d *DefaultTyped[T]) unsubscribe(consumerFnPtr *func(T)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for idx, currSub := range d.subscribers {
+		if currSub == consumerFnPtr {
+			// copy rather than modify in place so that slices copied by Update are not modified
+			subscribers := make([]*func(T), 0, len(d.subscribers)-1)
+			subscribers = append(subscribers, d.subscribers[:idx]...)
+			d.subscribers = append(subscribers, d.subscribers[idx+1:]...)
+			return
+		}
+	}
+}
+
+// NewTyped returns a Typed whose value tracks the value of the provided Refreshable. Returns an error if the current
+// value of the provided Refreshable is not of type T. Subsequent updates whose value is not of type T are ignored.
+//
+// The returned Typed remains subscribed to the provided Refreshable for as long as the Refreshable exists, so it is
+// intended to be created once (typically during server initialization) rather than repeatedly.
+func NewTyped[T any](in refreshable.Refreshable) (Typed[T], error) {
+	current, ok := in.Current().(T)
+	if !ok {
+		return nil, werror.Error("refreshable value is not of the requested type",
+			werror.SafeParam("valueType", fmt.Sprintf("%T", in.Current())),
+			werror.SafeParam("requestedType", reflect.TypeOf((*T)(nil)).Elem().String()))
+	}
+	out := NewDefaultTyped(current)
+	in.Subscribe(func(updated interface{}) {
+		if val, ok := updated.(T); ok {
+			out.Update(val)
+		}
+	})
+	return out, nil
+}
+
+// MapTyped returns a Typed whose value is the result of applying mapFn to the value of the provided Typed. The
+// returned Typed is updated whenever the provided one is, but its subscribers are only called when the mapped value
+// changes. Like NewTyped, the returned Typed remains subscribed to the provided one, so it is intended to be created
+// once rather than repeatedly.
+func MapTyped[T any, U any](in Typed[T], mapFn func(T) U) Typed[U] {
+	out := NewDefaultTyped(mapFn(in.Current()))
+	in.Subscribe(func(updated T) {
+		out.Update(mapFn(updated))
+	})
+	return out
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package refreshable
+
+import (
+	"testing"
+
+	"github.com/palantir/pkg/refreshable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testConfig struct {
+	Name  string
+	Count int
+	Tags  []string
+}
+
+func TestNewTyped(t *testing.T) {
+	in := refreshable.NewDefaultRefreshable(testConfig{Name: "foo", Count: 1})
+	typed, err := NewTyped[testConfig](in)
+	require.NoError(t, err)
+	assert.Equal(t, testConfig{Name: "foo", Count: 1}, typed.Current())
+
+	var updates []testConfig
+	typed.Subscribe(func(cfg testConfig) {
+		updates = append(updates, cfg)
+	})
+	require.NoError(t, in.Update(testConfig{Name: "bar", Count: 1}))
+	assert.Equal(t, testConfig{Name: "bar", Count: 1}, typed.Current())
+	assert.Equal(t, []testConfig{{Name: "bar", Count: 1}}, updates)
+}
+
+func TestNewTyped_WrongType(t *testing.T) {
+	_, err := NewTyped[testConfig](refreshable.NewDefaultRefreshable("foo"))
+	require.EqualError(t, err, "refreshable value is not of the requested type")
+}
+
+func TestMapTyped(t *testing.T) {
+	in := NewDefaultTyped(testConfig{Name: "foo", Count: 1, Tags: []string{"a"}})
+	name := MapTyped[testConfig](in, func(cfg testConfig) string {
+		return cfg.Name
+	})
+	tags := MapTyped[testConfig](in, func(cfg testConfig) []string {
+		return cfg.Tags
+	})
+	assert.Equal(t, "foo", name.Current())
+
+	var nameUpdates []string
+	name.Subscribe(func(name string) {
+		nameUpdates = append(nameUpdates, name)
+	})
+	var tagUpdates [][]string
+	unsubscribe := tags.Subscribe(func(tags []string) {
+		tagUpdates = append(tagUpdates, tags)
+	})
+
+	// changing a different field does not call subscribers of the mapped refreshables
+	in.Update(testConfig{Name: "foo", Count: 2, Tags: []string{"a"}})
+	assert.Empty(t, nameUpdates)
+	assert.Empty(t, tagUpdates)
+
+	in.Update(testConfig{Name: "bar", Count: 2, Tags: []string{"a", "b"}})
+	assert.Equal(t, "bar", name.Current())
+	assert.Equal(t, []string{"bar"}, nameUpdates)
+	assert.Equal(t, [][]string{{"a", "b"}}, tagUpdates)
+
+	unsubscribe()
+	in.Update(testConfig{Name: "bar", Count: 2, Tags: []string{"c"}})
+	assert.Equal(t, []string{"c"}, tags.Current())
+	assert.Equal(t, [][]string{{"a", "b"}}, tagUpdates)
+}
+
+func TestDefaultTyped_SubscriberCallsRefreshable(t *testing.T) {
+	typed := NewDefaultTyped(1)
+	var seen []int
+	var unsubscribe func()
+	unsubscribe = typed.Subscribe(func(int) {
+		// calling the refreshable from a subscriber must not deadlock
+		seen = append(seen, typed.Current())
+		typed.Subscribe(func(int) {})
+		unsubscribe()
+	})
+	typed.Update(2)
+	typed.Update(3)
+	assert.Equal(t, []int{2}, seen)
+}
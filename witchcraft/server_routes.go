@@ -25,10 +25,12 @@ import (
 	"github.com/palantir/pkg/metrics"
 	werror "github.com/palantir/witchcraft-go-error"
 	healthstatus "github.com/palantir/witchcraft-go-health/status"
+	wparams "github.com/palantir/witchcraft-go-params"
 	"github.com/palantir/witchcraft-go-server/v2/config"
 	"github.com/palantir/witchcraft-go-server/v2/status/routes"
 	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
 	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/wdebug"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wopenapi"
 	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wresource"
 	"github.com/palantir/witchcraft-go-server/v2/wrouter"
 	"github.com/palantir/witchcraft-go-tracing/wtracing"
@@ -43,15 +45,34 @@ func (s *Server) initRouters(installCfg config.Install) (rRouter wrouter.Router,
 	return routerWithContextPath, mgmtRouterWithContextPath
 }
 
-func (s *Server) addRoutes(mgmtRouterWithContextPath wrouter.Router, runtimeCfg config.RefreshableRuntime) error {
+func (s *Server) addRoutes(routerWithContextPath, mgmtRouterWithContextPath wrouter.Router, installCfg config.Install, runtimeCfg config.RefreshableRuntime) error {
 	// add debugging endpoints to management router
 	if err := addPprofRoutes(mgmtRouterWithContextPath); err != nil {
 		return werror.Wrap(err, "failed to register debugging routes")
 	}
-	if err := wdebug.RegisterRoute(mgmtRouterWithContextPath, runtimeCfg.DiagnosticsConfig().DebugSharedSecret()); err != nil {
+	if err := wdebug.RegisterRoute(
+		mgmtRouterWithContextPath,
+		runtimeCfg.DiagnosticsConfig().DebugSharedSecret(),
+		wdebug.NewRoutesDiagnosticHandler(routerWithContextPath.RootRouter(), mgmtRouterWithContextPath.RootRouter()),
+		wdebug.NewMiddlewareDiagnosticHandler(middlewareStageDiagnostics(s.middlewareStages)),
+	); err != nil {
 		return err
 	}
 
+	if err := wdebug.RegisterPurgeResponseCacheRoute(
+		mgmtRouterWithContextPath,
+		runtimeCfg.DiagnosticsConfig().DebugSharedSecret(),
+		s.responseCache.Purge,
+	); err != nil {
+		return err
+	}
+
+	if s.openAPIInfo != nil {
+		if err := addOpenAPIRoute(mgmtRouterWithContextPath, routerWithContextPath.RootRouter(), *s.openAPIInfo, installCfg); err != nil {
+			return werror.Wrap(err, "failed to register OpenAPI route")
+		}
+	}
+
 	statusResource := wresource.New("status", mgmtRouterWithContextPath)
 
 	// add health endpoints
@@ -82,46 +103,82 @@ func (s *Server) addRoutes(mgmtRouterWithContextPath wrouter.Router, runtimeCfg
 	return nil
 }
 
-func (s *Server) addMiddleware(rootRouter wrouter.RootRouter, registry metrics.RootRegistry, tracerOptions []wtracing.TracerOption) {
-	rootRouter.AddRequestHandlerMiddleware(
+// addMiddleware adds the middleware stages configured for the server and the not found and method not allowed handlers
+// to the provided router. Returns the stages that were added.
+func (s *Server) addMiddleware(rootRouter wrouter.RootRouter, registry metrics.RootRegistry, tracerOptions []wtracing.TracerOption, runtimeCfg config.RefreshableRuntime) ([]middlewareStage, error) {
+	stages, err := applyMiddlewareStageOps([]middlewareStage{
 		// add middleware that recovers from panics in request middleware
-		middleware.NewRequestPanicRecovery(s.svcLogger, s.evtLogger),
+		newRequestMiddlewareStage(MiddlewareStageRequestPanicRecovery, middleware.NewRequestPanicRecovery(s.svcLogger, s.evtLogger)),
 		// add middleware that injects metrics registry into request context
-		middleware.NewRequestContextMetricsRegistry(registry),
+		newRequestMiddlewareStage(MiddlewareStageMetricsRegistry, middleware.NewRequestContextMetricsRegistry(registry)),
 		// add middleware that injects loggers into request context
-		middleware.NewRequestContextLoggers(
+		newRequestMiddlewareStage(MiddlewareStageLoggers, middleware.NewRequestContextLoggers(
 			s.svcLogger,
 			s.evtLogger,
 			s.auditLogger,
 			s.metricLogger,
 			s.diagLogger,
 			s.reqLogger,
-		),
+		)),
 		// add middleware that extracts UID, SID, and TokenID into context for loggers, sets a tracer on the context and
 		// starts a root span and sets it on the context.
-		middleware.NewRequestExtractIDs(
+		newRequestMiddlewareStage(MiddlewareStageExtractIDs, middleware.NewRequestExtractIDs(
 			s.svcLogger,
 			s.trcLogger,
 			tracerOptions,
 			s.idsExtractor,
-		),
-	)
-
-	// add middleware that records HTTP request stats as metrics in registry
-	rootRouter.AddRouteHandlerMiddleware(middleware.NewRequestMetricRequestMeter(registry))
-
-	// add middleware to enforce setting HSTS headers per RFC 6797
-	rootRouter.AddRequestHandlerMiddleware(middleware.NewStrictTransportSecurityHeader())
+		)),
+		// add middleware to enforce setting HSTS headers per RFC 6797
+		newRequestMiddlewareStage(MiddlewareStageHSTS, middleware.NewStrictTransportSecurityHeader()),
+		// add middleware that handles cross-origin requests as specified by the runtime configuration
+		newRequestMiddlewareStage(MiddlewareStageCORS, middleware.NewCORS(runtimeCfg.CORS(), rootRouter.RegisteredRoutes)),
+		// add user-provided middleware
+		newRequestMiddlewareStage(MiddlewareStageUser, s.handlers...),
 
-	// add user-provided middleware
-	rootRouter.AddRequestHandlerMiddleware(s.handlers...)
-
-	// add route middleware
-	rootRouter.AddRouteHandlerMiddleware(middleware.NewRouteRequestLog())
-	rootRouter.AddRouteHandlerMiddleware(middleware.NewRouteLogTraceSpan())
-
-	// add a second, inner panic recovery middleware so panics within handler logic are correctly configured with logging, trace IDs, etc.
-	rootRouter.AddRouteHandlerMiddleware(middleware.NewRoutePanicRecovery())
+		// add middleware that records HTTP request stats as metrics in registry
+		newRouteMiddlewareStage(MiddlewareStageRequestMeter, middleware.NewRequestMetricRequestMeter(registry)),
+		newRouteMiddlewareStage(MiddlewareStageRequestLog, middleware.NewRouteRequestLog()),
+		newRouteMiddlewareStage(MiddlewareStageTraceSpan, middleware.NewRouteLogTraceSpan()),
+		// add middleware that compresses responses. Runs within the request meter and request log middleware so that
+		// they record the size of the compressed response.
+		newRouteMiddlewareStage(MiddlewareStageCompression, middleware.NewRouteCompression(
+			runtimeCfg.Requests().Compression().Enabled(),
+			runtimeCfg.Requests().Compression().MinSize(),
+			registry,
+		)),
+		// add a second, inner panic recovery middleware so panics within handler logic are correctly configured with logging, trace IDs, etc.
+		newRouteMiddlewareStage(MiddlewareStageRoutePanicRecovery, middleware.NewRoutePanicRecovery()),
+		// add middleware that handles calls to deprecated routes. Runs outside of the timeout middleware so that the
+		// deprecation headers are set on responses to requests that time out.
+		newRouteMiddlewareStage(MiddlewareStageDeprecation, middleware.NewRouteDeprecation(registry)),
+		// add middleware that enforces route timeouts. Runs within the inner panic recovery middleware so that panics in
+		// handlers that run with a timeout are recovered.
+		newRouteMiddlewareStage(MiddlewareStageTimeout, middleware.NewRouteTimeout(runtimeCfg.Requests().Timeout(), registry)),
+		// add middleware that decompresses request bodies. Runs outside of the body limit middleware so that maximum
+		// request body sizes apply to the decompressed bodies.
+		newRouteMiddlewareStage(MiddlewareStageDecompression, middleware.NewRouteDecompression(runtimeCfg.Requests().Decompression().Enabled(), runtimeCfg.Requests().Decompression().MaxSize(), registry)),
+		// add middleware that enforces maximum request body sizes
+		newRouteMiddlewareStage(MiddlewareStageBodyLimit, middleware.NewRouteBodyLimit(runtimeCfg.Requests().MaxBodySize(), registry)),
+		// add middleware that handles ETags and conditional requests. Runs within the compression middleware so that
+		// entity tags are computed over uncompressed responses.
+		newRouteMiddlewareStage(MiddlewareStageETag, middleware.NewRouteETag()),
+		// add middleware that caches responses. Runs within the ETag middleware so that conditional requests are
+		// evaluated against cached responses.
+		newRouteMiddlewareStage(MiddlewareStageResponseCache, s.responseCache.Middleware()),
+		// add middleware that coalesces concurrent identical requests. Runs within the response cache middleware so that
+		// requests that miss the cache at the same time run the handler once.
+		newRouteMiddlewareStage(MiddlewareStageCoalescing, middleware.NewRouteCoalescing(registry)),
+	}, s.middlewareStageOps)
+	if err != nil {
+		return nil, werror.Wrap(err, "failed to configure middleware stages")
+	}
+	for _, stage := range stages {
+		if stage.disabled {
+			continue
+		}
+		rootRouter.AddRequestHandlerMiddleware(stage.request...)
+		rootRouter.AddRouteHandlerMiddleware(stage.route...)
+	}
 
 	// add not found handler
 	rootRouter.RegisterNotFoundHandler(httpserver.NewJSONHandler(
@@ -129,6 +186,19 @@ func (s *Server) addMiddleware(rootRouter wrouter.RootRouter, registry metrics.R
 			return werror.Convert(errors.NewNotFound())
 		}, httpserver.StatusCodeMapper, httpserver.ErrHandler),
 	)
+
+	// add method not allowed handler
+	if methodNotAllowedRouter, ok := rootRouter.(wrouter.MethodNotAllowedRouter); ok {
+		methodNotAllowedRouter.RegisterMethodNotAllowedHandler(http.HandlerFunc(writeMethodNotAllowed))
+	}
+	return stages, nil
+}
+
+// methodNotAllowedErrorType is the type of the error returned for requests whose method is not allowed for the path.
+var methodNotAllowedErrorType = middleware.NewStatusErrorType("Witchcraft:MethodNotAllowed", http.StatusMethodNotAllowed)
+
+func writeMethodNotAllowed(rw http.ResponseWriter, req *http.Request) {
+	methodNotAllowedErrorType.Write(req.Context(), rw, nil, wparams.NewSafeParam("method", req.Method))
 }
 
 func createRouter(routerImpl wrouter.RouterImpl, ctxPath string) wrouter.Router {
@@ -143,6 +213,27 @@ func createRouter(routerImpl wrouter.RouterImpl, ctxPath string) wrouter.Router
 	return routerWithContextPath
 }
 
+// addOpenAPIRoute registers a route on the provided router that serves an OpenAPI document describing the routes that
+// are registered on documentedRouter when the document is requested.
+func addOpenAPIRoute(router wrouter.Router, documentedRouter wrouter.RootRouter, info wopenapi.Info, installCfg config.Install) error {
+	if info.Title == "" {
+		info.Title = installCfg.ProductName
+	}
+	if info.Version == "" {
+		info.Version = installCfg.ProductVersion
+	}
+	return wresource.New("openapi", router).Get("getOpenAPIDocument", "/openapi",
+		httpserver.NewJSONHandler(func(rw http.ResponseWriter, req *http.Request) error {
+			doc, err := wopenapi.Generate(info, wrouter.RegisteredRouteInfos(documentedRouter))
+			if err != nil {
+				return werror.WrapWithContextParams(req.Context(), err, "failed to generate OpenAPI document")
+			}
+			httpserver.WriteJSONResponse(rw, doc, http.StatusOK)
+			return nil
+		}, httpserver.StatusCodeMapper, httpserver.ErrHandler),
+	)
+}
+
 func addPprofRoutes(router wrouter.Router) error {
 	debugger := wresource.New("debug", router.Subrouter("/debug"))
 	if err := debugger.Get("pprofIndex", "/pprof/", http.HandlerFunc(netpprof.Index)); err != nil {
@@ -0,0 +1,74 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witchcraft
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/palantir/witchcraft-go-logging/wlog"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// TestSigHupReopensLogFiles verifies that the log files written by the server are reopened on SIGHUP so that writes
+// made after the files are renamed (for example, by logrotate) go to new files at the original paths. This test is
+// internal because the server only writes log files when it is not running in a container.
+func TestSigHupReopensLogFiles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logPath := filepath.Join(t.TempDir(), "service.log")
+	fileWriter := &lumberjack.Logger{Filename: logPath}
+	defer func() {
+		_ = fileWriter.Close()
+	}()
+	s := &Server{
+		disableSigHupConfigReloadHandler: true,
+		logFileWriters:                   []*lumberjack.Logger{fileWriter},
+		svcLogger:                        svc1log.New(fileWriter, wlog.InfoLevel),
+	}
+	s.initSigHupHandler(ctx)
+
+	s.svcLogger.Info("before rotation")
+	rotatedPath := logPath + ".1"
+	require.NoError(t, os.Rename(logPath, rotatedPath))
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	require.Eventually(t, func() bool {
+		content, err := os.ReadFile(logPath)
+		return err == nil && len(content) > 0
+	}, 5*time.Second, 10*time.Millisecond, "log file was not recreated after SIGHUP")
+	s.svcLogger.Info("after rotation")
+
+	rotatedContent, err := os.ReadFile(rotatedPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(rotatedContent), "before rotation")
+	assert.NotContains(t, string(rotatedContent), "after rotation")
+	assert.NotContains(t, string(rotatedContent), "Handled SIGHUP signal.")
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "before rotation")
+	assert.Contains(t, string(content), "Handled SIGHUP signal.")
+	assert.Contains(t, string(content), logPath)
+	assert.Contains(t, string(content), "after rotation")
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witchcraft
+
+import (
+	"fmt"
+	"reflect"
+
+	werror "github.com/palantir/witchcraft-go-error"
+	refreshablefile "github.com/palantir/witchcraft-go-server/v2/witchcraft/refreshable"
+)
+
+// InstallConfig returns the install configuration in the provided InitInfo as a T. T must be the type of the struct
+// provided to WithInstallConfigType (or config.Install if WithInstallConfigType was not called). Returns an error if
+// the install configuration is not a T.
+func InstallConfig[T any](info InitInfo) (T, error) {
+	installCfg, ok := info.InstallConfig.(T)
+	if !ok {
+		return installCfg, werror.Error("install configuration is not of the requested type",
+			werror.SafeParam("installConfigType", fmt.Sprintf("%T", info.InstallConfig)),
+			werror.SafeParam("requestedType", reflect.TypeOf((*T)(nil)).Elem().String()))
+	}
+	return installCfg, nil
+}
+
+// RuntimeConfig returns the runtime configuration in the provided InitInfo as a refreshable of T. T must be the type of
+// the struct provided to WithRuntimeConfigType (or config.Runtime if WithRuntimeConfigType was not called). Returns an
+// error if the runtime configuration is not a T.
+//
+// Use refreshable.MapTyped to derive refreshables for individual fields of the configuration: subscribers of the
+// derived refreshables are only called when the value of the field changes. The returned refreshable remains subscribed
+// to the runtime configuration for the lifetime of the server, so it should be created once in the initialization
+// function rather than per request.
+func RuntimeConfig[T any](info InitInfo) (refreshablefile.Typed[T], error) {
+	runtimeCfg, err := refreshablefile.NewTyped[T](info.RuntimeConfig)
+	if err != nil {
+		return nil, werror.Wrap(err, "failed to create typed runtime configuration refreshable")
+	}
+	return runtimeCfg, nil
+}
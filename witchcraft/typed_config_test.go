@@ -0,0 +1,63 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witchcraft_test
+
+import (
+	"testing"
+
+	"github.com/palantir/pkg/refreshable"
+	"github.com/palantir/witchcraft-go-server/v2/config"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft"
+	refreshablefile "github.com/palantir/witchcraft-go-server/v2/witchcraft/refreshable"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRuntimeConfig struct {
+	config.Runtime `yaml:",inline"`
+	Message        string `yaml:"message"`
+}
+
+func TestRuntimeConfig(t *testing.T) {
+	runtimeCfg := refreshable.NewDefaultRefreshable(testRuntimeConfig{Message: "hello"})
+	info := witchcraft.InitInfo{
+		InstallConfig: config.Install{ProductName: "product"},
+		RuntimeConfig: runtimeCfg,
+	}
+
+	installCfg, err := witchcraft.InstallConfig[config.Install](info)
+	require.NoError(t, err)
+	assert.Equal(t, "product", installCfg.ProductName)
+
+	typedRuntimeCfg, err := witchcraft.RuntimeConfig[testRuntimeConfig](info)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", typedRuntimeCfg.Current().Message)
+
+	message := refreshablefile.MapTyped(typedRuntimeCfg, func(cfg testRuntimeConfig) string {
+		return cfg.Message
+	})
+	var messages []string
+	message.Subscribe(func(msg string) {
+		messages = append(messages, msg)
+	})
+	require.NoError(t, runtimeCfg.Update(testRuntimeConfig{Message: "hello", Runtime: config.Runtime{HealthChecks: config.HealthChecksConfig{SharedSecret: "secret"}}}))
+	require.NoError(t, runtimeCfg.Update(testRuntimeConfig{Message: "goodbye"}))
+	assert.Equal(t, []string{"goodbye"}, messages)
+
+	_, err = witchcraft.RuntimeConfig[config.Runtime](info)
+	assert.Error(t, err)
+	_, err = witchcraft.InstallConfig[testRuntimeConfig](info)
+	assert.Error(t, err)
+}
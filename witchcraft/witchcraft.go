@@ -53,12 +53,15 @@ import (
 	"github.com/palantir/witchcraft-go-server/v2/config"
 	"github.com/palantir/witchcraft-go-server/v2/status"
 	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/dependencyhealth"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
 	refreshablehealth "github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/refreshable"
 	refreshablefile "github.com/palantir/witchcraft-go-server/v2/witchcraft/refreshable"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wopenapi"
 	"github.com/palantir/witchcraft-go-server/v2/wrouter"
 	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
 	"github.com/palantir/witchcraft-go-tracing/wtracing"
 	"github.com/palantir/witchcraft-go-tracing/wzipkin"
+	"gopkg.in/natefinch/lumberjack.v2"
 	"gopkg.in/yaml.v2"
 	yamlv3 "gopkg.in/yaml.v3"
 
@@ -72,6 +75,17 @@ type Server struct {
 	// will have the appropriate loggers and logger parameters set.
 	handlers []wrouter.RequestHandlerMiddleware
 
+	// middlewareStageOps specifies the operations that insert, replace or disable middleware stages in the order in
+	// which they were configured.
+	middlewareStageOps []middlewareStageOp
+
+	// middlewareStages stores the middleware stages installed on the main router. Set when the middleware is added.
+	middlewareStages []middlewareStage
+
+	// responseCache stores the responses of routes registered with RouteCached. It is shared by the main and the
+	// management router and is set when the middleware is added.
+	responseCache *middleware.ResponseCache
+
 	// useSelfSignedServerCertificate specifies whether the server uses a dynamically generated self-signed certificate
 	// for TLS. No verification mechanism is provided for the self-signed certificate, so clients can only connect to a
 	// server using this mode in an untrusted manner. As such, this option should only be used in very specialized
@@ -96,6 +110,12 @@ type Server struct {
 	// if true, disables the default behavior of shutting down the server on SIGTERM and SIGINT signals.
 	disableShutdownSignalHandler bool
 
+	// if true, disables the default behavior of immediately reloading the runtime configuration on SIGHUP signals.
+	disableSigHupConfigReloadHandler bool
+
+	// if true, disables the default behavior of reopening log files on SIGHUP signals.
+	disableSigHupLogReopenHandler bool
+
 	// provides the bytes for the install configuration for the server. If nil, a default configuration provider that
 	// reads the file at "var/conf/install.yml" is used.
 	installConfigProvider ConfigBytesProvider
@@ -106,6 +126,10 @@ type Server struct {
 	// Refreshable is "[]byte", where the byte slice is the contents of the runtime configuration file.
 	runtimeConfigProvider func(ctx context.Context) (refreshable.Refreshable, error)
 
+	// the Refreshable returned by runtimeConfigProvider. Stored so that a reload can be requested on SIGHUP if the
+	// Refreshable implements refreshablefile.Reloader.
+	runtimeConfigSource refreshable.Refreshable
+
 	// specifies the source used to provide the readiness information for the server. If nil, a default value that uses
 	// the server's status is used.
 	readinessSource healthstatus.Source
@@ -120,6 +144,10 @@ type Server struct {
 	// specifies the handlers to invoke upon health status changes. The LoggingHealthStatusChangeHandler is added by default.
 	healthStatusChangeHandlers []status.HealthStatusChangeHandler
 
+	// if non-nil, an OpenAPI document describing the routes of the main server is served on the management server using
+	// this info.
+	openAPIInfo *wopenapi.Info
+
 	// if true, disables the SERVICE_DEPENDENCY health check.
 	disableServiceDependencyHealth bool
 
@@ -216,6 +244,9 @@ type Server struct {
 	// nil if not enabled
 	asyncLogWriter tcpjson.AsyncWriter
 
+	// the file-based writers used by the loggers. Empty if the loggers write to stdout.
+	logFileWriters []*lumberjack.Logger
+
 	// the http.Server for the main server
 	httpServer *http.Server
 
@@ -477,6 +508,19 @@ func (s *Server) WithDisableShutdownSignalHandler() *Server {
 	return s
 }
 
+// WithDisableSigHupConfigReloadHandler disables the server's enabled-by-default reload of the runtime configuration on
+// SIGHUP.
+func (s *Server) WithDisableSigHupConfigReloadHandler() *Server {
+	s.disableSigHupConfigReloadHandler = true
+	return s
+}
+
+// WithDisableSigHupLogReopenHandler disables the server's enabled-by-default reopening of log files on SIGHUP.
+func (s *Server) WithDisableSigHupLogReopenHandler() *Server {
+	s.disableSigHupLogReopenHandler = true
+	return s
+}
+
 // WithDisableKeepAlives disables keep-alives on the server by calling SetKeepAlivesEnabled(false) on the http.Server
 // used by the server. Note that this setting is only applied to the main server -- if the management server is separate
 // from the main server, this setting is not applied to the management server. Refer to the documentation for
@@ -547,6 +591,15 @@ func (s *Server) WithHealthStatusChangeHandlers(handlers ...status.HealthStatusC
 	return s
 }
 
+// WithOpenAPI configures the server to serve an OpenAPI document that describes the routes registered on the main server
+// at "/openapi" on the management server. The document is generated when it is requested using the documentation
+// registered for routes using wrouter.RouteDocumentation. If the title or version of the provided info are empty, the
+// product name and version from the install configuration are used.
+func (s *Server) WithOpenAPI(info wopenapi.Info) *Server {
+	s.openAPIInfo = &info
+	return s
+}
+
 const (
 	defaultMetricEmitFrequency = time.Second * 60
 
@@ -683,10 +736,17 @@ func (s *Server) Start() (rErr error) {
 	router, mgmtRouter := s.initRouters(baseInstallCfg)
 
 	// add middleware
-	s.addMiddleware(router.RootRouter(), metricsRegistry, s.getApplicationTracingOptions(baseInstallCfg))
+	s.responseCache = middleware.NewResponseCache(baseRefreshableRuntimeCfg.Requests().Cache(), metricsRegistry)
+	stages, err := s.addMiddleware(router.RootRouter(), metricsRegistry, s.getApplicationTracingOptions(baseInstallCfg), baseRefreshableRuntimeCfg)
+	if err != nil {
+		return err
+	}
+	s.middlewareStages = stages
 	if mgmtRouter != router {
 		// add middleware to management router as well if it is distinct
-		s.addMiddleware(mgmtRouter.RootRouter(), metricsRegistry, s.getManagementTracingOptions(baseInstallCfg))
+		if _, err := s.addMiddleware(mgmtRouter.RootRouter(), metricsRegistry, s.getManagementTracingOptions(baseInstallCfg), baseRefreshableRuntimeCfg); err != nil {
+			return err
+		}
 	}
 
 	// handle built-in runtime config changes
@@ -699,6 +759,7 @@ func (s *Server) Start() (rErr error) {
 
 	s.initStackTraceHandler(ctx)
 	s.initShutdownSignalHandler(ctx)
+	s.initSigHupHandler(ctx)
 
 	// wait for s.Close() or s.Shutdown() to return if called
 	defer s.shutdownFinished.Wait()
@@ -750,7 +811,7 @@ func (s *Server) Start() (rErr error) {
 
 	// add routes for health, liveness and readiness. Must be done after initFn to ensure that any
 	// health/liveness/readiness configuration updated by initFn is applied.
-	if err := s.addRoutes(mgmtRouter, baseRefreshableRuntimeCfg); err != nil {
+	if err := s.addRoutes(router, mgmtRouter, baseInstallCfg, baseRefreshableRuntimeCfg); err != nil {
 		return err
 	}
 
@@ -852,6 +913,7 @@ func (s *Server) initRuntimeConfig(ctx context.Context) (rBaseCfg config.Refresh
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	s.runtimeConfigSource = runtimeConfigProvider
 
 	runtimeConfigProvider = runtimeConfigProvider.Map(func(cfgBytesVal interface{}) interface{} {
 		cfgBytes, err := s.decryptConfigBytes(cfgBytesVal.([]byte))
@@ -1005,6 +1067,52 @@ func (s *Server) initShutdownSignalHandler(ctx context.Context) {
 	})
 }
 
+func (s *Server) initSigHupHandler(ctx context.Context) {
+	if s.disableSigHupConfigReloadHandler && s.disableSigHupLogReopenHandler {
+		return
+	}
+
+	sigHupSignal := make(chan os.Signal, 1)
+	signal.Notify(sigHupSignal, syscall.SIGHUP)
+
+	go wapp.RunWithRecoveryLogging(ctx, func(ctx context.Context) {
+		defer signal.Stop(sigHupSignal)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigHupSignal:
+				s.handleSigHup(ctx)
+			}
+		}
+	})
+}
+
+// handleSigHup reloads the runtime configuration and reopens the log files unless the respective behavior is disabled
+// and logs a message that describes what was reloaded.
+func (s *Server) handleSigHup(ctx context.Context) {
+	var params []svc1log.Param
+	if !s.disableSigHupConfigReloadHandler {
+		reloaded := false
+		if reloader, ok := s.runtimeConfigSource.(refreshablefile.Reloader); !ok {
+			s.svcLogger.Warn("Runtime configuration provider does not support reloading on SIGHUP.")
+		} else if err := reloader.Reload(ctx); err != nil {
+			s.svcLogger.Error("Failed to reload runtime configuration on SIGHUP.", svc1log.Stacktrace(err))
+		} else {
+			reloaded = true
+		}
+		params = append(params, svc1log.SafeParam("runtimeConfigReloaded", reloaded))
+	}
+	if !s.disableSigHupLogReopenHandler {
+		filenames, err := s.reopenLogFiles()
+		if err != nil {
+			s.svcLogger.Error("Failed to reopen log files on SIGHUP.", svc1log.Stacktrace(err))
+		}
+		params = append(params, svc1log.SafeParam("reopenedLogFiles", filenames))
+	}
+	s.svcLogger.Info("Handled SIGHUP signal.", params...)
+}
+
 // Running returns true if the server is in the "running" state (as opposed to "idle" or "initializing"), false
 // otherwise.
 func (s *Server) Running() bool {
@@ -21,18 +21,22 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
 	"path"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"testing"
 	"time"
 
+	"github.com/nmiyake/pkg/dirs"
 	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
 	werror "github.com/palantir/witchcraft-go-error"
 	"github.com/palantir/witchcraft-go-logging/conjure/witchcraft/api/logging"
+	"github.com/palantir/witchcraft-go-logging/wlog"
 	"github.com/palantir/witchcraft-go-logging/wlog/auditlog/audit2log"
 	"github.com/palantir/witchcraft-go-logging/wlog/evtlog/evt2log"
 	"github.com/palantir/witchcraft-go-logging/wlog/metriclog/metric1log"
@@ -453,6 +457,25 @@ func TestServer_Start_WithPortInUse(t *testing.T) {
 	}
 }
 
+func TestServer_Start_WithUnknownMiddlewareStage(t *testing.T) {
+	server, cleanup := newServer("127.0.0.1", 0)
+	defer cleanup()
+	server.WithMiddlewareBefore("unknown", witchcraft.NewNamedRequestMiddleware("custom", func(rw http.ResponseWriter, r *http.Request, next http.Handler) {
+		next.ServeHTTP(rw, r)
+	}))
+
+	errc := make(chan error)
+	go func() {
+		errc <- server.Start()
+	}()
+	select {
+	case err := <-errc:
+		require.EqualError(t, err, "failed to configure middleware stages: unknown middleware stage")
+	case <-time.After(5 * time.Second):
+		t.Errorf("server started despite an unknown middleware stage")
+	}
+}
+
 // TestServer_InitNetworkLogging verifies that in various cases of network logging misconfiguration,
 // we are always able to continue past those errors and attempt to initialize the server.
 func TestServer_InitNetworkLogging(t *testing.T) {
@@ -662,6 +685,91 @@ func testStop(t *testing.T, stop func(*witchcraft.Server, context.Context) error
 	}
 }
 
+// TestServer_SigHup verifies that the server reloads its runtime configuration and logs the result when it receives a
+// SIGHUP signal.
+func TestServer_SigHup(t *testing.T) {
+	tmpDir, cleanup, err := dirs.TempDir("", "")
+	require.NoError(t, err)
+	defer cleanup()
+	runtimeConfigPath := path.Join(tmpDir, "runtime.yml")
+	require.NoError(t, ioutil.WriteFile(runtimeConfigPath, []byte("logging:\n  level: info\n"), 0644))
+
+	logOutputBuffer := &lockedBuffer{}
+	runtimeConfigUpdated := make(chan config.Runtime, 1)
+	server := witchcraft.NewServer().
+		WithInitFunc(func(ctx context.Context, info witchcraft.InitInfo) (cleanup func(), rErr error) {
+			unsubscribe := info.RuntimeConfig.Subscribe(func(cfg interface{}) {
+				runtimeConfigUpdated <- cfg.(config.Runtime)
+			})
+			return unsubscribe, nil
+		}).
+		WithInstallConfig(config.Install{
+			Server: config.Server{
+				Address: "127.0.0.1",
+				Port:    0,
+			},
+			UseConsoleLog: true,
+		}).
+		WithRuntimeConfigFromFile(runtimeConfigPath).
+		WithLoggerStdoutWriter(logOutputBuffer).
+		WithECVKeyProvider(witchcraft.ECVKeyNoOp()).
+		WithDisableGoRuntimeMetrics().
+		WithSelfSignedCertificate()
+	defer func() {
+		_ = server.Close()
+	}()
+	go func() {
+		_ = server.Start()
+	}()
+	require.Eventually(t, server.Running, 5*time.Second, 10*time.Millisecond, "timed out waiting for server to start")
+
+	require.NoError(t, ioutil.WriteFile(runtimeConfigPath, []byte("logging:\n  level: debug\n"), 0644))
+	proc, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, proc.Signal(syscall.SIGHUP))
+
+	select {
+	case cfg := <-runtimeConfigUpdated:
+		require.NotNil(t, cfg.LoggerConfig)
+		assert.Equal(t, wlog.DebugLevel, cfg.LoggerConfig.Level)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timed out waiting for runtime configuration to be reloaded")
+	}
+
+	var sigHupLog logging.ServiceLogV1
+	require.Eventually(t, func() bool {
+		for _, line := range getLogMessagesOfType(t, "service.1", logOutputBuffer.Bytes()) {
+			var log logging.ServiceLogV1
+			require.NoError(t, json.Unmarshal(line, &log))
+			if log.Message == "Handled SIGHUP signal." {
+				sigHupLog = log
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond, "timed out waiting for SIGHUP log line")
+	assert.Equal(t, true, sigHupLog.Params["runtimeConfigReloaded"])
+	assert.Empty(t, sigHupLog.Params["reopenedLogFiles"], "no log files should be reopened when logging to the console")
+}
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
 func newServer(host string, port int) (*witchcraft.Server, func()) {
 	server := witchcraft.NewServer().
 		WithSelfSignedCertificate().
@@ -0,0 +1,203 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wopenapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+const defaultContentType = "application/json"
+
+// Generate returns an OpenAPI document that describes the provided routes. Operations are described using the
+// documentation registered for the routes using wrouter.RouteDocumentation (routes registered using wresource are
+// documented with their endpoint and resource names by default) and the path, query and header parameters declared by
+// the path templates and parameter permissions of the routes. Request and response schemas are derived from the Go types
+// of the documented bodies.
+//
+// Path parameter constraints are described using the schema of the parameter. OpenAPI does not support parameters that
+// span multiple path segments, so trailing path parameters are described as regular path parameters. OpenAPI cannot
+// describe routes that differ only in their host or header conditions (see wrouter.MatchHost), so only the route with the
+// fewest conditions is described for each method and path. OpenAPI paths do not contain constraints either, so an error
+// is returned if routes with the same method have path templates that differ only in their constraints (such as
+// "/x/{id:int}" and "/x/{id:uuid}").
+func Generate(info Info, routes []wrouter.RouteInfo) (*Document, error) {
+	g := newSchemaGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+	}
+	// pathTemplates stores the path template of the route described by each operation keyed by path and method
+	pathTemplates := make(map[[2]string]string)
+	for _, route := range routes {
+		template, err := wrouter.NewPathTemplate(route.Spec.PathTemplate)
+		if err != nil {
+			return nil, werror.Wrap(err, "failed to parse path template of route",
+				werror.SafeParam("method", route.Spec.Method),
+				werror.SafeParam("pathTemplate", route.Spec.PathTemplate))
+		}
+		path := openAPIPath(template.Segments())
+		key := [2]string{path, route.Spec.Method}
+		if existing, ok := pathTemplates[key]; ok && existing != route.Spec.PathTemplate {
+			return nil, werror.Error("routes with different path templates have the same OpenAPI path",
+				werror.SafeParam("method", route.Spec.Method),
+				werror.SafeParam("pathTemplate", route.Spec.PathTemplate),
+				werror.SafeParam("existingPathTemplate", existing),
+				werror.SafeParam("path", path))
+		}
+		pathTemplates[key] = route.Spec.PathTemplate
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(PathItem)
+		}
+		doc.Paths[path][strings.ToLower(route.Spec.Method)] = g.operation(route, template.Segments())
+	}
+	if len(g.schemas) > 0 {
+		doc.Components = &Components{Schemas: g.schemas}
+	}
+	return doc, nil
+}
+
+func (g *schemaGenerator) operation(route wrouter.RouteInfo, segments []wrouter.PathSegment) *Operation {
+	routeDoc := wrouter.RouteDoc{}
+	if route.Doc != nil {
+		routeDoc = *route.Doc
+	}
+	op := &Operation{
+		OperationID: routeDoc.OperationID,
+		Tags:        routeDoc.Tags,
+		Summary:     routeDoc.Summary,
+		Description: routeDoc.Description,
+		Parameters:  parameters(route, segments, routeDoc.ParamDescriptions),
+		Responses:   make(map[string]Response),
+		Deprecated:  route.Deprecation != nil,
+	}
+	if routeDoc.Request != nil {
+		op.RequestBody = &RequestBody{
+			Description: routeDoc.Request.Description,
+			Required:    true,
+			Content:     g.content(*routeDoc.Request),
+		}
+	}
+	for status, response := range routeDoc.Responses {
+		description := response.Description
+		if description == "" {
+			description = http.StatusText(status)
+		}
+		op.Responses[strconv.Itoa(status)] = Response{
+			Description: description,
+			Content:     g.content(response),
+		}
+	}
+	if len(op.Responses) == 0 {
+		// OpenAPI requires at least one response
+		op.Responses["default"] = Response{Description: "Undocumented response"}
+	}
+	return op
+}
+
+func (g *schemaGenerator) content(body wrouter.BodyDoc) map[string]MediaType {
+	contentType := body.ContentType
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+	return map[string]MediaType{
+		contentType: {Schema: g.bodySchema(body.Schema)},
+	}
+}
+
+// parameters returns the path parameters declared by the provided path segments followed by the query and header
+// parameters declared by the parameter permissions of the route. Parameter permissions store lowercase names, so the
+// name of a query or header parameter is taken from descriptions if it contains the parameter with different case.
+func parameters(route wrouter.RouteInfo, segments []wrouter.PathSegment, descriptions map[string]string) []Parameter {
+	var params []Parameter
+	for _, segment := range segments {
+		if segment.Type == wrouter.LiteralSegment {
+			continue
+		}
+		params = append(params, Parameter{
+			Name:        segment.Value,
+			In:          "path",
+			Description: descriptions[segment.Value],
+			Required:    true,
+			Schema:      pathParamSchema(segment),
+		})
+	}
+	if route.ParamPerms == nil {
+		return params
+	}
+	for _, location := range []struct {
+		in    string
+		perms wrouter.ParamPerms
+	}{
+		{in: "query", perms: route.ParamPerms.QueryParamPerms()},
+		{in: "header", perms: route.ParamPerms.HeaderParamPerms()},
+	} {
+		safe, forbidden := wrouter.ParamPermsNames(location.perms)
+		names := append(safe, forbidden...)
+		sort.Strings(names)
+		for _, name := range names {
+			name, description := describedParam(name, descriptions)
+			params = append(params, Parameter{
+				Name:        name,
+				In:          location.in,
+				Description: description,
+				Schema:      &Schema{Type: "string"},
+			})
+		}
+	}
+	return params
+}
+
+func describedParam(name string, descriptions map[string]string) (string, string) {
+	for describedName, description := range descriptions {
+		if strings.EqualFold(describedName, name) {
+			return describedName, description
+		}
+	}
+	return name, ""
+}
+
+func pathParamSchema(segment wrouter.PathSegment) *Schema {
+	switch {
+	case segment.Constraint == nil:
+		return &Schema{Type: "string"}
+	case segment.Constraint.String() == "int":
+		return &Schema{Type: "integer", Format: "int64"}
+	case segment.Constraint.String() == "uuid":
+		return &Schema{Type: "string", Format: "uuid"}
+	default:
+		return &Schema{Type: "string", Pattern: "^(?:" + segment.Constraint.Regexp() + ")$"}
+	}
+}
+
+// openAPIPath returns the OpenAPI path for the provided path segments. Constraints are omitted since OpenAPI paths only
+// contain parameter names.
+func openAPIPath(segments []wrouter.PathSegment) string {
+	parts := make([]string, len(segments))
+	for i, segment := range segments {
+		if segment.Type == wrouter.LiteralSegment {
+			parts[i] = segment.Value
+		} else {
+			parts[i] = "{" + segment.Value + "}"
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wopenapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wopenapi"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wresource"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/wradix"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dataset struct {
+	RID      string            `json:"rid"`
+	Name     string            `json:"name,omitempty"`
+	Created  time.Time         `json:"created"`
+	Parent   *dataset          `json:"parent,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Contents []byte            `json:"contents,omitempty"`
+	internal string
+}
+
+type createDatasetRequest struct {
+	Name string `json:"name"`
+}
+
+func TestGenerate(t *testing.T) {
+	r := wrouter.New(whttprouter.New())
+	resource := wresource.New("datasets", r.Subrouter("/api"))
+	require.NoError(t, resource.Get("getDataset", "/datasets/{rid:uuid}", http.NotFoundHandler(),
+		wrouter.SafeQueryParams("branch"),
+		wrouter.ForbiddenHeaderParams("Authorization"),
+		wrouter.RouteDocumentation(wrouter.RouteDoc{
+			Summary:           "Returns a dataset",
+			ParamDescriptions: map[string]string{"rid": "The dataset RID", "Branch": "The branch"},
+			Responses: map[int]wrouter.BodyDoc{
+				http.StatusOK: {Schema: dataset{}},
+			},
+		}),
+	))
+	require.NoError(t, resource.Post("createDataset", "/datasets", http.NotFoundHandler(),
+		wrouter.RouteDocumentation(wrouter.RouteDoc{
+			OperationID: "create",
+			Request:     &wrouter.BodyDoc{Schema: createDatasetRequest{}},
+			Responses: map[int]wrouter.BodyDoc{
+				http.StatusOK: {Description: "The created dataset", Schema: &dataset{}},
+			},
+		}),
+	))
+	require.NoError(t, r.Get("/files/{path*}", http.NotFoundHandler(), wrouter.RouteDeprecated(wrouter.RouteDeprecation{})))
+
+	doc, err := wopenapi.Generate(wopenapi.Info{Title: "datasets", Version: "1.0.0"}, wrouter.RegisteredRouteInfos(r))
+	require.NoError(t, err)
+	got, err := json.MarshalIndent(doc, "", "  ")
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+  "openapi": "3.0.3",
+  "info": {"title": "datasets", "version": "1.0.0"},
+  "paths": {
+    "/api/datasets": {
+      "post": {
+        "operationId": "create",
+        "tags": ["datasets"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/createDatasetRequest"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "The created dataset",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/dataset"}}}
+          }
+        }
+      }
+    },
+    "/api/datasets/{rid}": {
+      "get": {
+        "operationId": "getDataset",
+        "tags": ["datasets"],
+        "summary": "Returns a dataset",
+        "parameters": [
+          {"name": "rid", "in": "path", "description": "The dataset RID", "required": true, "schema": {"type": "string", "format": "uuid"}},
+          {"name": "Branch", "in": "query", "description": "The branch", "schema": {"type": "string"}},
+          {"name": "authorization", "in": "header", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/dataset"}}}
+          }
+        }
+      }
+    },
+    "/files/{path}": {
+      "get": {
+        "parameters": [
+          {"name": "path", "in": "path", "required": true, "schema": {"type": "string"}}
+        ],
+        "responses": {"default": {"description": "Undocumented response"}},
+        "deprecated": true
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "createDatasetRequest": {
+        "type": "object",
+        "properties": {"name": {"type": "string"}},
+        "required": ["name"]
+      },
+      "dataset": {
+        "type": "object",
+        "properties": {
+          "rid": {"type": "string"},
+          "name": {"type": "string"},
+          "created": {"type": "string", "format": "date-time"},
+          "parent": {"$ref": "#/components/schemas/dataset"},
+          "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+          "contents": {"type": "string", "format": "byte"}
+        },
+        "required": ["created", "rid"]
+      }
+    }
+  }
+}`, string(got))
+}
+
+func TestGeneratePathTemplatesThatDifferOnlyInConstraints(t *testing.T) {
+	r := wrouter.New(wradix.New())
+	require.NoError(t, r.Get("/x/{id:int}", http.NotFoundHandler()))
+	require.NoError(t, r.Get("/x/{id:uuid}", http.NotFoundHandler()))
+	require.NoError(t, r.Post("/x/{id:int}", http.NotFoundHandler()))
+
+	_, err := wopenapi.Generate(wopenapi.Info{Title: "x", Version: "1.0.0"}, wrouter.RegisteredRouteInfos(r))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "routes with different path templates have the same OpenAPI path")
+}
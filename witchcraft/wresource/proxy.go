@@ -0,0 +1,421 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wresource
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/requestlog"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+const (
+	// ProxyPathParamName is the name of the trailing path parameter of the routes registered by RegisterProxy.
+	ProxyPathParamName = "proxyPath"
+
+	// proxyUpstreamServiceParamName and proxyUpstreamURIParamName are the names of the request log parameters that
+	// record the service that a request was proxied to and the base URI of the service that served the request.
+	proxyUpstreamServiceParamName = "upstreamService"
+	proxyUpstreamURIParamName     = "upstreamUri"
+
+	proxyResponseBufferSize = 32 * 1024
+	// maxProxyErrorBodySize is the maximum size in bytes of the bodies of upstream error responses that are written to
+	// the caller. Error response bodies are read in full before they are written, so larger bodies are truncated.
+	maxProxyErrorBodySize = 1 << 20
+)
+
+var (
+	// proxyMethods are the methods of the routes registered by RegisterProxy.
+	proxyMethods = []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+	}
+
+	// proxyHopByHopHeaders are the headers that apply to a single connection and are never forwarded in either
+	// direction, in addition to the headers named by the Connection header.
+	proxyHopByHopHeaders = []string{
+		"Connection",
+		"Keep-Alive",
+		"Proxy-Authenticate",
+		"Proxy-Authorization",
+		"Proxy-Connection",
+		"Te",
+		"Trailer",
+		"Transfer-Encoding",
+		"Upgrade",
+	}
+
+	// proxyManagedRequestHeaders are the request headers that are never forwarded because they are set by the client
+	// used to make the upstream request. The B3 headers are injected by the client from the span of the request.
+	proxyManagedRequestHeaders = []string{
+		"Content-Length",
+		"Host",
+		"X-B3-Flags",
+		"X-B3-ParentSpanId",
+		"X-B3-Sampled",
+		"X-B3-SpanId",
+		"X-B3-TraceId",
+		"B3",
+	}
+
+	proxyUpstreamUnavailableErrorType = middleware.NewStatusErrorType("Witchcraft:ProxyUpstreamUnavailable", http.StatusBadGateway)
+
+	errProxyRequestBodyConsumed = werror.Error("proxied request body was partially sent and cannot be sent again")
+)
+
+// ServiceClientProvider creates clients for the services configured in the "service-discovery" block of the runtime
+// configuration. It is implemented by the Clients field of the witchcraft.InitInfo provided to the initialization
+// function of a server.
+type ServiceClientProvider interface {
+	NewClient(ctx context.Context, serviceName string, params ...httpclient.ClientParam) (httpclient.Client, error)
+}
+
+// ProxyConfig configures which request headers RegisterProxy forwards to the upstream service.
+type ProxyConfig struct {
+	// AllowedHeaders are the names of the request headers that are forwarded. If empty, all request headers that are
+	// not in DeniedHeaders are forwarded.
+	AllowedHeaders []string
+	// DeniedHeaders are the names of the request headers that are never forwarded. Takes precedence over
+	// AllowedHeaders.
+	DeniedHeaders []string
+}
+
+// RegisterProxy registers routes on the provided resource that forward all requests for the provided path prefix to the
+// service with the provided name. The client for the service is created using the provided ServiceClientProvider, so
+// the URIs, retries, timeouts and TLS configuration of the service are read from the "service-discovery" block of the
+// runtime configuration, and the SERVICE_DEPENDENCY health check of the server tracks the responses of the service.
+// The path of the request after the prefix is appended to the URI of the service, and the query of the request is
+// forwarded as is. See NewProxyHandler for how requests and responses are forwarded.
+//
+// The routes use the path template "<prefix>/{proxyPath*}" and are registered for the GET, HEAD, POST, PUT, PATCH and
+// DELETE methods. Proxying requests at the root (with the prefix "/") registers the routes "/" and "/{proxyPath*}", which
+// overlap with every other route with the same method and therefore require a RouterImpl that resolves trailing path
+// parameter overlaps (see wrouter.OverlapResolvingRouterImpl): wradix does, while wgorillamux rejects the routes with a
+// conflict error if any other route with the same method is registered and whttprouter always rejects them.
+func RegisterProxy(ctx context.Context, resource Resource, endpointName, prefix string, clients ServiceClientProvider, serviceName string, cfg ProxyConfig, params ...wrouter.RouteParam) error {
+	client, err := clients.NewClient(ctx, serviceName)
+	if err != nil {
+		return werror.WrapWithContextParams(ctx, err, "failed to create client for proxied service",
+			werror.SafeParam("serviceName", serviceName))
+	}
+	handler := NewProxyHandler(client, serviceName, cfg)
+	prefix = strings.TrimSuffix(prefix, "/")
+	// the trailing path parameter does not match the empty path for all RouterImpl implementations, so the prefix is
+	// registered separately
+	prefixPath := prefix
+	if prefixPath == "" {
+		prefixPath = "/"
+	}
+	paths := []string{prefixPath, prefix + "/{" + ProxyPathParamName + "*}"}
+	for _, p := range paths {
+		for _, method := range proxyMethods {
+			if err := resource.Register(endpointName, method, p, handler, params...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewProxyHandler returns the handler used by RegisterProxy, which forwards requests to the path named by the
+// ProxyPathParamName path parameter of the request (or the root path if the request has no such parameter) using the
+// provided client.
+//
+// Request bodies are streamed to the upstream service. A request with a body is only retried on another URI if the
+// previous attempt did not send any of the body. Hop-by-hop headers, the Host and Content-Length headers and the
+// incoming B3 headers are never forwarded, and the remaining headers are filtered using the provided configuration. The
+// client injects the B3 headers of the span of the request, and the X-Forwarded-For header of the request is extended
+// with the address of the caller.
+//
+// The status, headers and body of upstream responses (including error responses) are written to the caller, and
+// successful response bodies are streamed and flushed as they are received. The bodies of error responses are read
+// before they are written and are truncated to 1 MiB. If no response is received from the
+// upstream service, a 502 response with a "Witchcraft:ProxyUpstreamUnavailable" error is written. The request log entry
+// of the request records the name of the service in the "upstreamService" parameter and the base URI of the service
+// that sent the response in the "upstreamUri" parameter.
+func NewProxyHandler(client httpclient.Client, serviceName string, cfg ProxyConfig) http.Handler {
+	h := &proxyHandler{
+		client:      client,
+		serviceName: serviceName,
+		denied:      make(map[string]struct{}),
+	}
+	if len(cfg.AllowedHeaders) > 0 {
+		h.allowed = make(map[string]struct{}, len(cfg.AllowedHeaders))
+		for _, name := range cfg.AllowedHeaders {
+			h.allowed[http.CanonicalHeaderKey(name)] = struct{}{}
+		}
+	}
+	for _, names := range [][]string{proxyHopByHopHeaders, proxyManagedRequestHeaders, cfg.DeniedHeaders} {
+		for _, name := range names {
+			h.denied[http.CanonicalHeaderKey(name)] = struct{}{}
+		}
+	}
+	return h
+}
+
+type proxyHandler struct {
+	client      httpclient.Client
+	serviceName string
+	// allowed is nil if all headers that are not denied are forwarded.
+	allowed map[string]struct{}
+	denied  map[string]struct{}
+}
+
+func (h *proxyHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	requestlog.SetParam(ctx, proxyUpstreamServiceParamName, h.serviceName)
+
+	decoder := &proxyErrorDecoder{ctx: ctx}
+	reqParams := []httpclient.RequestParam{
+		httpclient.WithRequestMethod(req.Method),
+		httpclient.WithPath(proxyUpstreamPath(req)),
+		httpclient.WithQueryValues(req.URL.Query()),
+		httpclient.WithRawResponseBody(),
+		httpclient.WithRequestErrorDecoder(decoder),
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.ContentLength != 0 {
+		body := &proxyRequestBody{body: req.Body}
+		reqParams = append(reqParams, httpclient.WithRawRequestBodyProvider(body.provide))
+	}
+	// headers are set after the body params so that they replace the Accept and Content-Type headers set by them
+	reqParams = append(reqParams, h.forwardedHeaderParams(req)...)
+
+	resp, err := h.client.Do(ctx, reqParams...)
+	if err != nil {
+		var upstreamResp *proxyUpstreamResponse
+		if errors.As(err, &upstreamResp) {
+			upstreamResp.writeTo(rw)
+			return
+		}
+		proxyUpstreamUnavailableErrorType.Write(ctx, rw, err)
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	copyProxyResponseHeader(rw.Header(), resp.Header)
+	rw.WriteHeader(resp.StatusCode)
+	if err := copyProxyResponseBody(rw, resp.Body); err != nil {
+		// the status has already been written, so the error can only be logged
+		svc1log.FromContext(ctx).Warn("Failed to copy proxied response body",
+			svc1log.SafeParam("serviceName", h.serviceName),
+			svc1log.Stacktrace(err))
+	}
+}
+
+// forwardedHeaderParams returns the params that set the headers of the request that are forwarded to the upstream
+// service. The values of headers with multiple values are joined into a single value.
+func (h *proxyHandler) forwardedHeaderParams(req *http.Request) []httpclient.RequestParam {
+	connectionHeaders := connectionHeaderNames(req.Header)
+	var params []httpclient.RequestParam
+	for name, values := range req.Header {
+		name = http.CanonicalHeaderKey(name)
+		if !h.forwardHeader(name) {
+			continue
+		}
+		if _, ok := connectionHeaders[name]; ok {
+			continue
+		}
+		sep := ", "
+		if name == "Cookie" {
+			sep = "; "
+		}
+		params = append(params, httpclient.WithHeader(name, strings.Join(values, sep)))
+	}
+	if clientIP, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		forwardedFor := clientIP
+		if prior := req.Header.Values("X-Forwarded-For"); len(prior) > 0 && h.forwardHeader("X-Forwarded-For") {
+			forwardedFor = strings.Join(prior, ", ") + ", " + clientIP
+		}
+		params = append(params, httpclient.WithHeader("X-Forwarded-For", forwardedFor))
+	}
+	return params
+}
+
+func (h *proxyHandler) forwardHeader(name string) bool {
+	if _, ok := h.denied[name]; ok {
+		return false
+	}
+	if h.allowed == nil {
+		return true
+	}
+	_, ok := h.allowed[name]
+	return ok
+}
+
+// proxyUpstreamPath returns the path of the upstream request, which is the escaped value of the ProxyPathParamName path
+// parameter of the request.
+func proxyUpstreamPath(req *http.Request) string {
+	segments := strings.Split(wrouter.PathParams(req)[ProxyPathParamName], "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "/" + strings.TrimPrefix(strings.Join(segments, "/"), "/")
+}
+
+// connectionHeaderNames returns the canonical names of the headers listed in the Connection header, which apply to a
+// single connection and must not be forwarded.
+func connectionHeaderNames(header http.Header) map[string]struct{} {
+	names := make(map[string]struct{})
+	for _, value := range header.Values("Connection") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names[http.CanonicalHeaderKey(name)] = struct{}{}
+			}
+		}
+	}
+	return names
+}
+
+// copyProxyResponseHeader copies the headers of an upstream response other than its hop-by-hop headers.
+func copyProxyResponseHeader(dst, src http.Header) {
+	connectionHeaders := connectionHeaderNames(src)
+	for name, values := range src {
+		if _, ok := connectionHeaders[http.CanonicalHeaderKey(name)]; ok {
+			continue
+		}
+		dst[name] = append([]string(nil), values...)
+	}
+	for _, name := range proxyHopByHopHeaders {
+		dst.Del(name)
+	}
+}
+
+// copyProxyResponseBody copies the body of an upstream response, flushing the response writer after every read so that
+// streamed responses reach the caller as they are received.
+func copyProxyResponseBody(rw http.ResponseWriter, body io.Reader) error {
+	flusher, _ := rw.(http.Flusher)
+	buf := make([]byte, proxyResponseBufferSize)
+	for {
+		n, readErr := body.Read(buf)
+		if n > 0 {
+			if _, err := rw.Write(buf[:n]); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// proxyRequestBody streams the body of a proxied request to the upstream service. Because the body is not buffered, it
+// can only be provided for another attempt of the request if none of it was read by a previous attempt. Closing it
+// does nothing because the server closes the body of the request.
+type proxyRequestBody struct {
+	body io.Reader
+	read atomic.Bool
+}
+
+func (b *proxyRequestBody) provide() io.ReadCloser {
+	if b.read.Load() {
+		return io.NopCloser(errReader{err: errProxyRequestBodyConsumed})
+	}
+	return b
+}
+
+func (b *proxyRequestBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if n > 0 {
+		b.read.Store(true)
+	}
+	return n, err
+}
+
+func (b *proxyRequestBody) Close() error {
+	return nil
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// proxyErrorDecoder handles the same responses as the default error decoder of the client so that the client retries
+// them in the same way, but returns errors that contain the response so that it can be written to the caller. It also
+// records the base URI of the service that sent each response in the request log.
+type proxyErrorDecoder struct {
+	ctx context.Context
+}
+
+func (d *proxyErrorDecoder) Handles(resp *http.Response) bool {
+	if resp.Request != nil && resp.Request.URL != nil {
+		upstreamURI := url.URL{Scheme: resp.Request.URL.Scheme, Host: resp.Request.URL.Host}
+		requestlog.SetParam(d.ctx, proxyUpstreamURIParamName, upstreamURI.String())
+	}
+	return resp.StatusCode >= http.StatusTemporaryRedirect
+}
+
+func (d *proxyErrorDecoder) DecodeError(resp *http.Response) error {
+	params := []werror.Param{werror.SafeParam("statusCode", resp.StatusCode)}
+	if location := resp.Header.Get("Location"); location != "" {
+		params = append(params, werror.SafeParam("location", location))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProxyErrorBodySize))
+	if err != nil {
+		return werror.WrapWithContextParams(d.ctx, err, "failed to read proxied error response body", params...)
+	}
+	upstreamResp := &proxyUpstreamResponse{
+		status: resp.StatusCode,
+		header: resp.Header.Clone(),
+		body:   body,
+	}
+	return werror.WrapWithContextParams(d.ctx, upstreamResp, "proxied request returned an error response", params...)
+}
+
+// proxyUpstreamResponse is an error response of the upstream service.
+type proxyUpstreamResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+func (r *proxyUpstreamResponse) Error() string {
+	return "upstream service returned status " + strconv.Itoa(r.status)
+}
+
+func (r *proxyUpstreamResponse) writeTo(rw http.ResponseWriter) {
+	copyProxyResponseHeader(rw.Header(), r.header)
+	// the body has been read (up to the maximum size), so its length is known even if the upstream response was chunked
+	rw.Header().Set("Content-Length", strconv.Itoa(len(r.body)))
+	rw.WriteHeader(r.status)
+	_, _ = rw.Write(r.body)
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wresource_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-client/httpclient"
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/errors"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/palantir/witchcraft-go-logging/wlog/trclog/trc1log"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/requestlog"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wresource"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/palantir/witchcraft-go-tracing/wtracing"
+	"github.com/palantir/witchcraft-go-tracing/wzipkin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterProxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/missing":
+			rw.Header().Set("Content-Type", "application/json")
+			rw.Header().Set("X-Upstream", "true")
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(rw, `{"errorCode":"NOT_FOUND"}`)
+			return
+		case "/large-error":
+			rw.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(rw, strings.Repeat("a", 2<<20))
+			return
+		}
+		body, _ := io.ReadAll(req.Body)
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("Keep-Alive", "timeout=5")
+		rw.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+			"method": req.Method,
+			"path":   req.URL.EscapedPath(),
+			"query":  req.URL.RawQuery,
+			"header": req.Header,
+			"body":   string(body),
+		})
+	}))
+	defer upstream.Close()
+
+	r := wrouter.New(whttprouter.New())
+	var logParams map[string]string
+	r.AddRouteHandlerMiddleware(func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+		ctx := requestlog.ContextWithParams(req.Context())
+		next(rw, req.WithContext(ctx), reqVals)
+		logParams = requestlog.Params(ctx)
+	})
+	clients := testServiceClientProvider{"items": {upstream.URL}}
+	require.NoError(t, wresource.RegisterProxy(context.Background(), wresource.New("proxy", r), "items", "/api/items/", clients, "items", wresource.ProxyConfig{
+		DeniedHeaders: []string{"X-Secret"},
+	}))
+	assert.Equal(t, []wrouter.RouteSpec{
+		{Method: http.MethodDelete, PathTemplate: "/api/items"},
+		{Method: http.MethodGet, PathTemplate: "/api/items"},
+		{Method: http.MethodHead, PathTemplate: "/api/items"},
+		{Method: http.MethodPatch, PathTemplate: "/api/items"},
+		{Method: http.MethodPost, PathTemplate: "/api/items"},
+		{Method: http.MethodPut, PathTemplate: "/api/items"},
+		{Method: http.MethodDelete, PathTemplate: "/api/items/{proxyPath*}"},
+		{Method: http.MethodGet, PathTemplate: "/api/items/{proxyPath*}"},
+		{Method: http.MethodHead, PathTemplate: "/api/items/{proxyPath*}"},
+		{Method: http.MethodPatch, PathTemplate: "/api/items/{proxyPath*}"},
+		{Method: http.MethodPost, PathTemplate: "/api/items/{proxyPath*}"},
+		{Method: http.MethodPut, PathTemplate: "/api/items/{proxyPath*}"},
+	}, r.RegisteredRoutes())
+
+	type echoResponse struct {
+		Method string              `json:"method"`
+		Path   string              `json:"path"`
+		Query  string              `json:"query"`
+		Header map[string][]string `json:"header"`
+		Body   string              `json:"body"`
+	}
+
+	t.Run("forwards request", func(t *testing.T) {
+		tracer, err := wzipkin.NewTracer(trc1log.New(io.Discard))
+		require.NoError(t, err)
+		span, ctx := wtracing.StartSpanFromContext(context.Background(), tracer, "proxy")
+		defer span.Finish()
+
+		req := httptest.NewRequest(http.MethodPost, "/api/items/a%20b/c?x=1&x=2", strings.NewReader("payload")).WithContext(ctx)
+		req.Header.Set("Content-Type", "text/plain")
+		req.Header.Set("Authorization", "Bearer token")
+		req.Header.Add("X-Multi", "1")
+		req.Header.Add("X-Multi", "2")
+		req.Header.Set("X-Secret", "secret")
+		req.Header.Set("Connection", "X-Hop")
+		req.Header.Set("X-Hop", "hop")
+		req.Header.Set("X-B3-TraceId", "0000000000000001")
+		rw := httptest.NewRecorder()
+		r.ServeHTTP(rw, req)
+
+		require.Equal(t, http.StatusCreated, rw.Code)
+		assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+		assert.Empty(t, rw.Header().Get("Keep-Alive"))
+		var resp echoResponse
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+		assert.Equal(t, http.MethodPost, resp.Method)
+		assert.Equal(t, "/a%20b/c", resp.Path)
+		assert.Equal(t, "x=1&x=2", resp.Query)
+		assert.Equal(t, "payload", resp.Body)
+		assert.Equal(t, []string{"text/plain"}, resp.Header["Content-Type"])
+		assert.Equal(t, []string{"Bearer token"}, resp.Header["Authorization"])
+		assert.Equal(t, []string{"1, 2"}, resp.Header["X-Multi"])
+		assert.Equal(t, []string{"192.0.2.1"}, resp.Header["X-Forwarded-For"])
+		assert.Equal(t, []string{string(span.Context().TraceID)}, resp.Header["X-B3-Traceid"])
+		assert.NotContains(t, resp.Header, "X-Secret")
+		assert.NotContains(t, resp.Header, "X-Hop")
+		assert.Equal(t, map[string]string{
+			"upstreamService": "items",
+			"upstreamUri":     upstream.URL,
+		}, logParams)
+	})
+
+	t.Run("forwards prefix", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/api/items", nil))
+
+		require.Equal(t, http.StatusCreated, rw.Code)
+		var resp echoResponse
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &resp))
+		assert.Equal(t, http.MethodGet, resp.Method)
+		assert.Equal(t, "/", resp.Path)
+		assert.Empty(t, resp.Body)
+	})
+
+	t.Run("forwards error response", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/api/items/missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, rw.Code)
+		assert.Equal(t, `{"errorCode":"NOT_FOUND"}`, rw.Body.String())
+		assert.Equal(t, "true", rw.Header().Get("X-Upstream"))
+		assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+	})
+
+	t.Run("truncates large error response", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/api/items/large-error", nil))
+
+		assert.Equal(t, http.StatusBadRequest, rw.Code)
+		assert.Equal(t, strings.Repeat("a", 1<<20), rw.Body.String())
+		assert.Equal(t, strconv.Itoa(1<<20), rw.Header().Get("Content-Length"))
+	})
+}
+
+func TestRegisterProxyAllowedHeaders(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_ = json.NewEncoder(rw).Encode(req.Header)
+	}))
+	defer upstream.Close()
+
+	r := wrouter.New(whttprouter.New())
+	clients := testServiceClientProvider{"items": {upstream.URL}}
+	require.NoError(t, wresource.RegisterProxy(context.Background(), wresource.New("proxy", r), "items", "/items", clients, "items", wresource.ProxyConfig{
+		AllowedHeaders: []string{"x-allowed", "X-Denied"},
+		DeniedHeaders:  []string{"x-denied"},
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set("X-Allowed", "allowed")
+	req.Header.Set("X-Denied", "denied")
+	req.Header.Set("X-Other", "other")
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	rw := httptest.NewRecorder()
+	r.ServeHTTP(rw, req)
+
+	require.Equal(t, http.StatusOK, rw.Code)
+	var header http.Header
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &header))
+	assert.Equal(t, "allowed", header.Get("X-Allowed"))
+	assert.Empty(t, header.Get("X-Denied"))
+	assert.Empty(t, header.Get("X-Other"))
+	// the prior X-Forwarded-For header is not allowed, so only the address of the caller is forwarded
+	assert.Equal(t, "192.0.2.1", header.Get("X-Forwarded-For"))
+}
+
+func TestRegisterProxyUpstreamUnavailable(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	upstream.Close()
+
+	r := wrouter.New(whttprouter.New())
+	clients := testServiceClientProvider{"items": {upstream.URL}}
+	require.NoError(t, wresource.RegisterProxy(context.Background(), wresource.New("proxy", r), "items", "/items", clients, "items", wresource.ProxyConfig{}))
+
+	rw := httptest.NewRecorder()
+	r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/items/1", nil))
+
+	assert.Equal(t, http.StatusBadGateway, rw.Code)
+	cerr, err := errors.UnmarshalError(rw.Body.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "Witchcraft:ProxyUpstreamUnavailable", cerr.Name())
+}
+
+func TestRegisterProxyUnknownService(t *testing.T) {
+	r := wrouter.New(whttprouter.New())
+	err := wresource.RegisterProxy(context.Background(), wresource.New("proxy", r), "items", "/items", testServiceClientProvider{}, "items", wresource.ProxyConfig{})
+	assert.EqualError(t, err, "failed to create client for proxied service: service is not configured")
+}
+
+// testServiceClientProvider creates clients for the services with the configured base URIs keyed by service name.
+type testServiceClientProvider map[string][]string
+
+func (p testServiceClientProvider) NewClient(_ context.Context, serviceName string, params ...httpclient.ClientParam) (httpclient.Client, error) {
+	uris, ok := p[serviceName]
+	if !ok {
+		return nil, werror.Error("service is not configured")
+	}
+	return httpclient.NewClient(append([]httpclient.ClientParam{
+		httpclient.WithServiceName(serviceName),
+		httpclient.WithBaseURLs(uris),
+		httpclient.WithMaxRetries(0),
+	}, params...)...)
+}
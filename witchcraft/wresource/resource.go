@@ -87,6 +87,11 @@ func (r *resourceImpl) Register(endpointName, method, path string, handler http.
 	}
 	tags = append(tags, endpointTag)
 
+	// documentation provided in params takes precedence over the default documentation
+	params = append([]wrouter.RouteParam{wrouter.RouteDocumentation(wrouter.RouteDoc{
+		OperationID: endpointName,
+		Tags:        []string{r.resourceName},
+	})}, params...)
 	return r.router.Register(method, path, handler, append(params, wrouter.MetricTags(tags))...)
 }
 
@@ -0,0 +1,251 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wresource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	cerrors "github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/errors"
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-server/httpserver"
+	werror "github.com/palantir/witchcraft-go-error"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/middleware"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+const (
+	// StaticFilePathParamName is the name of the trailing path parameter of the routes registered by
+	// RegisterStaticFiles.
+	StaticFilePathParamName = "filePath"
+
+	staticIndexFileName = "index.html"
+)
+
+// staticFileEncodings are the precompressed variants of files that are served to clients that accept them in order of
+// preference, keyed by the extension of the variant.
+var staticFileEncodings = []struct {
+	encoding  string
+	extension string
+}{
+	{encoding: "br", extension: ".br"},
+	{encoding: "gzip", extension: ".gz"},
+}
+
+// StaticFilesConfig configures how RegisterStaticFiles serves files.
+type StaticFilesConfig struct {
+	// CacheControl returns the value of the Cache-Control header of responses for the file with the provided name,
+	// which is relative to the root of the file system. If nil or if it returns an empty string, "no-cache" is used,
+	// which requires clients to revalidate the file using its ETag before using a cached copy.
+	CacheControl func(name string) string
+	// SPAFallback configures requests for paths that do not match a file and whose last segment has no extension to be
+	// served the index.html file at the root of the file system, which supports single-page applications that route
+	// requests on the client.
+	SPAFallback bool
+}
+
+// RegisterStaticFiles registers GET and HEAD routes on the provided resource that serve the files of the provided file
+// system (such as an embed.FS or the result of os.DirFS) under the provided path prefix. Requests for the prefix and
+// for directories are served the index.html file of the directory, and directory listings are never served.
+//
+// The routes use the path template "<prefix>/{filePath*}", so the request logs and metrics of the routes record the
+// template rather than the path of the requested file. Responses have a strong ETag computed from the content of the
+// file, and conditional requests (If-None-Match, If-Modified-Since) and byte range requests are supported. If the file
+// system contains a ".br" or ".gz" sibling of a file and the client accepts the corresponding encoding, the sibling is
+// served with the Content-Encoding header set instead of the file. Requests for files that do not exist receive a 404
+// response. Serving files at the root (with the prefix "/") registers the routes "/" and "/{filePath*}", which overlap
+// with every other route with the same method and therefore require a RouterImpl that resolves trailing path parameter
+// overlaps (see wrouter.OverlapResolvingRouterImpl): wradix does, while wgorillamux rejects the routes with a conflict
+// error if any other route with the same method is registered and whttprouter always rejects them.
+func RegisterStaticFiles(resource Resource, endpointName, prefix string, fsys fs.FS, cfg StaticFilesConfig, params ...wrouter.RouteParam) error {
+	handler := NewStaticFilesHandler(fsys, cfg)
+	prefix = strings.TrimSuffix(prefix, "/")
+	// the trailing path parameter does not match the empty path for all RouterImpl implementations, so the prefix is
+	// registered separately
+	prefixPath := prefix
+	if prefixPath == "" {
+		prefixPath = "/"
+	}
+	paths := []string{prefixPath, prefix + "/{" + StaticFilePathParamName + "*}"}
+	for _, p := range paths {
+		if err := resource.Get(endpointName, p, handler, params...); err != nil {
+			return err
+		}
+		if err := resource.Head(endpointName, p, handler, params...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewStaticFilesHandler returns the handler used by RegisterStaticFiles, which serves the file named by the
+// StaticFilePathParamName path parameter of the request. See RegisterStaticFiles for details.
+func NewStaticFilesHandler(fsys fs.FS, cfg StaticFilesConfig) http.Handler {
+	h := &staticFilesHandler{
+		fsys: fsys,
+		cfg:  cfg,
+	}
+	return httpserver.NewJSONHandler(h.serve, httpserver.StatusCodeMapper, httpserver.ErrHandler)
+}
+
+type staticFilesHandler struct {
+	fsys fs.FS
+	cfg  StaticFilesConfig
+	// etags caches the ETags of the files that have been served keyed by file name.
+	etags sync.Map
+}
+
+// staticFileETag is the ETag of a file with the stored modification time and size.
+type staticFileETag struct {
+	modTime time.Time
+	size    int64
+	etag    string
+}
+
+func (h *staticFilesHandler) serve(rw http.ResponseWriter, req *http.Request) error {
+	name := strings.Trim(wrouter.PathParams(req)[StaticFilePathParamName], "/")
+	if name == "" {
+		name = "."
+	}
+	if !fs.ValidPath(name) {
+		return werror.Convert(cerrors.NewNotFound())
+	}
+
+	requestedName := name
+	name, err := h.resolve(requestedName)
+	if errors.Is(err, fs.ErrNotExist) && h.cfg.SPAFallback && path.Ext(requestedName) == "" {
+		name, err = h.resolve(staticIndexFileName)
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return werror.Convert(cerrors.NewNotFound())
+	}
+	if err != nil {
+		return werror.WrapWithContextParams(req.Context(), err, "failed to resolve static file", werror.SafeParam("fileName", name))
+	}
+
+	servedName, encoding := name, ""
+	acceptedEncodings := req.Header.Get("Accept-Encoding")
+	for _, variant := range staticFileEncodings {
+		if middleware.AcceptEncodingQuality(acceptedEncodings, variant.encoding) <= 0 {
+			continue
+		}
+		if info, err := fs.Stat(h.fsys, name+variant.extension); err == nil && info.Mode().IsRegular() {
+			servedName, encoding = name+variant.extension, variant.encoding
+			break
+		}
+	}
+
+	f, err := h.fsys.Open(servedName)
+	if err != nil {
+		return werror.WrapWithContextParams(req.Context(), err, "failed to open static file", werror.SafeParam("fileName", servedName))
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return werror.WrapWithContextParams(req.Context(), err, "failed to stat static file", werror.SafeParam("fileName", servedName))
+	}
+	content, etag, err := h.contentAndETag(servedName, f, info)
+	if err != nil {
+		return werror.WrapWithContextParams(req.Context(), err, "failed to read static file", werror.SafeParam("fileName", servedName))
+	}
+
+	header := rw.Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", h.cacheControl(name))
+	header.Add("Vary", "Accept-Encoding")
+	if encoding != "" {
+		header.Set("Content-Encoding", encoding)
+		// the content type must be determined from the name of the file rather than the compressed content
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header.Set("Content-Type", contentType)
+	}
+	http.ServeContent(rw, req, name, info.ModTime(), content)
+	return nil
+}
+
+// resolve returns the name of the regular file that is served for the provided name, which is the index.html file of
+// the directory if the name refers to a directory. Returns an error that wraps fs.ErrNotExist if there is no such file.
+func (h *staticFilesHandler) resolve(name string) (string, error) {
+	info, err := fs.Stat(h.fsys, name)
+	if err != nil {
+		return name, err
+	}
+	if info.IsDir() {
+		name = path.Join(name, staticIndexFileName)
+		if info, err = fs.Stat(h.fsys, name); err != nil {
+			return name, err
+		}
+	}
+	if !info.Mode().IsRegular() {
+		return name, fs.ErrNotExist
+	}
+	return name, nil
+}
+
+func (h *staticFilesHandler) cacheControl(name string) string {
+	if h.cfg.CacheControl != nil {
+		if cacheControl := h.cfg.CacheControl(name); cacheControl != "" {
+			return cacheControl
+		}
+	}
+	return "no-cache"
+}
+
+// contentAndETag returns a seekable reader for the content of the provided file and its ETag, which is computed from the
+// content of the file the first time the file is served and whenever its modification time or size changes.
+func (h *staticFilesHandler) contentAndETag(name string, f fs.File, info fs.FileInfo) (io.ReadSeeker, string, error) {
+	seeker, isSeeker := f.(io.ReadSeeker)
+	if cached, ok := h.etags.Load(name); ok {
+		if cached := cached.(staticFileETag); cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() && isSeeker {
+			return seeker, cached.etag, nil
+		}
+	}
+
+	hash := sha256.New()
+	var content io.ReadSeeker
+	if isSeeker {
+		if _, err := io.Copy(hash, seeker); err != nil {
+			return nil, "", err
+		}
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return nil, "", err
+		}
+		content = seeker
+	} else {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return nil, "", err
+		}
+		_, _ = hash.Write(data)
+		content = bytes.NewReader(data)
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	h.etags.Store(name, staticFileETag{modTime: info.ModTime(), size: info.Size(), etag: etag})
+	return content, etag, nil
+}
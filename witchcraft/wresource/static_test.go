@@ -0,0 +1,193 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wresource_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/palantir/conjure-go-runtime/v2/conjure-go-contract/errors"
+	// underscore import to use zap implementation
+	_ "github.com/palantir/witchcraft-go-logging/wlog-zap"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/internal/routertest"
+	"github.com/palantir/witchcraft-go-server/v2/witchcraft/wresource"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/wgorillamux"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/wradix"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterStaticFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":            {Data: []byte("<html>index</html>")},
+		"app.js":                {Data: []byte("console.log('app');")},
+		"app.js.gz":             {Data: []byte("gzip-compressed")},
+		"app.js.br":             {Data: []byte("brotli-compressed")},
+		"assets/logo.svg":       {Data: []byte("<svg></svg>")},
+		"docs/index.html":       {Data: []byte("<html>docs</html>")},
+		"empty/placeholder.txt": {Data: []byte("placeholder")},
+	}
+	r := wrouter.New(whttprouter.New())
+	require.NoError(t, wresource.RegisterStaticFiles(wresource.New("ui", r), "static", "/ui/", fsys, wresource.StaticFilesConfig{
+		CacheControl: func(name string) string {
+			if strings.HasPrefix(name, "assets/") {
+				return "public, max-age=31536000, immutable"
+			}
+			return ""
+		},
+		SPAFallback: true,
+	}))
+	assert.Equal(t, []wrouter.RouteSpec{
+		{Method: http.MethodGet, PathTemplate: "/ui"},
+		{Method: http.MethodHead, PathTemplate: "/ui"},
+		{Method: http.MethodGet, PathTemplate: "/ui/{filePath*}"},
+		{Method: http.MethodHead, PathTemplate: "/ui/{filePath*}"},
+	}, r.RegisteredRoutes())
+
+	t.Run("index", func(t *testing.T) {
+		rw := routertest.ServeRequest(r, http.MethodGet, "/ui", nil, nil)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "<html>index</html>", rw.Body.String())
+		assert.Equal(t, "text/html; charset=utf-8", rw.Header().Get("Content-Type"))
+		assert.Equal(t, "no-cache", rw.Header().Get("Cache-Control"))
+		assert.Regexp(t, `^"[0-9a-f]{32}"$`, rw.Header().Get("ETag"))
+	})
+
+	t.Run("directory index", func(t *testing.T) {
+		rw := routertest.ServeRequest(r, http.MethodGet, "/ui/docs/", nil, nil)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "<html>docs</html>", rw.Body.String())
+	})
+
+	t.Run("head", func(t *testing.T) {
+		rw := routertest.ServeRequest(r, http.MethodHead, "/ui/app.js", nil, nil)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "19", rw.Header().Get("Content-Length"))
+		assert.Empty(t, rw.Body.String())
+	})
+
+	t.Run("cache control policy", func(t *testing.T) {
+		rw := routertest.ServeRequest(r, http.MethodGet, "/ui/assets/logo.svg", nil, nil)
+		assert.Equal(t, http.StatusOK, rw.Code)
+		assert.Equal(t, "public, max-age=31536000, immutable", rw.Header().Get("Cache-Control"))
+		assert.Equal(t, "image/svg+xml", rw.Header().Get("Content-Type"))
+	})
+
+	t.Run("if-none-match", func(t *testing.T) {
+		etag := routertest.ServeRequest(r, http.MethodGet, "/ui/app.js", nil, nil).Header().Get("ETag")
+		rw := routertest.ServeRequest(r, http.MethodGet, "/ui/app.js", http.Header{"If-None-Match": {etag}}, nil)
+		assert.Equal(t, http.StatusNotModified, rw.Code)
+		assert.Empty(t, rw.Body.String())
+
+		rw = routertest.ServeRequest(r, http.MethodGet, "/ui/app.js", http.Header{"If-None-Match": {`"other"`}}, nil)
+		assert.Equal(t, http.StatusOK, rw.Code)
+	})
+
+	t.Run("range", func(t *testing.T) {
+		rw := routertest.ServeRequest(r, http.MethodGet, "/ui/app.js", http.Header{"Range": {"bytes=0-6"}}, nil)
+		assert.Equal(t, http.StatusPartialContent, rw.Code)
+		assert.Equal(t, "console", rw.Body.String())
+		assert.Equal(t, "bytes 0-6/19", rw.Header().Get("Content-Range"))
+	})
+
+	t.Run("precompressed", func(t *testing.T) {
+		uncompressedETag := routertest.ServeRequest(r, http.MethodGet, "/ui/app.js", nil, nil).Header().Get("ETag")
+		for _, tc := range []struct {
+			acceptEncoding string
+			wantEncoding   string
+			wantBody       string
+		}{
+			{"gzip, deflate, br", "br", "brotli-compressed"},
+			{"gzip", "gzip", "gzip-compressed"},
+			{"br;q=0, gzip;q=0.5", "gzip", "gzip-compressed"},
+			{"identity", "", "console.log('app');"},
+		} {
+			t.Run(tc.acceptEncoding, func(t *testing.T) {
+				rw := routertest.ServeRequest(r, http.MethodGet, "/ui/app.js", http.Header{"Accept-Encoding": {tc.acceptEncoding}}, nil)
+				assert.Equal(t, http.StatusOK, rw.Code)
+				assert.Equal(t, tc.wantBody, rw.Body.String())
+				assert.Equal(t, tc.wantEncoding, rw.Header().Get("Content-Encoding"))
+				assert.Equal(t, "text/javascript; charset=utf-8", rw.Header().Get("Content-Type"))
+				assert.Equal(t, "Accept-Encoding", rw.Header().Get("Vary"))
+				if tc.wantEncoding != "" {
+					assert.NotEqual(t, uncompressedETag, rw.Header().Get("ETag"), "variants must have distinct ETags")
+				}
+			})
+		}
+	})
+
+	t.Run("spa fallback", func(t *testing.T) {
+		// paths without an extension and directories without an index are served the index of the application
+		for _, path := range []string{"/ui/settings/profile", "/ui/empty"} {
+			rw := routertest.ServeRequest(r, http.MethodGet, path, nil, nil)
+			assert.Equal(t, http.StatusOK, rw.Code)
+			assert.Equal(t, "<html>index</html>", rw.Body.String())
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		rw := routertest.ServeRequest(r, http.MethodGet, "/ui/missing.js", nil, nil)
+		assert.Equal(t, http.StatusNotFound, rw.Code)
+		var serializableErr errors.SerializableError
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &serializableErr))
+		assert.Equal(t, errors.DefaultNotFound.Name(), serializableErr.ErrorName)
+	})
+}
+
+func TestRegisterStaticFilesAtRoot(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte("<html>index</html>")},
+		"app.js":     {Data: []byte("console.log('app');")},
+	}
+	statusHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte("status"))
+	})
+	for _, tc := range []struct {
+		name    string
+		impl    wrouter.RouterImpl
+		wantErr string
+	}{
+		{name: "wradix", impl: wradix.New()},
+		{name: "wgorillamux", impl: wgorillamux.New(), wantErr: "the path templates match some of the same paths"},
+		{name: "whttprouter", impl: whttprouter.New(), wantErr: "httprouter does not support a path parameter segment and a different segment at the same position"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := wrouter.New(tc.impl)
+			require.NoError(t, r.Get("/status", statusHandler))
+			err := wresource.RegisterStaticFiles(wresource.New("ui", r), "static", "/", fsys, wresource.StaticFilesConfig{})
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			for path, want := range map[string]string{
+				"/":       "<html>index</html>",
+				"/app.js": "console.log('app');",
+				"/status": "status",
+			} {
+				rw := routertest.ServeRequest(r, http.MethodGet, path, nil, nil)
+				assert.Equal(t, http.StatusOK, rw.Code, path)
+				assert.Equal(t, want, rw.Body.String(), path)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wrouter
+
+import (
+	"fmt"
+)
+
+// RouteOverlap is a way in which the path templates of two routes with the same method can both match a request path.
+// Requests for such paths are routed to one of the routes by the RouterImpl, so routes whose path templates overlap
+// conflict unless the RouterImpl routes such requests in a way that does not depend on the order in which the routes
+// were registered (see OverlapResolvingRouterImpl).
+type RouteOverlap int
+
+const (
+	// LiteralAndPathParamOverlap is the overlap of a literal segment and a path parameter segment at the same
+	// position, such as in "/a/latest" and "/a/{id}".
+	LiteralAndPathParamOverlap RouteOverlap = iota
+	// ConstrainedAndUnconstrainedPathParamOverlap is the overlap of a path parameter segment with a constraint and a
+	// path parameter segment without a constraint at the same position, such as in "/a/{id:int}" and "/a/{name}".
+	ConstrainedAndUnconstrainedPathParamOverlap
+	// ConstrainedPathParamsOverlap is the overlap of path parameter segments with different constraints that may both
+	// match the same value at the same position, such as in "/a/{id:int}" and "/a/{id:[0-9]+}". The built-in
+	// constraints "int" and "uuid" never match the same value, so they do not overlap.
+	ConstrainedPathParamsOverlap
+	// TrailingPathParamOverlap is the overlap of a trailing path parameter segment and any other segment at the same
+	// position, such as in "/a/b" and "/a/{rest*}" or "/a/{id}/b" and "/a/{rest*}".
+	TrailingPathParamOverlap
+)
+
+// checkRouteConflicts returns an error if a route that conflicts with a route with the provided method and segments is
+// already registered with the provided RouterImpl. Routes with the same method conflict if their path templates are
+// equivalent or if they overlap in a way that the RouterImpl does not resolve (see OverlapResolvingRouterImpl). If the
+// RouterImpl is a ConflictCheckingRouterImpl, it may reject other routes that it cannot register.
+func checkRouteConflicts(impl RouterImpl, routes []RouteSpec, routeSegments map[string][]PathSegment, method string, template string, segments []PathSegment) error {
+	conflictChecker, _ := impl.(ConflictCheckingRouterImpl)
+	overlapResolver, _ := impl.(OverlapResolvingRouterImpl)
+	for _, existing := range routes {
+		if existing.Method != method {
+			continue
+		}
+		existingSegments := routeSegments[existing.PathTemplate]
+		if equivalentSegments(existingSegments, segments) {
+			return fmt.Errorf("route [%s] %s conflicts with existing route [%s] %s: the path templates are equivalent",
+				method, template, existing.Method, existing.PathTemplate)
+		}
+		if conflictChecker != nil {
+			if err := conflictChecker.CheckConflict(existingSegments, segments); err != nil {
+				return fmt.Errorf("route [%s] %s conflicts with existing route [%s] %s: %v",
+					method, template, existing.Method, existing.PathTemplate, err)
+			}
+		}
+		if overlaps, ok := segmentOverlaps(existingSegments, segments); ok && !resolvesOverlaps(overlapResolver, overlaps) {
+			return fmt.Errorf("route [%s] %s conflicts with existing route [%s] %s: the path templates match some of the same paths",
+				method, template, existing.Method, existing.PathTemplate)
+		}
+	}
+	return nil
+}
+
+// resolvesOverlaps returns true if the provided OverlapResolvingRouterImpl, which may be nil, resolves all of the
+// provided overlaps.
+func resolvesOverlaps(overlapResolver OverlapResolvingRouterImpl, overlaps []RouteOverlap) bool {
+	for _, overlap := range overlaps {
+		if overlapResolver == nil || !overlapResolver.ResolvesOverlap(overlap) {
+			return false
+		}
+	}
+	return true
+}
+
+// equivalentSegments returns true if the provided segments match exactly the same paths. Path parameter names are not
+// considered.
+func equivalentSegments(a, b []PathSegment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type {
+			return false
+		}
+		switch a[i].Type {
+		case LiteralSegment:
+			if a[i].Value != b[i].Value {
+				return false
+			}
+		case PathParamSegment:
+			if constraintString(a[i].Constraint) != constraintString(b[i].Constraint) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// segmentOverlaps returns true if there may be a path that matches both of the provided segments, which are not
+// equivalent, along with the overlaps at the positions at which the segments differ. Two path parameters with different
+// constraints are assumed to overlap unless both constraints are built-in, since the overlap of regular expressions is
+// not computed.
+func segmentOverlaps(a, b []PathSegment) ([]RouteOverlap, bool) {
+	var overlaps []RouteOverlap
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i].Type == TrailingPathParamSegment && b[i].Type == TrailingPathParamSegment {
+			return overlaps, true
+		}
+		if a[i].Type == TrailingPathParamSegment {
+			return append(overlaps, TrailingPathParamOverlap), canMatchNonEmptyRemainder(b[i:])
+		}
+		if b[i].Type == TrailingPathParamSegment {
+			return append(overlaps, TrailingPathParamOverlap), canMatchNonEmptyRemainder(a[i:])
+		}
+		overlap, differ, ok := segmentOverlap(a[i], b[i])
+		if !ok {
+			return nil, false
+		}
+		if differ {
+			overlaps = append(overlaps, overlap)
+		}
+	}
+	return overlaps, len(a) == len(b)
+}
+
+// segmentOverlap returns whether there may be a value that matches both of the provided non-trailing segments and, if
+// the segments differ, the kind of their overlap.
+func segmentOverlap(a, b PathSegment) (overlap RouteOverlap, differ bool, ok bool) {
+	switch {
+	case a.Type == LiteralSegment && b.Type == LiteralSegment:
+		return 0, false, a.Value == b.Value
+	case a.Type == LiteralSegment:
+		return LiteralAndPathParamOverlap, true, literalMatchesParam(a.Value, b)
+	case b.Type == LiteralSegment:
+		return LiteralAndPathParamOverlap, true, literalMatchesParam(b.Value, a)
+	case constraintString(a.Constraint) == constraintString(b.Constraint):
+		return 0, false, true
+	case a.Constraint == nil || b.Constraint == nil:
+		return ConstrainedAndUnconstrainedPathParamOverlap, true, true
+	default:
+		return ConstrainedPathParamsOverlap, true, !isBuiltinConstraint(a.Constraint) || !isBuiltinConstraint(b.Constraint)
+	}
+}
+
+func isBuiltinConstraint(constraint PathParamConstraint) bool {
+	switch constraint.(type) {
+	case intConstraint, uuidConstraint:
+		return true
+	default:
+		return false
+	}
+}
+
+func literalMatchesParam(literal string, param PathSegment) bool {
+	return literal != "" && (param.Constraint == nil || param.Constraint.Matches(literal))
+}
+
+// canMatchNonEmptyRemainder returns true if the provided segments can match a non-empty path remainder, which is
+// required for a trailing path parameter to match.
+func canMatchNonEmptyRemainder(segments []PathSegment) bool {
+	if len(segments) == 0 {
+		return false
+	}
+	return !(len(segments) == 1 && segments[0].Type == LiteralSegment && segments[0].Value == "")
+}
+
+func constraintString(constraint PathParamConstraint) string {
+	if constraint == nil {
+		return ""
+	}
+	return constraint.String()
+}
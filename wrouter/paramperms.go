@@ -15,6 +15,7 @@
 package wrouter
 
 import (
+	"sort"
 	"strings"
 )
 
@@ -126,3 +127,37 @@ func (c combinedParamPerms) Forbidden(paramName string) bool {
 	}
 	return false
 }
+
+// ParamPermsNames returns the sorted names of the parameters that the provided ParamPerms declares safe and forbidden.
+// Names are lowercase since ParamPerms are case-insensitive. Only the ParamPerms implementations provided by this package
+// can be enumerated: no names are returned for other implementations.
+func ParamPermsNames(perms ParamPerms) (safe []string, forbidden []string) {
+	safeNames, forbiddenNames := make(map[string]struct{}), make(map[string]struct{})
+	addParamNames(perms, safeNames, forbiddenNames)
+	for name := range forbiddenNames {
+		forbidden = append(forbidden, name)
+		delete(safeNames, name)
+	}
+	for name := range safeNames {
+		safe = append(safe, name)
+	}
+	sort.Strings(safe)
+	sort.Strings(forbidden)
+	return safe, forbidden
+}
+
+func addParamNames(perms ParamPerms, safe, forbidden map[string]struct{}) {
+	switch p := perms.(type) {
+	case *mapParamPerms:
+		for name := range p.safe {
+			safe[name] = struct{}{}
+		}
+		for name := range p.forbidden {
+			forbidden[name] = struct{}{}
+		}
+	case combinedParamPerms:
+		for _, currPerms := range p {
+			addParamNames(currPerms, safe, forbidden)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wrouter
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/palantir/pkg/uuid"
+)
+
+// PathParamConstraint restricts the values that a path parameter can match. Constraints are specified in path templates
+// using the form "{paramName:constraint}", where constraint is "int", "uuid" or a regular expression that must match the
+// entire value of the path parameter.
+type PathParamConstraint interface {
+	// String returns the constraint as it appears in the path template.
+	String() string
+
+	// Matches returns true if the provided path parameter value satisfies the constraint.
+	Matches(value string) bool
+
+	// Regexp returns a regular expression that matches the values that satisfy the constraint, never matches '/' and
+	// does not contain capturing groups. Can be used by RouterImpl implementations that support regular expressions to
+	// express the constraint natively. Values that match the regular expression may still fail Matches (for example,
+	// integers that overflow an int64), so routers must not rely on it exclusively.
+	Regexp() string
+}
+
+const (
+	intConstraintName  = "int"
+	uuidConstraintName = "uuid"
+)
+
+// NewPathParamConstraint returns the PathParamConstraint for the provided constraint string.
+func NewPathParamConstraint(constraint string) (PathParamConstraint, error) {
+	switch constraint {
+	case "":
+		return nil, fmt.Errorf("path param constraint must not be empty")
+	case intConstraintName:
+		return intConstraint{}, nil
+	case uuidConstraintName:
+		return uuidConstraint{}, nil
+	}
+	re, err := regexp.Compile("^(?:" + constraint + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid path param constraint regular expression %q: %v", constraint, err)
+	}
+	native, err := nativeRegexp(constraint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path param constraint regular expression %q: %v", constraint, err)
+	}
+	return &regexpConstraint{
+		expr:   constraint,
+		native: native,
+		re:     re,
+	}, nil
+}
+
+// nativeRegexp returns a regular expression that matches the values that the provided one matches except for the ones
+// that contain '/' and that does not contain capturing groups. Some RouterImpl implementations (such as gorilla/mux) use
+// capturing groups to extract path parameters and reject constraints that contain their own, and match the expression
+// against the whole path rather than a single segment, so constraints are provided to them in this form.
+func nativeRegexp(expr string) (string, error) {
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	return removeSlashes(removeCaptures(parsed)).String(), nil
+}
+
+func removeCaptures(re *syntax.Regexp) *syntax.Regexp {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	for i, sub := range re.Sub {
+		re.Sub[i] = removeCaptures(sub)
+	}
+	return re
+}
+
+// removeSlashes removes '/' from all of the characters that the provided regular expression can match. Since path
+// segments never contain '/', only the parts of the expression that match a single character need to change.
+func removeSlashes(re *syntax.Regexp) *syntax.Regexp {
+	switch re.Op {
+	case syntax.OpAnyChar:
+		return &syntax.Regexp{Op: syntax.OpCharClass, Flags: re.Flags, Rune: []rune{0, '/' - 1, '/' + 1, unicode.MaxRune}}
+	case syntax.OpAnyCharNotNL:
+		return &syntax.Regexp{Op: syntax.OpCharClass, Flags: re.Flags, Rune: []rune{0, '\n' - 1, '\n' + 1, '/' - 1, '/' + 1, unicode.MaxRune}}
+	case syntax.OpCharClass:
+		var runes []rune
+		for i := 0; i < len(re.Rune); i += 2 {
+			lo, hi := re.Rune[i], re.Rune[i+1]
+			if lo > '/' || hi < '/' {
+				runes = append(runes, lo, hi)
+				continue
+			}
+			if lo < '/' {
+				runes = append(runes, lo, '/'-1)
+			}
+			if hi > '/' {
+				runes = append(runes, '/'+1, hi)
+			}
+		}
+		if len(runes) == 0 {
+			return &syntax.Regexp{Op: syntax.OpNoMatch}
+		}
+		re.Rune = runes
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if r == '/' {
+				return &syntax.Regexp{Op: syntax.OpNoMatch}
+			}
+		}
+	}
+	for i, sub := range re.Sub {
+		re.Sub[i] = removeSlashes(sub)
+	}
+	return re
+}
+
+type intConstraint struct{}
+
+func (intConstraint) String() string {
+	return intConstraintName
+}
+
+func (intConstraint) Matches(value string) bool {
+	_, err := strconv.ParseInt(value, 10, 64)
+	return err == nil
+}
+
+func (intConstraint) Regexp() string {
+	return `-?[0-9]+`
+}
+
+type uuidConstraint struct{}
+
+func (uuidConstraint) String() string {
+	return uuidConstraintName
+}
+
+func (uuidConstraint) Matches(value string) bool {
+	return uuidRegexp.MatchString(value)
+}
+
+func (uuidConstraint) Regexp() string {
+	return uuidExpr
+}
+
+const uuidExpr = `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`
+
+var uuidRegexp = regexp.MustCompile("^" + uuidExpr + "$")
+
+type regexpConstraint struct {
+	expr   string
+	native string
+	re     *regexp.Regexp
+}
+
+func (c *regexpConstraint) String() string {
+	return c.expr
+}
+
+func (c *regexpConstraint) Matches(value string) bool {
+	return c.re.MatchString(value)
+}
+
+func (c *regexpConstraint) Regexp() string {
+	return c.native
+}
+
+// PathParamInt returns the value of the path parameter with the provided name for the provided request parsed as an
+// int64. Returns an error if the request does not have the path parameter or if its value is not an integer.
+func PathParamInt(req *http.Request, name string) (int64, error) {
+	value, err := pathParam(req, name)
+	if err != nil {
+		return 0, err
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("path param %q with value %q is not an integer: %v", name, value, err)
+	}
+	return parsed, nil
+}
+
+// PathParamUUID returns the value of the path parameter with the provided name for the provided request parsed as a
+// UUID. Returns an error if the request does not have the path parameter or if its value is not a UUID.
+func PathParamUUID(req *http.Request, name string) (uuid.UUID, error) {
+	value, err := pathParam(req, name)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	parsed, err := uuid.ParseUUID(value)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("path param %q with value %q is not a UUID: %v", name, value, err)
+	}
+	return parsed, nil
+}
+
+func pathParam(req *http.Request, name string) (string, error) {
+	value, ok := PathParams(req)[name]
+	if !ok {
+		return "", fmt.Errorf("path param %q does not exist for request", name)
+	}
+	return value, nil
+}
+
+// pathParamsSatisfyConstraints returns true if all of the provided path parameter values satisfy the constraints of
+// the path segments for which they were matched and the values of all non-trailing path parameters are a single path
+// segment.
+func pathParamsSatisfyConstraints(segments []PathSegment, pathParamVals map[string]string) bool {
+	for _, segment := range segments {
+		if segment.Type != PathParamSegment {
+			continue
+		}
+		value := pathParamVals[segment.Value]
+		if strings.Contains(value, "/") {
+			return false
+		}
+		if segment.Constraint != nil && !segment.Constraint.Matches(value) {
+			return false
+		}
+	}
+	return true
+}
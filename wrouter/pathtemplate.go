@@ -38,10 +38,16 @@ func (p *pathTemplateImpl) Segments() []PathSegment {
 	return p.segments
 }
 
+// MatchesPathTemplate returns true if the provided request path matches the provided path template.
+func MatchesPathTemplate(template PathTemplate, path string) bool {
+	return matchesPath(template.Segments(), path)
+}
+
 var (
-	fullPathRegExp           = regexp.MustCompile(`^/[a-zA-Z0-9_{}/.\-*]*$`)
-	pathParamMatcher         = regexp.MustCompile(`^\{([a-zA-Z0-9]+)(\*?)}$`)
-	regularPathSegmentRegExp = regexp.MustCompile(`^[a-zA-Z0-9\-_]+$`)
+	fullPathRegExp            = regexp.MustCompile(`^/[a-zA-Z0-9_{}/.\-*]*$`)
+	pathParamMatcher          = regexp.MustCompile(`^\{([a-zA-Z0-9]+)(\*?)}$`)
+	regularPathSegmentRegExp  = regexp.MustCompile(`^[a-zA-Z0-9\-_]+$`)
+	constrainedPathParamStart = regexp.MustCompile(`\{([a-zA-Z0-9]+):`)
 )
 
 // NewPathTemplate creates a new PathTemplate using the provided path. The provided path must be of the following form:
@@ -55,17 +61,71 @@ var (
 //     precedes the variable
 //   - For example, a route registered with path "/pkg/{pkgPath*}" matched against the request
 //     "/pkg/product/1.0.0/package.tgz" will result in a path parameter value of "product/1.0.0/package.tgz"
+//   - Non-trailing path parameters can take the form "{paramName:constraint}", where constraint is "int", "uuid" or a
+//     regular expression that must match the entire value (see PathParamConstraint). Constraints may not contain '/'
 func NewPathTemplate(in string) (PathTemplate, error) {
-	segments, err := toPathSegments(in)
+	unconstrained, constraints, err := extractPathParamConstraints(in)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := toPathSegments(unconstrained)
 	if err != nil {
 		return nil, err
 	}
+	for i := range segments {
+		if constraint, ok := constraints[segments[i].Value]; ok && segments[i].Type == PathParamSegment {
+			segments[i].Constraint = constraint
+		}
+	}
 	return &pathTemplateImpl{
 		rawTemplate: in,
 		segments:    segments,
 	}, nil
 }
 
+// extractPathParamConstraints returns the provided path with all of the path parameters of the form
+// "{paramName:constraint}" replaced by "{paramName}" along with the constraints keyed by path parameter name.
+func extractPathParamConstraints(path string) (string, map[string]PathParamConstraint, error) {
+	constraints := make(map[string]PathParamConstraint)
+	var unconstrained strings.Builder
+	for {
+		loc := constrainedPathParamStart.FindStringSubmatchIndex(path)
+		if loc == nil {
+			break
+		}
+		paramName := path[loc[2]:loc[3]]
+		// find the brace that closes the path parameter, allowing for braces within the constraint
+		end, depth := -1, 1
+		for i := loc[1]; i < len(path) && end == -1; i++ {
+			switch path[i] {
+			case '{':
+				depth++
+			case '}':
+				if depth--; depth == 0 {
+					end = i
+				}
+			}
+		}
+		if end == -1 {
+			return "", nil, fmt.Errorf("path param %q in path %s is missing a closing brace", paramName, path)
+		}
+		constraintStr := path[loc[1]:end]
+		if strings.Contains(constraintStr, "/") {
+			return "", nil, fmt.Errorf("constraint %q for path param %q in path %s must not contain '/'", constraintStr, paramName, path)
+		}
+		constraint, err := NewPathParamConstraint(constraintStr)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid constraint for path param %q in path %s: %v", paramName, path, err)
+		}
+		constraints[paramName] = constraint
+		unconstrained.WriteString(path[:loc[0]])
+		unconstrained.WriteString("{" + paramName + "}")
+		path = path[end+1:]
+	}
+	unconstrained.WriteString(path)
+	return unconstrained.String(), constraints, nil
+}
+
 func toPathSegments(path string) ([]PathSegment, error) {
 	if !fullPathRegExp.MatchString(path) {
 		return nil, fmt.Errorf("path %q must match regexp %s", path, fullPathRegExp)
@@ -114,3 +174,68 @@ func toPathSegments(path string) ([]PathSegment, error) {
 func isTrailingMatchParam(pathParamMatches []string) bool {
 	return pathParamMatches[2] == "*"
 }
+
+// matchesPath returns true if the provided request path matches the path template with the provided segments.
+func matchesPath(segments []PathSegment, path string) bool {
+	pathParts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, segment := range segments {
+		if segment.Type == TrailingPathParamSegment {
+			return i < len(pathParts) && strings.Join(pathParts[i:], "/") != ""
+		}
+		if i >= len(pathParts) {
+			return false
+		}
+		switch segment.Type {
+		case PathParamSegment:
+			if pathParts[i] == "" || (segment.Constraint != nil && !segment.Constraint.Matches(pathParts[i])) {
+				return false
+			}
+		default:
+			if pathParts[i] != segment.Value {
+				return false
+			}
+		}
+	}
+	return len(pathParts) == len(segments)
+}
+
+// pathParamValues returns the values of the path parameters of the path template with the provided segments for the
+// provided request path, which must match the path template.
+func pathParamValues(segments []PathSegment, path string) map[string]string {
+	pathParts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	values := make(map[string]string)
+	for i, segment := range segments {
+		switch segment.Type {
+		case PathParamSegment:
+			values[segment.Value] = pathParts[i]
+		case TrailingPathParamSegment:
+			values[segment.Value] = strings.Join(pathParts[i:], "/")
+		}
+	}
+	return values
+}
+
+// moreSpecificSegments returns true if the first segment in which the provided segments differ is more specific in a
+// than in b: literal segments are more specific than constrained path parameters, which are more specific than
+// unconstrained path parameters, which are more specific than trailing path parameters.
+func moreSpecificSegments(a, b []PathSegment) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if rankA, rankB := segmentSpecificity(a[i]), segmentSpecificity(b[i]); rankA != rankB {
+			return rankA > rankB
+		}
+	}
+	return false
+}
+
+func segmentSpecificity(segment PathSegment) int {
+	switch {
+	case segment.Type == LiteralSegment:
+		return 3
+	case segment.Type == PathParamSegment && segment.Constraint != nil:
+		return 2
+	case segment.Type == PathParamSegment:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -15,6 +15,7 @@
 package wrouter_test
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/palantir/witchcraft-go-server/v2/wrouter"
@@ -158,3 +159,130 @@ func TestInvalidPathSegments(t *testing.T) {
 		assert.EqualError(t, err, currCase.WantError, "Case %d: %s\n%v", i, currCase.Name, err)
 	}
 }
+
+func TestPathTemplateMatches(t *testing.T) {
+	for i, currCase := range []struct {
+		template string
+		path     string
+		want     bool
+	}{
+		{"/", "/", true},
+		{"/", "/a", false},
+		{"/a/b", "/a/b", true},
+		{"/a/b", "/a/c", false},
+		{"/a/b", "/a/b/c", false},
+		{"/a/{b}", "/a/value", true},
+		{"/a/{b}", "/a/", false},
+		{"/a/{b}", "/a", false},
+		{"/a/{b}/c", "/a/value/c", true},
+		{"/a/{b*}", "/a/b/c/d.txt", true},
+		{"/a/{b*}", "/a/b", true},
+		{"/a/{b*}", "/a/", false},
+		{"/a/{b*}", "/a", false},
+	} {
+		template, err := wrouter.NewPathTemplate(currCase.template)
+		require.NoError(t, err, "Case %d", i)
+		assert.Equal(t, currCase.want, wrouter.MatchesPathTemplate(template, currCase.path), "Case %d: %s against %s", i, currCase.template, currCase.path)
+	}
+}
+
+func TestPathTemplateConstraints(t *testing.T) {
+	template, err := wrouter.NewPathTemplate("/datasets/{id:int}/files/{fileId:uuid}/{name:[a-z]{2,4}}/{rest*}")
+	require.NoError(t, err)
+	assert.Equal(t, "/datasets/{id:int}/files/{fileId:uuid}/{name:[a-z]{2,4}}/{rest*}", template.Template())
+
+	segments := template.Segments()
+	require.Len(t, segments, 6)
+	for i, want := range []struct {
+		segmentType wrouter.SegmentType
+		value       string
+		constraint  string
+	}{
+		{wrouter.LiteralSegment, "datasets", ""},
+		{wrouter.PathParamSegment, "id", "int"},
+		{wrouter.LiteralSegment, "files", ""},
+		{wrouter.PathParamSegment, "fileId", "uuid"},
+		{wrouter.PathParamSegment, "name", "[a-z]{2,4}"},
+		{wrouter.TrailingPathParamSegment, "rest", ""},
+	} {
+		assert.Equal(t, want.segmentType, segments[i].Type, "Segment %d", i)
+		assert.Equal(t, want.value, segments[i].Value, "Segment %d", i)
+		if want.constraint == "" {
+			assert.Nil(t, segments[i].Constraint, "Segment %d", i)
+		} else if assert.NotNil(t, segments[i].Constraint, "Segment %d", i) {
+			assert.Equal(t, want.constraint, segments[i].Constraint.String(), "Segment %d", i)
+		}
+	}
+
+	assert.True(t, wrouter.MatchesPathTemplate(template, "/datasets/-12/files/0d8ba6d2-9b0f-4a8c-8c42-35d1d1a2b6e0/abc/x/y"))
+	assert.False(t, wrouter.MatchesPathTemplate(template, "/datasets/abc/files/0d8ba6d2-9b0f-4a8c-8c42-35d1d1a2b6e0/abc/x/y"))
+	assert.False(t, wrouter.MatchesPathTemplate(template, "/datasets/99999999999999999999/files/0d8ba6d2-9b0f-4a8c-8c42-35d1d1a2b6e0/abc/x/y"))
+	assert.False(t, wrouter.MatchesPathTemplate(template, "/datasets/12/files/not-a-uuid/abc/x/y"))
+	assert.False(t, wrouter.MatchesPathTemplate(template, "/datasets/12/files/0d8ba6d2-9b0f-4a8c-8c42-35d1d1a2b6e0/abcde/x/y"))
+}
+
+// Tests that the regular expressions of constraints do not match '/' so that routers that match them against the whole
+// path only match a single path segment.
+func TestPathParamConstraintRegexpDoesNotMatchSlash(t *testing.T) {
+	for _, tc := range []struct {
+		constraint string
+		matches    []string
+	}{
+		{".+", []string{"a", "a.b"}},
+		{"(?s).*", []string{"", "a\nb"}},
+		{"[^a]+", []string{"b", "xyz"}},
+		{"[ -z]+", []string{"a.b", "a-b"}},
+		{"a|\\x2f", []string{"a"}},
+		{"[/]", nil},
+		{"int", []string{"-12"}},
+		{"uuid", []string{"0d8ba6d2-9b0f-4a8c-8c42-35d1d1a2b6e0"}},
+	} {
+		constraint, err := wrouter.NewPathParamConstraint(tc.constraint)
+		require.NoError(t, err, tc.constraint)
+		re, err := regexp.Compile("^(?:" + constraint.Regexp() + ")$")
+		require.NoError(t, err, tc.constraint)
+		for _, value := range tc.matches {
+			assert.True(t, re.MatchString(value), "%s should match %q", tc.constraint, value)
+		}
+		for _, value := range []string{"/", "a/b", "/a", "a/"} {
+			assert.False(t, re.MatchString(value), "%s should not match %q", tc.constraint, value)
+		}
+	}
+}
+
+func TestInvalidPathParamConstraints(t *testing.T) {
+	for i, currCase := range []struct {
+		Name      string
+		Path      string
+		WantError string
+	}{
+		{
+			"constraint must not be empty",
+			"/a/{b:}",
+			"invalid constraint for path param \"b\" in path /a/{b:}: path param constraint must not be empty",
+		},
+		{
+			"constrained path param must have a closing brace",
+			"/a/{b:[a-z]",
+			"path param \"b\" in path /a/{b:[a-z] is missing a closing brace",
+		},
+		{
+			"constraint regular expression must compile",
+			"/a/{b:(a}",
+			"invalid constraint for path param \"b\" in path /a/{b:(a}: invalid path param constraint regular expression \"(a\": error parsing regexp: missing closing ): `^(?:(a)$`",
+		},
+		{
+			"constraint must not contain slashes",
+			"/a/{b:a/b}",
+			"constraint \"a/b\" for path param \"b\" in path /a/{b:a/b} must not contain '/'",
+		},
+		{
+			"trailing path params cannot have constraints",
+			"/a/{b*:int}",
+			"path \"/a/{b*:int}\" must match regexp ^/[a-zA-Z0-9_{}/.\\-*]*$",
+		},
+	} {
+		_, err := wrouter.NewPathTemplate(currCase.Path)
+		assert.EqualError(t, err, currCase.WantError, "Case %d: %s\n%v", i, currCase.Name, err)
+	}
+}
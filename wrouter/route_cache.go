@@ -0,0 +1,52 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wrouter
+
+import (
+	"fmt"
+)
+
+// RouteCache describes how the responses of a route are cached, configured using RouteCached.
+type RouteCache struct {
+	// Name identifies the cache of the route in the runtime configuration, which configures how long its responses
+	// are cached, and in requests to purge cached responses. Routes may share a name.
+	Name string
+	// QueryParams are the names of the query parameters whose values are part of the cache key. Requests that differ
+	// only in the values of other query parameters receive the same cached response.
+	QueryParams []string
+	// Headers are the names of the request headers whose values are part of the cache key. Unless the route is Public,
+	// the Authorization and Cookie headers are also part of the cache key. Requests that differ only in the values of
+	// other headers receive the same cached response, so responses whose Vary header names other headers are not
+	// cached.
+	Headers []string
+	// Public configures the cached responses of the route to be shared by all callers. By default, the Authorization
+	// and Cookie headers of a request are part of the cache key so that callers never receive responses cached for
+	// other callers, whether they authenticate with a bearer token or a cookie.
+	Public bool
+}
+
+// RouteCached enables the in-memory caching of successful responses to GET and HEAD requests to the route, which is
+// useful for idempotent routes that compute identical responses for many requests. Responses are cached for the TTL
+// that the runtime configuration of the server configures for the name of the cache. Returns an error if the name is
+// empty.
+func RouteCached(cache RouteCache) RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		if cache.Name == "" {
+			return fmt.Errorf("route cache name must not be empty")
+		}
+		b.cache = &cache
+		return nil
+	})
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wrouter
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// HostRouteTagName is the name of the metric tag that stores the host pattern of routes registered using MatchHost.
+const HostRouteTagName = "hostRoute"
+
+// RouteConditions stores the conditions other than the method and path that a request must satisfy to match a route.
+// The zero value matches all requests.
+type RouteConditions struct {
+	// Host is the host pattern configured using MatchHost. Empty if the route matches requests for any host.
+	Host string
+	// Headers maps the canonical names of the headers configured using MatchHeader to the values they must have.
+	Headers map[string]string
+}
+
+// IsEmpty returns true if the conditions match all requests.
+func (c RouteConditions) IsEmpty() bool {
+	return c.Host == "" && len(c.Headers) == 0
+}
+
+// String returns a description of the conditions, such as `host=*.example.com, X-Api-Version=2`.
+func (c RouteConditions) String() string {
+	var parts []string
+	if c.Host != "" {
+		parts = append(parts, "host="+c.Host)
+	}
+	names := make([]string, 0, len(c.Headers))
+	for name := range c.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, name+"="+c.Headers[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// count returns the number of conditions.
+func (c RouteConditions) count() int {
+	n := len(c.Headers)
+	if c.Host != "" {
+		n++
+	}
+	return n
+}
+
+func (c RouteConditions) equal(other RouteConditions) bool {
+	if c.Host != other.Host || len(c.Headers) != len(other.Headers) {
+		return false
+	}
+	for name, value := range c.Headers {
+		if otherValue, ok := other.Headers[name]; !ok || otherValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// matches returns true if the provided request satisfies the conditions.
+func (c RouteConditions) matches(req *http.Request) bool {
+	if c.Host != "" && !matchesHostPattern(c.Host, req.Host) {
+		return false
+	}
+	for name, value := range c.Headers {
+		if !containsString(req.Header.Values(name), value) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchHost configures the route to only match requests whose Host header matches the provided pattern. The pattern
+// is a host name whose labels may be "*" to match any single label: for example, "*.example.com" matches
+// "api.example.com" but not "example.com" or "v1.api.example.com". Matching ignores case and the port of the request.
+//
+// Routes with the same method and path template can be registered multiple times with different conditions. A request
+// is handled by the route with the most conditions that it satisfies (the host pattern and each header count as one
+// condition), or by the route that was registered first among routes with the same number of conditions. A route
+// registered without conditions handles the requests that satisfy none of the others. If no route matches, the request
+// is handled by the not found handler. If provided multiple times (for example, to both a subrouter and a route
+// registered on it), the last pattern is used.
+func MatchHost(pattern string) RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		normalized, err := normalizeHostPattern(pattern)
+		if err != nil {
+			return err
+		}
+		b.conditions.Host = normalized
+		return nil
+	})
+}
+
+// MatchHeader configures the route to only match requests that have a header with the provided name and value. The
+// name is case-insensitive and the value must match exactly. If provided multiple times for the same header, the last
+// value is used. See MatchHost for how routes that differ only in their conditions are matched.
+func MatchHeader(name, value string) RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		if name == "" {
+			return fmt.Errorf("header name must be non-empty")
+		}
+		if b.conditions.Headers == nil {
+			b.conditions.Headers = make(map[string]string)
+		}
+		b.conditions.Headers[http.CanonicalHeaderKey(name)] = value
+		return nil
+	})
+}
+
+func normalizeHostPattern(pattern string) (string, error) {
+	normalized := strings.TrimSuffix(strings.ToLower(pattern), ".")
+	if normalized == "" {
+		return "", fmt.Errorf("host pattern must be non-empty")
+	}
+	for _, label := range strings.Split(normalized, ".") {
+		if label == "*" {
+			continue
+		}
+		if label == "" {
+			return "", fmt.Errorf("host pattern %q contains an empty label", pattern)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return "", fmt.Errorf("host pattern %q contains invalid character %q", pattern, r)
+			}
+		}
+	}
+	return normalized, nil
+}
+
+// matchesHostPattern returns true if the provided request host matches the provided normalized pattern.
+func matchesHostPattern(pattern, host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(host, ".")
+	for {
+		patternLabel, patternRest, patternHasRest := strings.Cut(pattern, ".")
+		hostLabel, hostRest, hostHasRest := strings.Cut(host, ".")
+		if patternHasRest != hostHasRest || hostLabel == "" {
+			return false
+		}
+		if patternLabel != "*" && !strings.EqualFold(patternLabel, hostLabel) {
+			return false
+		}
+		if !patternHasRest {
+			return true
+		}
+		pattern, host = patternRest, hostRest
+	}
+}
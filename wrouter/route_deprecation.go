@@ -0,0 +1,56 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wrouter
+
+import (
+	"fmt"
+	"time"
+)
+
+// RouteDeprecation describes the deprecation of a route configured using RouteDeprecated.
+type RouteDeprecation struct {
+	// Date is the time at which the route was deprecated. If zero, the route is reported as deprecated without a date.
+	Date time.Time
+	// Sunset is the time after which the route is expected to become unavailable. If zero, no sunset is reported.
+	Sunset time.Time
+	// RejectAfterSunset configures the server to reject requests to the route once the sunset has passed. Requires
+	// Sunset to be set.
+	RejectAfterSunset bool
+	// Link is an optional URL of documentation that describes the deprecation and the replacement of the route.
+	Link string
+}
+
+// IsSunset returns true if the deprecation has a sunset and the provided time is after it.
+func (d RouteDeprecation) IsSunset(now time.Time) bool {
+	return !d.Sunset.IsZero() && now.After(d.Sunset)
+}
+
+// RouteDeprecated marks the route as deprecated. The server adds the Deprecation and Sunset headers (see RFC 8594) to
+// responses of the route, meters and logs calls to it by caller and, if RejectAfterSunset is set, rejects calls to it
+// once the sunset has passed. Returns an error if RejectAfterSunset is set without a sunset or if the sunset is before
+// the deprecation date.
+func RouteDeprecated(deprecation RouteDeprecation) RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		if deprecation.RejectAfterSunset && deprecation.Sunset.IsZero() {
+			return fmt.Errorf("route deprecation that rejects requests after the sunset must have a sunset")
+		}
+		if !deprecation.Date.IsZero() && !deprecation.Sunset.IsZero() && deprecation.Sunset.Before(deprecation.Date) {
+			return fmt.Errorf("route sunset %s is before its deprecation date %s",
+				deprecation.Sunset.Format(time.RFC3339), deprecation.Date.Format(time.RFC3339))
+		}
+		b.deprecation = &deprecation
+		return nil
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wrouter
+
+// RouteDoc documents a route for the purposes of generating API documentation (such as OpenAPI documents). It does not
+// affect how requests are handled.
+type RouteDoc struct {
+	// OperationID uniquely identifies the route. Defaults to the endpoint name for routes registered using wresource.
+	OperationID string
+	// Tags group related routes. Defaults to the resource name for routes registered using wresource.
+	Tags        []string
+	Summary     string
+	Description string
+	// ParamDescriptions maps the names of path, query and header parameters to their descriptions.
+	ParamDescriptions map[string]string
+	// Request documents the request body. Nil if the route does not accept a request body.
+	Request *BodyDoc
+	// Responses documents the responses of the route keyed by status code.
+	Responses map[int]BodyDoc
+}
+
+// BodyDoc documents a request or response body.
+type BodyDoc struct {
+	Description string
+	// ContentType is the media type of the body. Defaults to "application/json".
+	ContentType string
+	// Schema is a value of the Go type that is serialized as the body (or the reflect.Type of the body). The schema of
+	// the body is derived from the type and its JSON struct tags. If nil, the body has no schema.
+	Schema interface{}
+}
+
+// RouteDocumentation attaches the provided documentation to the route. If the parameter is provided multiple times, the
+// documentation is merged: non-empty strings and bodies override earlier values, tags are appended and descriptions and
+// responses are combined.
+func RouteDocumentation(doc RouteDoc) RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		if b.doc == nil {
+			b.doc = &RouteDoc{}
+		}
+		b.doc.merge(doc)
+		return nil
+	})
+}
+
+func (d *RouteDoc) merge(other RouteDoc) {
+	if other.OperationID != "" {
+		d.OperationID = other.OperationID
+	}
+	for _, tag := range other.Tags {
+		if !containsString(d.Tags, tag) {
+			d.Tags = append(d.Tags, tag)
+		}
+	}
+	if other.Summary != "" {
+		d.Summary = other.Summary
+	}
+	if other.Description != "" {
+		d.Description = other.Description
+	}
+	for name, description := range other.ParamDescriptions {
+		if d.ParamDescriptions == nil {
+			d.ParamDescriptions = make(map[string]string)
+		}
+		d.ParamDescriptions[name] = description
+	}
+	if other.Request != nil {
+		request := *other.Request
+		d.Request = &request
+	}
+	for status, response := range other.Responses {
+		if d.Responses == nil {
+			d.Responses = make(map[int]BodyDoc)
+		}
+		d.Responses[status] = response
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wrouter
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// CurrentETagFunc returns the entity tag of the current representation of the resource targeted by a request, or an
+// empty string if the resource does not exist. Entity tags that are not quoted are quoted by the server.
+type CurrentETagFunc func(req *http.Request) (string, error)
+
+// RouteETag enables ETags and conditional requests for GET requests to the route. Unless the handler sets the ETag
+// header of its response, the server buffers successful responses and sets their ETag header to a weak entity tag
+// computed over the response body. Requests whose If-None-Match header matches the entity tag of the response receive a
+// 304 (Not Modified) response without the body. Responses that the handler flushes are not buffered and do not receive
+// a computed entity tag. HEAD requests are passed to the handler as is, since their responses have no body to compute
+// an entity tag over.
+func RouteETag() RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		b.etag = true
+		return nil
+	})
+}
+
+// RouteCurrentETag enables ETags and conditional requests for the route using the provided function to determine the
+// entity tag of the current representation of the resource targeted by a request before the handler is invoked. The
+// If-Match and If-None-Match preconditions of requests are evaluated against the current entity tag: requests whose
+// preconditions fail receive a 304 (Not Modified) response if they are GET or HEAD requests or a precondition failed
+// error otherwise, and the handler is not invoked. This makes it possible to honor If-Match for routes that modify
+// the resource. GET and HEAD responses of the route have the current entity tag unless the handler sets one. Returns
+// an error if the function is nil.
+func RouteCurrentETag(currentETag CurrentETagFunc) RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		if currentETag == nil {
+			return fmt.Errorf("route current ETag function must not be nil")
+		}
+		b.etag = true
+		b.currentETag = currentETag
+		return nil
+	})
+}
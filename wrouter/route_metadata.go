@@ -0,0 +1,100 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wrouter
+
+import (
+	"context"
+	"fmt"
+)
+
+// MetadataKey identifies a route metadata value of type T. Keys are compared by identity rather than by name, so each
+// key should be created once using NewMetadataKey and stored in a package-level variable.
+type MetadataKey[T any] struct {
+	name string
+}
+
+// NewMetadataKey returns a new key for route metadata values of type T. The name is used to describe the key (for
+// example, in diagnostics) and does not need to be unique.
+func NewMetadataKey[T any](name string) *MetadataKey[T] {
+	return &MetadataKey[T]{name: name}
+}
+
+func (k *MetadataKey[T]) String() string {
+	return k.name
+}
+
+// Value returns the value for this key in the provided metadata and whether the metadata contains a value for the key.
+func (k *MetadataKey[T]) Value(md Metadata) (T, bool) {
+	v, ok := md.values[k]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return v.(T), true
+}
+
+// FromContext returns the value for this key in the metadata of the route that matched the request with the provided
+// context and whether the route has a value for the key.
+func (k *MetadataKey[T]) FromContext(ctx context.Context) (T, bool) {
+	return k.Value(MetadataFromContext(ctx))
+}
+
+// RouteMetadata attaches the provided value for the provided key to the route. The metadata of a route is available to
+// RouteHandlerMiddleware through RequestVals and to handlers through the request context, so middleware registered for
+// all routes can make per-route decisions. If a value is provided for the same key multiple times, the last value is
+// used (values provided when registering a route take precedence over values provided to its subrouters).
+func RouteMetadata[T any](key *MetadataKey[T], value T) RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		if key == nil {
+			return fmt.Errorf("route metadata key must not be nil")
+		}
+		if b.metadata == nil {
+			b.metadata = make(map[interface{}]interface{})
+		}
+		b.metadata[key] = value
+		return nil
+	})
+}
+
+// Metadata stores the metadata values attached to a route using RouteMetadata. The zero value contains no values.
+type Metadata struct {
+	values map[interface{}]interface{}
+}
+
+// Len returns the number of values in the metadata.
+func (m Metadata) Len() int {
+	return len(m.values)
+}
+
+// Strings returns the values in the metadata formatted using fmt.Sprint keyed by the names of their keys. If multiple
+// keys have the same name, only one of their values is returned.
+func (m Metadata) Strings() map[string]string {
+	strs := make(map[string]string, len(m.values))
+	for k, v := range m.values {
+		strs[fmt.Sprint(k)] = fmt.Sprint(v)
+	}
+	return strs
+}
+
+type metadataContextKeyType string
+
+const metadataContextKey = metadataContextKeyType("wrouterRouteMetadata")
+
+// MetadataFromContext returns the metadata of the route that matched the request with the provided context. Returns
+// empty metadata if the context is not for a request that matched a route.
+func MetadataFromContext(ctx context.Context) Metadata {
+	md, _ := ctx.Value(metadataContextKey).(Metadata)
+	return md
+}
@@ -15,14 +15,42 @@
 package wrouter
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/palantir/pkg/metrics"
 )
 
 type routeParamBuilder struct {
-	middleware       []RouteHandlerMiddleware
-	paramPerms       RouteParamPerms
-	metricTags       metrics.Tags
-	disableTelemetry bool
+	middleware         []RouteHandlerMiddleware
+	paramPerms         RouteParamPerms
+	metricTags         metrics.Tags
+	disableTelemetry   bool
+	disableCompression bool
+	decompressBody     bool
+	timeout            time.Duration
+	maxBodySize        int64
+	conditions         RouteConditions
+	deprecation        *RouteDeprecation
+	etag               bool
+	currentETag        CurrentETagFunc
+	cache              *RouteCache
+	coalesce           bool
+	replace            bool
+	doc                *RouteDoc
+	metadata           map[interface{}]interface{}
+	// subrouters stores the subrouters through which the route was registered from the outermost to the innermost.
+	subrouters []*subrouter
+}
+
+// routeMiddleware returns the middleware added to the subrouters through which the route was registered followed by the
+// middleware provided using RouteMiddleware.
+func (b *routeParamBuilder) routeMiddleware() []RouteHandlerMiddleware {
+	var middleware []RouteHandlerMiddleware
+	for _, s := range b.subrouters {
+		middleware = append(middleware, s.currentMiddleware()...)
+	}
+	return append(middleware, b.middleware...)
 }
 
 func (b *routeParamBuilder) toRequestParamPerms() RouteParamPerms {
@@ -37,6 +65,9 @@ func (b *routeParamBuilder) toMetricTags() metrics.Tags {
 	if b.metricTags != nil {
 		tags = append(tags, b.metricTags...)
 	}
+	if b.conditions.Host != "" {
+		tags = append(tags, metrics.NewTagWithFallbackValue(HostRouteTagName, b.conditions.Host, "unknown"))
+	}
 	return tags
 }
 
@@ -124,6 +155,75 @@ func DisableTelemetry() RouteParam {
 	})
 }
 
+// DisableCompression disables the compression of responses to requests matching this route. Useful for routes whose
+// responses are already compressed or that must be streamed to the client as written.
+func DisableCompression() RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		b.disableCompression = true
+		return nil
+	})
+}
+
+// DecompressRequestBody enables the decompression of the bodies of requests matching this route that are compressed
+// using a supported Content-Encoding, so that the handler reads the decompressed body. The maximum body size of the
+// route applies to the decompressed body.
+func DecompressRequestBody() RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		b.decompressBody = true
+		return nil
+	})
+}
+
+// CoalesceRequests enables the coalescing of concurrent identical GET and HEAD requests to this route: while the
+// handler runs for a request, identical requests wait for it to complete and receive a copy of its response instead
+// of invoking the handler themselves. Requests are identical if they have the same method, path, query and
+// Authorization and Cookie headers. Responses that set cookies or whose body is larger than 1 MiB are not shared, and
+// the waiting requests run the handler themselves. Useful for expensive idempotent routes that receive bursts of
+// identical requests.
+func CoalesceRequests() RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		b.coalesce = true
+		return nil
+	})
+}
+
+// RouteTimeout configures the maximum duration of requests matching this route. The server sets a deadline on the
+// context of the request, rejects writes made after the deadline and responds with a timeout error if the handler
+// returns after the deadline without having written a response.
+// Overrides the default timeout configured for the server. Returns an error if the timeout is not positive.
+func RouteTimeout(timeout time.Duration) RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		if timeout <= 0 {
+			return fmt.Errorf("route timeout must be positive: %v", timeout)
+		}
+		b.timeout = timeout
+		return nil
+	})
+}
+
+// RouteMaxBodySize configures the maximum size in bytes of the bodies of requests matching this route. The server
+// rejects requests with larger bodies with a 413 response. Overrides the default maximum body size configured for the
+// server. Returns an error if the size is not positive.
+func RouteMaxBodySize(maxBodySize int64) RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		if maxBodySize <= 0 {
+			return fmt.Errorf("route max body size must be positive: %d", maxBodySize)
+		}
+		b.maxBodySize = maxBodySize
+		return nil
+	})
+}
+
+// ReplaceExistingRoute configures Register to replace the route with the same method, path template and conditions
+// (see MatchHost and MatchHeader) if one is registered rather than returning an error. Requests that are in flight
+// when the route is replaced complete using the route that was registered when they were received.
+func ReplaceExistingRoute() RouteParam {
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		b.replace = true
+		return nil
+	})
+}
+
 // RouteMiddleware configures the provided middleware to run on requests matching this specific route.
 func RouteMiddleware(middleware RouteHandlerMiddleware) RouteParam {
 	return routeParamFunc(func(b *routeParamBuilder) error {
@@ -24,7 +24,9 @@ import (
 type Router interface {
 	// Register registers the provided handler for this router for the provided method (GET, POST, etc.) and path.
 	// The RouteParam parameters specifies any path, query or header parameters that should be considered safe or
-	// forbidden for the purposes of logging.
+	// forbidden for the purposes of logging. Returns an error if the route conflicts with a route that is already
+	// registered: routes conflict if they have the same method and their path templates can match the same path (see
+	// ReplaceExistingRoute). May be called while the router routes requests.
 	Register(method, path string, handler http.Handler, params ...RouteParam) error
 
 	// RegisteredRoutes returns a slice of all of the routes registered with this router in sorted order.
@@ -64,6 +66,32 @@ type Router interface {
 	RootRouter() RootRouter
 }
 
+// UnregisteringRouter is a Router that supports removing routes. The routers returned by New and their subrouters
+// implement this interface.
+type UnregisteringRouter interface {
+	Router
+
+	// Unregister removes the route registered for the provided method and path on this router whose conditions equal
+	// the conditions configured by the provided params (see MatchHost and MatchHeader). Other params are ignored.
+	// Requests that are in flight when the route is removed complete using the route. Returns an error if no such route
+	// is registered.
+	Unregister(method, path string, params ...RouteParam) error
+}
+
+// MiddlewareRouter is a Router that supports adding route middleware that is scoped to the router. The routers returned
+// by New and their subrouters implement this interface.
+type MiddlewareRouter interface {
+	Router
+
+	// AddRouteHandlerMiddleware adds middleware that runs on requests for the routes registered on this router. For the
+	// root router, the middleware runs for all routes. For a subrouter, the middleware runs for the routes registered
+	// through the subrouter (or its descendants), including routes that were registered before the middleware was
+	// added. Middleware runs in the following order: middleware added to the root router, middleware added to the
+	// subrouters through which the route was registered from the outermost to the innermost subrouter, and then the
+	// middleware provided for the route using RouteMiddleware.
+	AddRouteHandlerMiddleware(handlers ...RouteHandlerMiddleware)
+}
+
 // RequestHandlerMiddleware is registered on a router and runs on all requests before the path template and params are parsed.
 // Implementations must call the 'next' handler or write a response to the ResponseWriter.
 type RequestHandlerMiddleware func(rw http.ResponseWriter, r *http.Request, next http.Handler)
@@ -82,6 +110,46 @@ type RouteSpec struct {
 	PathTemplate string
 }
 
+// RouteInfo describes a registered route and the parameters with which it was registered.
+type RouteInfo struct {
+	Spec       RouteSpec
+	ParamPerms RouteParamPerms
+	MetricTags metrics.Tags
+	// DisableTelemetry is true if the route was registered with DisableTelemetry.
+	DisableTelemetry bool
+	// DisableCompression is true if the route was registered with DisableCompression.
+	DisableCompression bool
+	// DecompressRequestBody is true if the route was registered with DecompressRequestBody.
+	DecompressRequestBody bool
+	// Timeout is the timeout configured for the route using RouteTimeout. 0 if no timeout was configured.
+	Timeout time.Duration
+	// MaxBodySize is the maximum request body size configured for the route using RouteMaxBodySize. 0 if no maximum
+	// was configured.
+	MaxBodySize int64
+	// Conditions stores the conditions configured for the route using MatchHost and MatchHeader.
+	Conditions RouteConditions
+	// Deprecation stores the deprecation configured for the route using RouteDeprecated. Nil if the route is not
+	// deprecated.
+	Deprecation *RouteDeprecation
+	// ETag is true if the route was registered with RouteETag or RouteCurrentETag.
+	ETag bool
+	// CurrentETag is the function configured for the route using RouteCurrentETag. Nil if no function was configured.
+	CurrentETag CurrentETagFunc
+	// Cache stores the cache configured for the route using RouteCached. Nil if the responses of the route are not
+	// cached.
+	Cache *RouteCache
+	// CoalesceRequests is true if the route was registered with CoalesceRequests.
+	CoalesceRequests bool
+	// Middleware stores the middleware that runs for the route in the order in which it runs: the middleware added to
+	// the subrouters through which the route was registered followed by the middleware provided using RouteMiddleware.
+	// Does not include the middleware added to the root router, which runs for all routes.
+	Middleware []RouteHandlerMiddleware
+	// Doc stores the documentation registered for the route using RouteDocumentation. Nil if the route is undocumented.
+	Doc *RouteDoc
+	// Metadata stores the metadata attached to the route using RouteMetadata.
+	Metadata Metadata
+}
+
 type RequestVals struct {
 	Spec          RouteSpec
 	PathParamVals map[string]string
@@ -90,6 +158,32 @@ type RequestVals struct {
 	// DisableTelemetry instructs the logging middleware to skip over
 	// generating metrics, request, and trace logs for a request.
 	DisableTelemetry bool
+	// DisableCompression instructs the compression middleware to not compress the response to a request.
+	DisableCompression bool
+	// DecompressRequestBody instructs the decompression middleware to decompress the body of a request.
+	DecompressRequestBody bool
+	// Timeout is the timeout configured for the route using RouteTimeout. 0 if no timeout was configured, in which
+	// case the server's default timeout (if any) applies.
+	Timeout time.Duration
+	// MaxBodySize is the maximum request body size configured for the route using RouteMaxBodySize. 0 if no maximum
+	// was configured, in which case the server's default maximum (if any) applies.
+	MaxBodySize int64
+	// Conditions stores the conditions configured for the route using MatchHost and MatchHeader.
+	Conditions RouteConditions
+	// Deprecation stores the deprecation configured for the route using RouteDeprecated. Nil if the route is not
+	// deprecated.
+	Deprecation *RouteDeprecation
+	// ETag is true if the route was registered with RouteETag or RouteCurrentETag.
+	ETag bool
+	// CurrentETag is the function configured for the route using RouteCurrentETag. Nil if no function was configured.
+	CurrentETag CurrentETagFunc
+	// Cache stores the cache configured for the route using RouteCached. Nil if the responses of the route are not
+	// cached.
+	Cache *RouteCache
+	// CoalesceRequests instructs the coalescing middleware to coalesce concurrent identical requests to the route.
+	CoalesceRequests bool
+	// Metadata stores the metadata attached to the route using RouteMetadata.
+	Metadata Metadata
 }
 
 type ResponseVals struct {
@@ -16,8 +16,12 @@ package wrouter
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/palantir/pkg/metrics"
 )
@@ -34,41 +38,147 @@ type RootRouter interface {
 	RegisterNotFoundHandler(handler http.Handler)
 }
 
+// RouteInfoRouter is a RootRouter that provides information about the parameters with which its routes were
+// registered. The routers returned by New implement this interface.
+type RouteInfoRouter interface {
+	RootRouter
+
+	// RegisteredRouteInfos returns information about all of the routes registered with this router in the same order
+	// as RegisteredRoutes. Routes with the same spec that differ in their conditions (see MatchHost) are returned
+	// consecutively in the order in which requests are matched against them.
+	RegisteredRouteInfos() []RouteInfo
+}
+
+// RegisteredRouteInfos returns information about all of the routes registered with the provided router. If the router
+// does not implement RouteInfoRouter, the returned information only contains the specs of the routes returned by
+// RegisteredRoutes.
+func RegisteredRouteInfos(router RootRouter) []RouteInfo {
+	if infoRouter, ok := router.(RouteInfoRouter); ok {
+		return infoRouter.RegisteredRouteInfos()
+	}
+	routes := router.RegisteredRoutes()
+	infos := make([]RouteInfo, len(routes))
+	for i, routeSpec := range routes {
+		infos[i] = RouteInfo{Spec: routeSpec}
+	}
+	return infos
+}
+
+// MethodNotAllowedRouter is a RootRouter that supports registering a handler for requests whose method is not allowed.
+// The routers returned by New implement this interface. The handler is only used if the RouterImpl of the router
+// implements MethodNotAllowedRouterImpl.
+type MethodNotAllowedRouter interface {
+	RootRouter
+
+	// RegisterMethodNotAllowedHandler registers a handler to produce 405 responses for requests whose path matches a
+	// registered route but whose method does not. The "Allow" header of the response is set to the methods registered
+	// for the path before the handler is invoked. If no handler is registered, a plain 405 response is written.
+	// OPTIONS requests for such paths are answered automatically with a 200 response with the "Allow" header set.
+	// It should be called after all middlewares are added to the router.
+	RegisterMethodNotAllowedHandler(handler http.Handler)
+}
+
 type rootRouter struct {
-	// impl stores the underlying RouterImpl used to route requests.
-	impl RouterImpl
+	// mu serializes changes to the routes and middleware of this router. Requests are routed without acquiring it.
+	mu sync.Mutex
+
+	// table stores the routing table that is used to route requests. Once the table has been shared with requests,
+	// changes to the routes and middleware of this router are applied to a copy of the table that replaces it (see
+	// updateTable). Every request stores the table that was current when it was received in its context and is served
+	// using that table, so requests that are in flight are not affected by changes.
+	table atomic.Pointer[routeTable]
+
+	// shared is true once the table may have been read by a request or a caller of RegisteredRoutes or
+	// RegisteredRouteInfos. Until then, new routes are registered with the RouterImpl of the table in place, which
+	// avoids rebuilding the RouterImpl for every route registered while the server starts.
+	shared atomic.Bool
 
 	// reqHandlers specifies the handlers that run for every request received by the router. Every request received by
 	// the router (including requests to methods/paths that are not registered on the router) is handled by these
-	// handlers in order before being handled by the underlying RouterImpl.
+	// handlers in order before being handled by the underlying RouterImpl. Only accessed while holding mu.
 	reqHandlers []RequestHandlerMiddleware
+}
 
-	// routeHandlers specifies the handlers that are run for all of the routes that are registered on this router.
-	// Requests that are routed to a registered route on this router are handled by these handlers in order before being
+// routeTable stores the routes registered on a router and the RouterImpl that routes requests to them.
+type routeTable struct {
+	// impl stores the underlying RouterImpl used to route requests.
+	impl RouterImpl
+
+	// handler stores the http.Handler created by chaining all of the request handlers of the router with impl. This is
+	// done because this http.Handler is called on every request and the request handlers rarely change, so it is much
+	// more efficient to cache the handler rather than creating a chained one on every request.
+	handler http.Handler
+
+	// routes stores all of the routes that are registered on the router in sorted order.
+	routes []RouteSpec
+
+	// registrationOrder stores all of the routes that are registered on the router in the order in which they were
+	// registered. Routes are registered with new RouterImpls in this order, so RouterImpl implementations that give
+	// precedence to the routes registered first (such as gorilla/mux) route requests in the same way once rebuilt.
+	registrationOrder []RouteSpec
+
+	// registeredRoutes stores the information about every route registered on the router keyed by spec. Routes with
+	// the same spec differ in their conditions and are stored in the order in which requests are matched against them.
+	registeredRoutes map[RouteSpec][]*registeredRoute
+
+	// routeSegments stores the path segments of every path template registered on the router. Used to register routes
+	// with impl and to determine the methods that are allowed for a request path that does not match a registered
+	// route.
+	routeSegments map[string][]PathSegment
+
+	// routeHandlers specifies the handlers that are run for all of the routes that are registered on the router.
+	// Requests that are routed to a registered route on the router are handled by these handlers in order before being
 	// handled by the registered handler.
 	routeHandlers []RouteHandlerMiddleware
 
-	// routes stores all of the routes that are registered on this router.
-	routes []RouteSpec
+	// notFoundHandler and methodNotAllowedHandler store the handlers registered using RegisterNotFoundHandler and
+	// RegisterMethodNotAllowedHandler wrapped with the route handlers. May be nil.
+	notFoundHandler         http.Handler
+	methodNotAllowedHandler http.Handler
+}
 
-	// cachedHandler stores the http.Handler created by chaining all of the request handlers in reqHandlers with the
-	// request handler provided by impl. This is done because this http.Handler is called on every request and
-	// reqHandlers rarely changes, so it is much more efficient to cache the handler rather than creating a chained one
-	// on every request.
-	cachedHandler http.Handler
+type routeTableContextKeyType string
+
+const routeTableContextKey = routeTableContextKeyType("wrouterRouteTable")
+
+// copy returns a copy of the table that can be changed without affecting this table. The slices of registered routes
+// and route handlers are not copied since changes replace them rather than modifying them.
+func (t *routeTable) copy() *routeTable {
+	tableCopy := &routeTable{
+		impl:                    t.impl,
+		handler:                 t.handler,
+		routes:                  append([]RouteSpec(nil), t.routes...),
+		registrationOrder:       append([]RouteSpec(nil), t.registrationOrder...),
+		registeredRoutes:        make(map[RouteSpec][]*registeredRoute, len(t.registeredRoutes)),
+		routeSegments:           make(map[string][]PathSegment, len(t.routeSegments)),
+		routeHandlers:           t.routeHandlers,
+		notFoundHandler:         t.notFoundHandler,
+		methodNotAllowedHandler: t.methodNotAllowedHandler,
+	}
+	for k, v := range t.registeredRoutes {
+		tableCopy.registeredRoutes[k] = v
+	}
+	for k, v := range t.routeSegments {
+		tableCopy.routeSegments[k] = v
+	}
+	return tableCopy
 }
 
 func New(impl RouterImpl, params ...RootRouterParam) RootRouter {
-	r := &rootRouter{
-		impl: impl,
-	}
+	r := &rootRouter{}
+	r.table.Store(&routeTable{
+		impl:             impl,
+		registeredRoutes: make(map[RouteSpec][]*registeredRoute),
+		routeSegments:    make(map[string][]PathSegment),
+	})
+	r.registerMethodNotAllowedHandler(impl)
 	for _, p := range params {
 		if p == nil {
 			continue
 		}
 		p.configure(r)
 	}
-	r.updateCachedHandler()
+	r.table.Load().handler = createRequestHandler(impl, r.reqHandlers)
 
 	return r
 }
@@ -95,12 +205,115 @@ func RootRouterParamAddRouteHandlerMiddleware(routeReqHandler ...RouteHandlerMid
 	})
 }
 
-func (r *rootRouter) updateCachedHandler() {
-	r.cachedHandler = createRequestHandler(r.impl, r.reqHandlers)
+func (r *rootRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !r.shared.Load() {
+		// wait for any change that registers routes with the RouterImpl of the table in place to complete. Once the
+		// table is shared, changes no longer modify it.
+		r.mu.Lock()
+		r.shared.Store(true)
+		r.mu.Unlock()
+	}
+	t := r.table.Load()
+	t.handler.ServeHTTP(w, req.WithContext(context.WithValue(req.Context(), routeTableContextKey, t)))
 }
 
-func (r *rootRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	r.cachedHandler.ServeHTTP(w, req)
+// requestTable returns the table that was current when the provided request was received. Falls back to the current
+// table if the request handler middleware replaced the context of the request with one that does not derive from it.
+func (r *rootRouter) requestTable(req *http.Request) *routeTable {
+	if t, ok := req.Context().Value(routeTableContextKey).(*routeTable); ok {
+		return t
+	}
+	return r.table.Load()
+}
+
+// updateTable applies the provided change to a copy of the routing table and replaces the table with the copy if the
+// change succeeds. If the change adds routes with new specs or the first not found handler and the table has not been
+// shared with requests yet, they are registered with the RouterImpl of the table. Otherwise, if the change adds or
+// removes route specs or adds the first not found handler, the copy uses a new RouterImpl with all of its routes and
+// handlers registered, since RouterImpl implementations do not support removing routes or registering routes while they
+// route requests.
+func (r *rootRouter) updateTable(change func(t *routeTable) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	curr := r.table.Load()
+	next := curr.copy()
+	if err := change(next); err != nil {
+		return err
+	}
+	added, removed := diffRouteSpecs(curr.routes, next.routes)
+	addedNotFoundHandler := curr.notFoundHandler == nil && next.notFoundHandler != nil
+	switch {
+	case !r.shared.Load() && len(removed) == 0:
+		for _, routeSpec := range added {
+			r.registerWithImpl(next.impl, routeSpec, next.routeSegments[routeSpec.PathTemplate])
+		}
+		if addedNotFoundHandler {
+			next.impl.RegisterNotFoundHandler(http.HandlerFunc(r.serveNotFound))
+		}
+	case len(added) > 0 || len(removed) > 0 || addedNotFoundHandler:
+		impl, err := r.newImpl(next)
+		switch {
+		case err == nil:
+			next.impl = impl
+		case len(added) == 0 && len(removed) == 0:
+			// the RouterImpl cannot be rebuilt, so the not found handler can only be registered with the RouterImpl
+			// that is routing requests
+			next.impl.RegisterNotFoundHandler(http.HandlerFunc(r.serveNotFound))
+		default:
+			return err
+		}
+	}
+	next.handler = createRequestHandler(next.impl, r.reqHandlers)
+	r.table.Store(next)
+	return nil
+}
+
+// newImpl returns a new RouterImpl created from the RouterImpl of the provided table with the routes of the table and
+// the handlers of this router registered.
+func (r *rootRouter) newImpl(t *routeTable) (RouterImpl, error) {
+	rebuildable, ok := t.impl.(RebuildableRouterImpl)
+	if !ok {
+		return nil, fmt.Errorf("router implementation %T does not support removing routes or adding routes once the router has started routing requests", t.impl)
+	}
+	impl := rebuildable.NewEmpty()
+	r.registerMethodNotAllowedHandler(impl)
+	if t.notFoundHandler != nil {
+		impl.RegisterNotFoundHandler(http.HandlerFunc(r.serveNotFound))
+	}
+	for _, routeSpec := range t.registrationOrder {
+		r.registerWithImpl(impl, routeSpec, t.routeSegments[routeSpec.PathTemplate])
+	}
+	return impl, nil
+}
+
+// registerMethodNotAllowedHandler registers the handler of this router for requests whose method is not allowed with
+// the provided RouterImpl if it supports such a handler.
+func (r *rootRouter) registerMethodNotAllowedHandler(impl RouterImpl) {
+	if methodNotAllowedImpl, ok := impl.(MethodNotAllowedRouterImpl); ok {
+		methodNotAllowedImpl.RegisterMethodNotAllowedHandler(http.HandlerFunc(r.handleMethodNotAllowed))
+	}
+}
+
+// diffRouteSpecs returns the specs that are in next but not in curr and the specs that are in curr but not in next.
+func diffRouteSpecs(curr, next []RouteSpec) (added, removed []RouteSpec) {
+	currSpecs := make(map[RouteSpec]struct{}, len(curr))
+	for _, routeSpec := range curr {
+		currSpecs[routeSpec] = struct{}{}
+	}
+	for _, routeSpec := range next {
+		if _, ok := currSpecs[routeSpec]; ok {
+			delete(currSpecs, routeSpec)
+			continue
+		}
+		added = append(added, routeSpec)
+	}
+	for _, routeSpec := range curr {
+		if _, ok := currSpecs[routeSpec]; ok {
+			removed = append(removed, routeSpec)
+		}
+	}
+	return added, removed
 }
 
 func (r *rootRouter) Register(method, path string, handler http.Handler, params ...RouteParam) error {
@@ -119,43 +332,250 @@ func (r *rootRouter) Register(method, path string, handler http.Handler, params
 		return err
 	}
 
-	var pathVarNames []string
-	for _, segment := range pathTemplate.Segments() {
-		if segment.Type == LiteralSegment {
+	routeSpec := RouteSpec{
+		Method:       method,
+		PathTemplate: pathTemplate.Template(),
+	}
+	requestParamPerms := b.toRequestParamPerms()
+	metricTags := b.toMetricTags()
+	metadata := Metadata{values: b.metadata}
+
+	route := &registeredRoute{
+		info: RouteInfo{
+			Spec:                  routeSpec,
+			ParamPerms:            requestParamPerms,
+			MetricTags:            metricTags,
+			DisableTelemetry:      b.disableTelemetry,
+			DisableCompression:    b.disableCompression,
+			DecompressRequestBody: b.decompressBody,
+			Timeout:               b.timeout,
+			MaxBodySize:           b.maxBodySize,
+			Conditions:            b.conditions,
+			Deprecation:           b.deprecation,
+			ETag:                  b.etag,
+			CurrentETag:           b.currentETag,
+			Cache:                 b.cache,
+			CoalesceRequests:      b.coalesce,
+			Doc:                   b.doc,
+			Metadata:              metadata,
+		},
+		builder: b,
+		serve: func(w http.ResponseWriter, req *http.Request, routeHandlers []RouteHandlerMiddleware, pathParamVals map[string]string) {
+			req = req.WithContext(context.WithValue(req.Context(), pathParamsContextKey, pathParamVals))
+			if metadata.Len() > 0 {
+				req = req.WithContext(context.WithValue(req.Context(), metadataContextKey, metadata))
+			}
+
+			wrappedHandlerFn := createRouteRequestHandler(func(rw http.ResponseWriter, r *http.Request, reqVals RequestVals) {
+				handler.ServeHTTP(rw, r)
+			}, routeHandlerChain(routeHandlers, b))
+
+			wrappedHandlerFn(w, req, RequestVals{
+				Spec:                  routeSpec,
+				PathParamVals:         pathParamVals,
+				ParamPerms:            requestParamPerms,
+				MetricTags:            metricTags,
+				DisableTelemetry:      b.disableTelemetry,
+				DisableCompression:    b.disableCompression,
+				DecompressRequestBody: b.decompressBody,
+				Timeout:               b.timeout,
+				MaxBodySize:           b.maxBodySize,
+				Conditions:            b.conditions,
+				Deprecation:           b.deprecation,
+				ETag:                  b.etag,
+				CurrentETag:           b.currentETag,
+				Cache:                 b.cache,
+				CoalesceRequests:      b.coalesce,
+				Metadata:              metadata,
+			})
+		},
+	}
+
+	return r.updateTable(func(t *routeTable) error {
+		existingRoutes := t.registeredRoutes[routeSpec]
+		if len(existingRoutes) == 0 {
+			if err := checkRouteConflicts(t.impl, t.routes, t.routeSegments, method, routeSpec.PathTemplate, pathTemplate.Segments()); err != nil {
+				return err
+			}
+			t.routes = append(t.routes, routeSpec)
+			sort.Sort(routeSpecs(t.routes))
+			t.registrationOrder = append(t.registrationOrder, routeSpec)
+			t.registeredRoutes[routeSpec] = []*registeredRoute{route}
+			t.routeSegments[routeSpec.PathTemplate] = pathTemplate.Segments()
+			return nil
+		}
+		for i, existing := range existingRoutes {
+			if !existing.info.Conditions.equal(b.conditions) {
+				continue
+			}
+			if !b.replace {
+				return checkConditionConflict(existing.info, routeSpec, b.conditions)
+			}
+			replacedRoutes := append([]*registeredRoute(nil), existingRoutes...)
+			replacedRoutes[i] = route
+			t.registeredRoutes[routeSpec] = replacedRoutes
+			return nil
+		}
+		// the RouterImpl already routes requests for the path template to the handler that selects between the routes
+		t.registeredRoutes[routeSpec] = insertRoute(existingRoutes, route)
+		return nil
+	})
+}
+
+func (r *rootRouter) Unregister(method, path string, params ...RouteParam) error {
+	b := &routeParamBuilder{}
+	for _, param := range params {
+		if param == nil {
 			continue
 		}
-		pathVarNames = append(pathVarNames, segment.Value)
+		if err := param.apply(b); err != nil {
+			return err
+		}
 	}
 
+	pathTemplate, err := NewPathTemplate(path)
+	if err != nil {
+		return err
+	}
 	routeSpec := RouteSpec{
 		Method:       method,
 		PathTemplate: pathTemplate.Template(),
 	}
-	r.routes = append(r.routes, routeSpec)
-	sort.Sort(routeSpecs(r.routes))
 
-	requestParamPerms := b.toRequestParamPerms()
-	metricTags := b.toMetricTags()
+	return r.updateTable(func(t *routeTable) error {
+		existingRoutes := t.registeredRoutes[routeSpec]
+		remainingRoutes := make([]*registeredRoute, 0, len(existingRoutes))
+		for _, existing := range existingRoutes {
+			if !existing.info.Conditions.equal(b.conditions) {
+				remainingRoutes = append(remainingRoutes, existing)
+			}
+		}
+		if len(remainingRoutes) == len(existingRoutes) {
+			if b.conditions.IsEmpty() {
+				return fmt.Errorf("no route is registered for [%s] %s", routeSpec.Method, routeSpec.PathTemplate)
+			}
+			return fmt.Errorf("no route with the conditions %s is registered for [%s] %s", b.conditions, routeSpec.Method, routeSpec.PathTemplate)
+		}
+		if len(remainingRoutes) > 0 {
+			t.registeredRoutes[routeSpec] = remainingRoutes
+			return nil
+		}
 
-	// wrap provided handler with a handler that registers the path parameter information in the context
-	r.impl.Register(method, pathTemplate.Segments(), http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		// register path parameters in context
-		pathParamVals := r.impl.PathParams(req, pathVarNames)
-		req = req.WithContext(context.WithValue(req.Context(), pathParamsContextKey, pathParamVals))
-
-		wrappedHandlerFn := createRouteRequestHandler(func(rw http.ResponseWriter, r *http.Request, reqVals RequestVals) {
-			handler.ServeHTTP(rw, r)
-		}, append(r.routeHandlers, b.middleware...))
-
-		wrappedHandlerFn(w, req, RequestVals{
-			Spec:             routeSpec,
-			PathParamVals:    pathParamVals,
-			ParamPerms:       requestParamPerms,
-			MetricTags:       metricTags,
-			DisableTelemetry: b.disableTelemetry,
-		})
-	}))
-	return nil
+		delete(t.registeredRoutes, routeSpec)
+		pathTemplateInUse := false
+		remainingSpecs := make([]RouteSpec, 0, len(t.routes))
+		for _, currSpec := range t.routes {
+			if currSpec == routeSpec {
+				continue
+			}
+			remainingSpecs = append(remainingSpecs, currSpec)
+			pathTemplateInUse = pathTemplateInUse || currSpec.PathTemplate == routeSpec.PathTemplate
+		}
+		t.routes = remainingSpecs
+		remainingOrder := make([]RouteSpec, 0, len(t.registrationOrder))
+		for _, currSpec := range t.registrationOrder {
+			if currSpec != routeSpec {
+				remainingOrder = append(remainingOrder, currSpec)
+			}
+		}
+		t.registrationOrder = remainingOrder
+		if !pathTemplateInUse {
+			delete(t.routeSegments, routeSpec.PathTemplate)
+		}
+		return nil
+	})
+}
+
+// checkConditionConflict returns an error if a route with the provided spec and conditions conflicts with the provided
+// existing route with the same spec, which is the case if their conditions are equal.
+func checkConditionConflict(existing RouteInfo, routeSpec RouteSpec, conditions RouteConditions) error {
+	if !existing.Conditions.equal(conditions) {
+		return nil
+	}
+	if conditions.IsEmpty() {
+		return fmt.Errorf("route [%s] %s conflicts with existing route [%s] %s: the path templates are equivalent",
+			routeSpec.Method, routeSpec.PathTemplate, existing.Spec.Method, existing.Spec.PathTemplate)
+	}
+	return fmt.Errorf("route [%s] %s conflicts with existing route [%s] %s: the path templates are equivalent and both routes have the conditions %s",
+		routeSpec.Method, routeSpec.PathTemplate, existing.Spec.Method, existing.Spec.PathTemplate, conditions)
+}
+
+// insertRoute returns the result of adding the provided route to the provided routes with the same spec in the order in
+// which requests are matched against them: routes with more conditions before routes with fewer conditions, and routes
+// with the same number of conditions in the order in which they were registered.
+func insertRoute(routes []*registeredRoute, route *registeredRoute) []*registeredRoute {
+	idx := len(routes)
+	for idx > 0 && routes[idx-1].info.Conditions.count() < route.info.Conditions.count() {
+		idx--
+	}
+	result := make([]*registeredRoute, 0, len(routes)+1)
+	result = append(result, routes[:idx]...)
+	result = append(result, route)
+	return append(result, routes[idx:]...)
+}
+
+// registerWithImpl registers the handler for the routes with the provided spec with the provided RouterImpl. The
+// handler registers the path parameter information in the context and invokes the first route registered for the spec
+// in the routing table of the request whose conditions the request satisfies. If there is no such route, the request is
+// handled by the most specific other route with the same method whose path template matches the request and whose
+// conditions the request satisfies (see overlappingRoute), since the RouterImpl selects the path template before the
+// conditions are evaluated. Routes that the RouterImpl cannot register are rejected by checkRouteConflicts before they
+// are added to the table.
+func (r *rootRouter) registerWithImpl(impl RouterImpl, routeSpec RouteSpec, segments []PathSegment) {
+	var pathVarNames []string
+	for _, segment := range segments {
+		if segment.Type == LiteralSegment {
+			continue
+		}
+		pathVarNames = append(pathVarNames, segment.Value)
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		pathParamVals := impl.PathParams(req, pathVarNames)
+		t := r.requestTable(req)
+		// if the RouterImpl matched the route without enforcing its constraints, the route is treated as unmatched
+		if pathParamsSatisfyConstraints(segments, pathParamVals) {
+			for _, route := range t.registeredRoutes[routeSpec] {
+				if route.info.Conditions.matches(req) {
+					route.serve(w, req, t.routeHandlers, pathParamVals)
+					return
+				}
+			}
+		}
+		if route, overlappingPathParamVals := t.overlappingRoute(req, routeSpec); route != nil {
+			route.serve(w, req, t.routeHandlers, overlappingPathParamVals)
+			return
+		}
+		r.serveNotFound(w, req)
+	})
+	impl.Register(routeSpec.Method, segments, handler)
+}
+
+// overlappingRoute returns the route that handles a request that the RouterImpl routed to the routes with the provided
+// spec even though none of them match it, along with the values of its path parameters. The route is the first route
+// whose conditions the request satisfies of the routes with the most specific path template (see moreSpecificSegments)
+// that has the same method as the spec and matches the request path. Returns nil if there is no such route.
+func (t *routeTable) overlappingRoute(req *http.Request, routeSpec RouteSpec) (*registeredRoute, map[string]string) {
+	var match *registeredRoute
+	var matchSegments []PathSegment
+	for _, currSpec := range t.routes {
+		if currSpec == routeSpec || currSpec.Method != routeSpec.Method {
+			continue
+		}
+		currSegments := t.routeSegments[currSpec.PathTemplate]
+		if !matchesPath(currSegments, req.URL.Path) || (match != nil && !moreSpecificSegments(currSegments, matchSegments)) {
+			continue
+		}
+		for _, route := range t.registeredRoutes[currSpec] {
+			if route.info.Conditions.matches(req) {
+				match, matchSegments = route, currSegments
+				break
+			}
+		}
+	}
+	if match == nil {
+		return nil, nil
+	}
+	return match, pathParamValues(matchSegments, req.URL.Path)
 }
 
 func (r *routeRequestHandlerWithNext) HandleRequest(rw http.ResponseWriter, req *http.Request, reqVals RequestVals) {
@@ -163,11 +583,43 @@ func (r *routeRequestHandlerWithNext) HandleRequest(rw http.ResponseWriter, req
 }
 
 func (r *rootRouter) RegisteredRoutes() []RouteSpec {
-	ris := make([]RouteSpec, len(r.routes))
-	copy(ris, r.routes)
+	routes := r.table.Load().routes
+	ris := make([]RouteSpec, len(routes))
+	copy(ris, routes)
 	return ris
 }
 
+// registeredRoute stores the information about a registered route, the builder for its params, which is used to
+// determine the middleware for the route when it is requested (since middleware may be added to subrouters after the
+// route is registered), and the function that serves requests that match the route.
+type registeredRoute struct {
+	info    RouteInfo
+	builder *routeParamBuilder
+	serve   func(w http.ResponseWriter, req *http.Request, routeHandlers []RouteHandlerMiddleware, pathParamVals map[string]string)
+}
+
+func (r *rootRouter) RegisteredRouteInfos() []RouteInfo {
+	t := r.table.Load()
+	infos := make([]RouteInfo, 0, len(t.routes))
+	for _, routeSpec := range t.routes {
+		for _, route := range t.registeredRoutes[routeSpec] {
+			info := route.info
+			info.Middleware = route.builder.routeMiddleware()
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
+// routeHandlerChain returns the middleware that runs for a route with the provided params: the provided middleware added
+// to the root router followed by the middleware for the route.
+func routeHandlerChain(routeHandlers []RouteHandlerMiddleware, b *routeParamBuilder) []RouteHandlerMiddleware {
+	routeMiddleware := b.routeMiddleware()
+	chain := make([]RouteHandlerMiddleware, 0, len(routeHandlers)+len(routeMiddleware))
+	chain = append(chain, routeHandlers...)
+	return append(chain, routeMiddleware...)
+}
+
 func (r *rootRouter) Get(path string, handler http.Handler, params ...RouteParam) error {
 	return r.Register(http.MethodGet, path, handler, params...)
 }
@@ -213,20 +665,45 @@ func (r *rootRouter) RootRouter() RootRouter {
 }
 
 func (r *rootRouter) AddRequestHandlerMiddleware(handlers ...RequestHandlerMiddleware) {
-	r.reqHandlers = append(r.reqHandlers, handlers...)
-	r.updateCachedHandler()
+	// the change cannot fail since it does not change the routes of the table
+	_ = r.updateTable(func(*routeTable) error {
+		r.reqHandlers = append(r.reqHandlers, handlers...)
+		return nil
+	})
 }
 
 func (r *rootRouter) AddRouteHandlerMiddleware(handlers ...RouteHandlerMiddleware) {
-	r.routeHandlers = append(r.routeHandlers, handlers...)
+	// the change cannot fail since it does not change the routes of the table
+	_ = r.updateTable(func(t *routeTable) error {
+		t.routeHandlers = append(append([]RouteHandlerMiddleware(nil), t.routeHandlers...), handlers...)
+		return nil
+	})
 }
 
 func (r *rootRouter) RegisterNotFoundHandler(handler http.Handler) {
+	// the change cannot fail since it does not change the routes of the table
+	_ = r.updateTable(func(t *routeTable) error {
+		t.notFoundHandler = unmatchedRouteHandler(handler, t.routeHandlers)
+		return nil
+	})
+}
+
+func (r *rootRouter) RegisterMethodNotAllowedHandler(handler http.Handler) {
+	// the change cannot fail since it does not change the routes of the table
+	_ = r.updateTable(func(t *routeTable) error {
+		t.methodNotAllowedHandler = unmatchedRouteHandler(handler, t.routeHandlers)
+		return nil
+	})
+}
+
+// unmatchedRouteHandler returns a handler that invokes the provided handler with the provided route handlers for
+// requests that do not match a registered route.
+func unmatchedRouteHandler(handler http.Handler, routeHandlers []RouteHandlerMiddleware) http.Handler {
 	wrappedHandlerFn := createRouteRequestHandler(func(rw http.ResponseWriter, r *http.Request, reqVals RequestVals) {
 		handler.ServeHTTP(rw, r)
-	}, r.routeHandlers)
+	}, routeHandlers)
 
-	r.impl.RegisterNotFoundHandler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
 		wrappedHandlerFn(rw, r, RequestVals{
 			Spec: RouteSpec{
 				Method:       r.Method,
@@ -240,7 +717,68 @@ func (r *rootRouter) RegisterNotFoundHandler(handler http.Handler) {
 			},
 			MetricTags: metrics.Tags{},
 		})
-	}))
+	})
+}
+
+// handleMethodNotAllowed handles requests that the RouterImpl matched to a registered path but not to a registered
+// method. The allowed methods are determined using the routes registered on this router so that the behavior is the
+// same for all RouterImpl implementations.
+func (r *rootRouter) handleMethodNotAllowed(rw http.ResponseWriter, req *http.Request) {
+	t := r.requestTable(req)
+	allowed := t.allowedMethods(req)
+	if len(allowed) == 0 {
+		// the RouterImpl may have set the Allow header based on routes whose conditions the request does not satisfy
+		rw.Header().Del("Allow")
+		r.serveNotFound(rw, req)
+		return
+	}
+	rw.Header().Set("Allow", strings.Join(allowed, ", "))
+
+	if req.Method == http.MethodOptions {
+		rw.WriteHeader(http.StatusOK)
+		return
+	}
+	if t.methodNotAllowedHandler != nil {
+		t.methodNotAllowedHandler.ServeHTTP(rw, req)
+		return
+	}
+	http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+func (r *rootRouter) serveNotFound(rw http.ResponseWriter, req *http.Request) {
+	if notFoundHandler := r.requestTable(req).notFoundHandler; notFoundHandler != nil {
+		notFoundHandler.ServeHTTP(rw, req)
+		return
+	}
+	http.NotFound(rw, req)
+}
+
+// allowedMethods returns the sorted methods of all of the routes whose path template matches the path of the provided
+// request and whose conditions the request satisfies. If any route matches, OPTIONS is always included in the result.
+func (t *routeTable) allowedMethods(req *http.Request) []string {
+	methods := make(map[string]struct{})
+	for _, routeSpec := range t.routes {
+		if _, ok := methods[routeSpec.Method]; ok || !matchesPath(t.routeSegments[routeSpec.PathTemplate], req.URL.Path) {
+			continue
+		}
+		for _, route := range t.registeredRoutes[routeSpec] {
+			if route.info.Conditions.matches(req) {
+				methods[routeSpec.Method] = struct{}{}
+				break
+			}
+		}
+	}
+	if len(methods) == 0 {
+		return nil
+	}
+	methods[http.MethodOptions] = struct{}{}
+
+	allowed := make([]string, 0, len(methods))
+	for method := range methods {
+		allowed = append(allowed, method)
+	}
+	sort.Strings(allowed)
+	return allowed
 }
 
 type requestHandlerWithNext struct {
@@ -19,17 +19,70 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 )
 
 type subrouter struct {
 	rPath   string
 	rParent Router
 	params  []RouteParam
+
+	// mu protects middleware, which is read on every request for the routes registered through this subrouter.
+	mu sync.RWMutex
+	// middleware stores the middleware added to this subrouter using AddRouteHandlerMiddleware. The slice is replaced
+	// rather than modified when middleware is added, so slices returned by currentMiddleware are never modified.
+	middleware []RouteHandlerMiddleware
 }
 
 func (s *subrouter) Register(method, path string, handler http.Handler, params ...RouteParam) error {
 	rootRouter, basePath := s.getRootRouterAndPath()
-	return rootRouter.Register(method, fmt.Sprint(basePath, path), handler, append(s.params, params...)...)
+	allParams := make([]RouteParam, 0, len(s.params)+len(params)+1)
+	allParams = append(allParams, s.subroutersParam())
+	allParams = append(allParams, s.params...)
+	allParams = append(allParams, params...)
+	return rootRouter.Register(method, fmt.Sprint(basePath, path), handler, allParams...)
+}
+
+func (s *subrouter) Unregister(method, path string, params ...RouteParam) error {
+	rootRouter, basePath := s.getRootRouterAndPath()
+	allParams := make([]RouteParam, 0, len(s.params)+len(params))
+	allParams = append(allParams, s.params...)
+	allParams = append(allParams, params...)
+	unregisteringRouter, ok := rootRouter.(UnregisteringRouter)
+	if !ok {
+		return fmt.Errorf("router %T does not support removing routes", rootRouter)
+	}
+	return unregisteringRouter.Unregister(method, fmt.Sprint(basePath, path), allParams...)
+}
+
+func (s *subrouter) AddRouteHandlerMiddleware(handlers ...RouteHandlerMiddleware) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	middleware := make([]RouteHandlerMiddleware, 0, len(s.middleware)+len(handlers))
+	middleware = append(middleware, s.middleware...)
+	s.middleware = append(middleware, handlers...)
+}
+
+// currentMiddleware returns the middleware added to this subrouter. The returned slice must not be modified.
+func (s *subrouter) currentMiddleware() []RouteHandlerMiddleware {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.middleware
+}
+
+// subroutersParam returns a RouteParam that records this subrouter and its ancestor subrouters so that the middleware
+// added to them runs for the route.
+func (s *subrouter) subroutersParam() RouteParam {
+	var subrouters []*subrouter
+	for curr := Router(s); curr != nil; curr = curr.Parent() {
+		if currSubrouter, ok := curr.(*subrouter); ok {
+			subrouters = append([]*subrouter{currSubrouter}, subrouters...)
+		}
+	}
+	return routeParamFunc(func(b *routeParamBuilder) error {
+		b.subrouters = subrouters
+		return nil
+	})
 }
 
 func (s *subrouter) RegisteredRoutes() []RouteSpec {
@@ -21,13 +21,19 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/palantir/pkg/metrics"
 	// underscore import to use zap implementation
 	_ "github.com/palantir/witchcraft-go-logging/wlog-zap"
 	"github.com/palantir/witchcraft-go-server/v2/wrouter"
 	"github.com/palantir/witchcraft-go-server/v2/wrouter/wgorillamux"
 	"github.com/palantir/witchcraft-go-server/v2/wrouter/whttprouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/wradix"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -39,6 +45,7 @@ func TestRouterImpls(t *testing.T) {
 	}{
 		{"wgorillamux", wgorillamux.New()},
 		{"whttprouter", whttprouter.New()},
+		{"wradix", wradix.New()},
 	} {
 		// create router
 		r := wrouter.New(tc.impl, nil)
@@ -114,6 +121,7 @@ func TestRouterImplSmoke(t *testing.T) {
 	}{
 		{"wgorillamux", wgorillamux.New()},
 		{"whttprouter", whttprouter.New()},
+		{"wradix", wradix.New()},
 	} {
 		func() {
 			// create router
@@ -272,6 +280,7 @@ func TestRouterImplRouteHandling(t *testing.T) {
 		}{
 			{"wgorillamux", wgorillamux.New()},
 			{"whttprouter", whttprouter.New()},
+			{"wradix", wradix.New()},
 		} {
 			func() {
 				// create router
@@ -377,3 +386,1224 @@ func TestRouterMiddlewareRegistration(t *testing.T) {
 		require.Equal(t, "[global handler]", string(body))
 	})
 }
+
+func TestRegisteredRouteInfos(t *testing.T) {
+	noopMiddleware := func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+		next(rw, req, reqVals)
+	}
+	tags := metrics.Tags{metrics.MustNewTag("endpoint", "getDataset")}
+
+	r := wrouter.New(whttprouter.New())
+	sub := r.Subrouter("/datasets", wrouter.RouteMiddleware(noopMiddleware), wrouter.ForbiddenHeaderParams("Authorization"))
+	require.NoError(t, sub.Get("/{rid}", http.NotFoundHandler(),
+		wrouter.SafePathParams("rid"),
+		wrouter.SafeQueryParams("Branch", "token"),
+		wrouter.ForbiddenQueryParams("token"),
+		wrouter.MetricTags(tags),
+	))
+	require.NoError(t, r.Get("/status", http.NotFoundHandler(), wrouter.DisableTelemetry()))
+
+	infos := wrouter.RegisteredRouteInfos(r)
+	require.Len(t, infos, 2)
+
+	assert.Equal(t, wrouter.RouteSpec{Method: http.MethodGet, PathTemplate: "/datasets/{rid}"}, infos[0].Spec)
+	assert.Equal(t, tags, infos[0].MetricTags)
+	assert.False(t, infos[0].DisableTelemetry)
+	assert.Len(t, infos[0].Middleware, 1)
+	safe, forbidden := wrouter.ParamPermsNames(infos[0].ParamPerms.PathParamPerms())
+	assert.Equal(t, []string{"rid"}, safe)
+	assert.Empty(t, forbidden)
+	safe, forbidden = wrouter.ParamPermsNames(infos[0].ParamPerms.QueryParamPerms())
+	assert.Equal(t, []string{"branch"}, safe)
+	assert.Equal(t, []string{"token"}, forbidden)
+	safe, forbidden = wrouter.ParamPermsNames(infos[0].ParamPerms.HeaderParamPerms())
+	assert.Empty(t, safe)
+	assert.Equal(t, []string{"authorization"}, forbidden)
+
+	assert.Equal(t, wrouter.RouteSpec{Method: http.MethodGet, PathTemplate: "/status"}, infos[1].Spec)
+	assert.True(t, infos[1].DisableTelemetry)
+	assert.Empty(t, infos[1].Middleware)
+}
+
+var (
+	auditCategoryKey = wrouter.NewMetadataKey[string]("auditCategory")
+	scopesKey        = wrouter.NewMetadataKey[[]string]("scopes")
+)
+
+func TestRouteMetadata(t *testing.T) {
+	var middlewareScopes []string
+	r := wrouter.New(whttprouter.New(), wrouter.RootRouterParamAddRouteHandlerMiddleware(
+		func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+			middlewareScopes, _ = scopesKey.Value(reqVals.Metadata)
+			next(rw, req, reqVals)
+		},
+	))
+	echoCategoryHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		category, ok := auditCategoryKey.FromContext(req.Context())
+		_, _ = fmt.Fprint(rw, category, ok)
+	})
+	admin := r.Subrouter("/admin", wrouter.RouteMetadata(auditCategoryKey, "admin"), wrouter.RouteMetadata(scopesKey, []string{"admin"}))
+	require.NoError(t, admin.Get("/users", echoCategoryHandler))
+	require.NoError(t, admin.Get("/audit", echoCategoryHandler, wrouter.RouteMetadata(auditCategoryKey, "audit")))
+	require.NoError(t, r.Get("/public", echoCategoryHandler))
+
+	for _, tc := range []struct {
+		path       string
+		wantBody   string
+		wantScopes []string
+	}{
+		{"/admin/users", "admintrue", []string{"admin"}},
+		{"/admin/audit", "audittrue", []string{"admin"}},
+		{"/public", "false", nil},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			middlewareScopes = nil
+			rw := httptest.NewRecorder()
+			r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			assert.Equal(t, tc.wantBody, rw.Body.String())
+			assert.Equal(t, tc.wantScopes, middlewareScopes)
+		})
+	}
+
+	infos := wrouter.RegisteredRouteInfos(r)
+	require.Len(t, infos, 3)
+	assert.Equal(t, map[string]string{"auditCategory": "audit", "scopes": "[admin]"}, infos[0].Metadata.Strings())
+	assert.Equal(t, 0, infos[2].Metadata.Len())
+}
+
+// Tests that routes with the same method and path template are selected based on their host and header conditions on
+// all router implementations.
+func TestRouteConditions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		impl wrouter.RouterImpl
+	}{
+		{"wgorillamux", wgorillamux.New()},
+		{"whttprouter", whttprouter.New()},
+		{"wradix", wradix.New()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotTags metrics.Tags
+			r := wrouter.New(tc.impl, wrouter.RootRouterParamAddRouteHandlerMiddleware(
+				func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+					gotTags = reqVals.MetricTags
+					next(rw, req, reqVals)
+				},
+			))
+			newHandler := func(name string) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					_, _ = fmt.Fprint(rw, name, ":", wrouter.PathParams(req)["id"])
+				})
+			}
+			api := r.Subrouter("", wrouter.MatchHost("api.example.com"))
+			require.NoError(t, api.Get("/items/{id}", newHandler("api")))
+			require.NoError(t, api.Get("/items/{id}", newHandler("api-v2"), wrouter.MatchHeader("x-api-version", "2")))
+			require.NoError(t, r.Get("/items/{id}", newHandler("default")))
+			require.NoError(t, r.Get("/items/{id}", newHandler("tenants"), wrouter.MatchHost("*.tenants.example.com")))
+
+			for _, test := range []struct {
+				host        string
+				header      string
+				wantBody    string
+				wantHostTag string
+			}{
+				{"api.example.com", "", "api:1", "api.example.com"},
+				{"API.example.com:8443", "", "api:1", "api.example.com"},
+				{"api.example.com", "2", "api-v2:1", "api.example.com"},
+				{"api.example.com", "3", "api:1", "api.example.com"},
+				{"a.tenants.example.com", "", "tenants:1", "_.tenants.example.com"},
+				{"a.b.tenants.example.com", "", "default:1", ""},
+				{"localhost", "2", "default:1", ""},
+			} {
+				req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+				req.Host = test.host
+				if test.header != "" {
+					req.Header.Set("X-Api-Version", test.header)
+				}
+				rw := httptest.NewRecorder()
+				r.ServeHTTP(rw, req)
+				assert.Equal(t, test.wantBody, rw.Body.String(), "host %s, header %q", test.host, test.header)
+				hostTag, _ := gotTags.ToMap()[strings.ToLower(wrouter.HostRouteTagName)]
+				assert.Equal(t, test.wantHostTag, hostTag, "host %s, header %q", test.host, test.header)
+			}
+
+			assert.Equal(t, []wrouter.RouteSpec{{Method: http.MethodGet, PathTemplate: "/items/{id}"}}, r.RegisteredRoutes())
+			var conditions []string
+			for _, info := range wrouter.RegisteredRouteInfos(r) {
+				conditions = append(conditions, info.Conditions.String())
+			}
+			assert.Equal(t, []string{"host=api.example.com, X-Api-Version=2", "host=api.example.com", "host=*.tenants.example.com", ""}, conditions)
+		})
+	}
+}
+
+// Tests that a request whose path matches the path templates of multiple routes with different conditions is handled by
+// a route whose conditions it satisfies even if the RouterImpl selects a path template of routes whose conditions it
+// does not satisfy, and that RouterImpls that do not resolve overlapping path templates reject such routes.
+func TestRouteConditionsAcrossPathTemplates(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		impl    wrouter.RouterImpl
+		wantErr string
+	}{
+		{"wgorillamux", wgorillamux.New(), "route [GET] /api/status conflicts with existing route [GET] /api/{x}: the path templates match some of the same paths"},
+		{"whttprouter", whttprouter.New(), "route [GET] /api/status conflicts with existing route [GET] /api/{x}: httprouter does not support a path parameter segment and a different segment at the same position"},
+		{"wradix", wradix.New(), ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := wrouter.New(tc.impl)
+			newHandler := func(name string) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					_, _ = fmt.Fprint(rw, name, ":", wrouter.PathParams(req))
+				})
+			}
+			require.NoError(t, r.Get("/api/{x}", newHandler("a"), wrouter.MatchHost("a.example.com")))
+			err := r.Get("/api/status", newHandler("b"), wrouter.MatchHost("b.example.com"))
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, r.Get("/api/{rest*}", newHandler("c"), wrouter.MatchHost("c.example.com")))
+
+			for _, test := range []struct {
+				host       string
+				path       string
+				wantStatus int
+				wantBody   string
+			}{
+				{"a.example.com", "/api/status", http.StatusOK, "a:map[x:status]"},
+				{"a.example.com", "/api/other", http.StatusOK, "a:map[x:other]"},
+				{"b.example.com", "/api/status", http.StatusOK, "b:map[]"},
+				{"b.example.com", "/api/other", http.StatusNotFound, "404 page not found\n"},
+				{"c.example.com", "/api/status", http.StatusOK, "c:map[rest:status]"},
+				{"c.example.com", "/api/a/b", http.StatusOK, "c:map[rest:a/b]"},
+				{"d.example.com", "/api/status", http.StatusNotFound, "404 page not found\n"},
+			} {
+				req := httptest.NewRequest(http.MethodGet, test.path, nil)
+				req.Host = test.host
+				rw := httptest.NewRecorder()
+				r.ServeHTTP(rw, req)
+				assert.Equal(t, test.wantStatus, rw.Code, "host %s, path %s", test.host, test.path)
+				assert.Equal(t, test.wantBody, rw.Body.String(), "host %s, path %s", test.host, test.path)
+			}
+		})
+	}
+}
+
+func TestRouteConditionsWithoutFallback(t *testing.T) {
+	r := wrouter.New(wradix.New())
+	require.NoError(t, r.Get("/items", http.NotFoundHandler(), wrouter.MatchHost("api.example.com")))
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Host = "other.example.com"
+	rw := httptest.NewRecorder()
+	r.ServeHTTP(rw, req)
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}
+
+func TestRouteConditionConflicts(t *testing.T) {
+	r := wrouter.New(whttprouter.New())
+	require.NoError(t, r.Get("/items", http.NotFoundHandler(), wrouter.MatchHost("api.example.com"), wrouter.MatchHeader("X-Api-Version", "2")))
+	require.NoError(t, r.Get("/items", http.NotFoundHandler()))
+
+	assert.EqualError(t, r.Get("/items", http.NotFoundHandler(), wrouter.MatchHeader("x-api-version", "2"), wrouter.MatchHost("API.example.com")),
+		"route [GET] /items conflicts with existing route [GET] /items: the path templates are equivalent and both routes have the conditions host=api.example.com, X-Api-Version=2")
+	assert.EqualError(t, r.Get("/items", http.NotFoundHandler()),
+		"route [GET] /items conflicts with existing route [GET] /items: the path templates are equivalent")
+	assert.EqualError(t, r.Get("/{name}", http.NotFoundHandler(), wrouter.MatchHost("other.example.com")),
+		"route [GET] /{name} conflicts with existing route [GET] /items: httprouter does not support a path parameter segment and a different segment at the same position")
+	assert.EqualError(t, r.Get("/other", http.NotFoundHandler(), wrouter.MatchHost("*.example..com")),
+		`host pattern "*.example..com" contains an empty label`)
+	assert.EqualError(t, r.Get("/other", http.NotFoundHandler(), wrouter.MatchHost("api_example.com")),
+		`host pattern "api_example.com" contains invalid character '_'`)
+}
+
+func TestRouteTimeout(t *testing.T) {
+	var gotTimeout time.Duration
+	r := wrouter.New(whttprouter.New(), wrouter.RootRouterParamAddRouteHandlerMiddleware(
+		func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+			gotTimeout = reqVals.Timeout
+			next(rw, req, reqVals)
+		},
+	))
+	require.NoError(t, r.Get("/slow", http.NotFoundHandler(), wrouter.RouteTimeout(time.Second)))
+	require.NoError(t, r.Get("/default", http.NotFoundHandler()))
+	require.EqualError(t, r.Get("/invalid", http.NotFoundHandler(), wrouter.RouteTimeout(0)), "route timeout must be positive: 0s")
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, time.Second, gotTimeout)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/default", nil))
+	assert.Equal(t, time.Duration(0), gotTimeout)
+
+	infos := wrouter.RegisteredRouteInfos(r)
+	require.Len(t, infos, 2)
+	assert.Equal(t, time.Duration(0), infos[0].Timeout)
+	assert.Equal(t, time.Second, infos[1].Timeout)
+}
+
+func TestRouteMaxBodySize(t *testing.T) {
+	var gotMaxBodySize int64
+	r := wrouter.New(whttprouter.New(), wrouter.RootRouterParamAddRouteHandlerMiddleware(
+		func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+			gotMaxBodySize = reqVals.MaxBodySize
+			next(rw, req, reqVals)
+		},
+	))
+	require.NoError(t, r.Post("/upload", http.NotFoundHandler(), wrouter.RouteMaxBodySize(1024)))
+	require.EqualError(t, r.Post("/invalid", http.NotFoundHandler(), wrouter.RouteMaxBodySize(-1)), "route max body size must be positive: -1")
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/upload", nil))
+	assert.Equal(t, int64(1024), gotMaxBodySize)
+
+	infos := wrouter.RegisteredRouteInfos(r)
+	require.Len(t, infos, 1)
+	assert.Equal(t, int64(1024), infos[0].MaxBodySize)
+}
+
+func TestRouteDisableCompression(t *testing.T) {
+	var gotDisableCompression []bool
+	r := wrouter.New(whttprouter.New(), wrouter.RootRouterParamAddRouteHandlerMiddleware(
+		func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+			gotDisableCompression = append(gotDisableCompression, reqVals.DisableCompression)
+			next(rw, req, reqVals)
+		},
+	))
+	require.NoError(t, r.Get("/compressed", http.NotFoundHandler()))
+	require.NoError(t, r.Get("/uncompressed", http.NotFoundHandler(), wrouter.DisableCompression()))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/compressed", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/uncompressed", nil))
+	assert.Equal(t, []bool{false, true}, gotDisableCompression)
+
+	infos := wrouter.RegisteredRouteInfos(r)
+	require.Len(t, infos, 2)
+	assert.False(t, infos[0].DisableCompression)
+	assert.True(t, infos[1].DisableCompression)
+}
+
+func TestRouteDecompressRequestBody(t *testing.T) {
+	var gotDecompressRequestBody bool
+	r := wrouter.New(whttprouter.New(), wrouter.RootRouterParamAddRouteHandlerMiddleware(
+		func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+			gotDecompressRequestBody = reqVals.DecompressRequestBody
+			next(rw, req, reqVals)
+		},
+	))
+	require.NoError(t, r.Post("/upload", http.NotFoundHandler(), wrouter.DecompressRequestBody()))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/upload", nil))
+	assert.True(t, gotDecompressRequestBody)
+
+	infos := wrouter.RegisteredRouteInfos(r)
+	require.Len(t, infos, 1)
+	assert.True(t, infos[0].DecompressRequestBody)
+}
+
+func TestRouteETag(t *testing.T) {
+	r := wrouter.New(whttprouter.New())
+	require.NoError(t, r.Get("/computed", http.NotFoundHandler(), wrouter.RouteETag()))
+	require.NoError(t, r.Put("/current", http.NotFoundHandler(), wrouter.RouteCurrentETag(func(req *http.Request) (string, error) {
+		return "v1", nil
+	})))
+	require.EqualError(t, r.Put("/invalid", http.NotFoundHandler(), wrouter.RouteCurrentETag(nil)), "route current ETag function must not be nil")
+
+	infos := wrouter.RegisteredRouteInfos(r)
+	require.Len(t, infos, 2)
+	assert.True(t, infos[0].ETag)
+	assert.Nil(t, infos[0].CurrentETag)
+	assert.True(t, infos[1].ETag)
+	assert.NotNil(t, infos[1].CurrentETag)
+}
+
+func TestRouteCached(t *testing.T) {
+	var gotCache *wrouter.RouteCache
+	r := wrouter.New(whttprouter.New(), wrouter.RootRouterParamAddRouteHandlerMiddleware(
+		func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+			gotCache = reqVals.Cache
+			next(rw, req, reqVals)
+		},
+	))
+	cache := wrouter.RouteCache{Name: "reports", QueryParams: []string{"format"}}
+	require.NoError(t, r.Get("/reports", http.NotFoundHandler(), wrouter.RouteCached(cache)))
+	require.EqualError(t, r.Get("/invalid", http.NotFoundHandler(), wrouter.RouteCached(wrouter.RouteCache{})), "route cache name must not be empty")
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/reports", nil))
+	require.NotNil(t, gotCache)
+	assert.Equal(t, cache, *gotCache)
+
+	infos := wrouter.RegisteredRouteInfos(r)
+	require.Len(t, infos, 1)
+	assert.Equal(t, &cache, infos[0].Cache)
+}
+
+func TestRouteCoalesceRequests(t *testing.T) {
+	var gotCoalesceRequests bool
+	r := wrouter.New(whttprouter.New(), wrouter.RootRouterParamAddRouteHandlerMiddleware(
+		func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+			gotCoalesceRequests = reqVals.CoalesceRequests
+			next(rw, req, reqVals)
+		},
+	))
+	require.NoError(t, r.Get("/reports", http.NotFoundHandler(), wrouter.CoalesceRequests()))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/reports", nil))
+	assert.True(t, gotCoalesceRequests)
+
+	infos := wrouter.RegisteredRouteInfos(r)
+	require.Len(t, infos, 1)
+	assert.True(t, infos[0].CoalesceRequests)
+}
+
+func TestRouteDeprecated(t *testing.T) {
+	var gotDeprecation *wrouter.RouteDeprecation
+	r := wrouter.New(whttprouter.New(), wrouter.RootRouterParamAddRouteHandlerMiddleware(
+		func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+			gotDeprecation = reqVals.Deprecation
+			next(rw, req, reqVals)
+		},
+	))
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	deprecation := wrouter.RouteDeprecation{Date: date, Sunset: sunset, RejectAfterSunset: true}
+	require.NoError(t, r.Get("/old", http.NotFoundHandler(), wrouter.RouteDeprecated(deprecation)))
+	require.NoError(t, r.Get("/current", http.NotFoundHandler()))
+	require.EqualError(t, r.Get("/no-sunset", http.NotFoundHandler(), wrouter.RouteDeprecated(wrouter.RouteDeprecation{RejectAfterSunset: true})),
+		"route deprecation that rejects requests after the sunset must have a sunset")
+	require.EqualError(t, r.Get("/early-sunset", http.NotFoundHandler(), wrouter.RouteDeprecated(wrouter.RouteDeprecation{Date: sunset, Sunset: date})),
+		"route sunset 2026-01-01T00:00:00Z is before its deprecation date 2026-06-01T00:00:00Z")
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/old", nil))
+	require.NotNil(t, gotDeprecation)
+	assert.Equal(t, deprecation, *gotDeprecation)
+	assert.False(t, gotDeprecation.IsSunset(sunset))
+	assert.True(t, gotDeprecation.IsSunset(sunset.Add(time.Second)))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/current", nil))
+	assert.Nil(t, gotDeprecation)
+}
+
+// Tests that middleware added to subrouters runs for the routes registered through them (including routes registered
+// before the middleware was added) in order after the root middleware and before the route middleware.
+func TestSubrouterMiddleware(t *testing.T) {
+	type ctxKey struct{}
+	newMarkingMiddleware := func(marking string) wrouter.RouteHandlerMiddleware {
+		return func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+			curr, _ := req.Context().Value(ctxKey{}).([]string)
+			req = req.WithContext(context.WithValue(req.Context(), ctxKey{}, append(curr, marking)))
+			next(rw, req, reqVals)
+		}
+	}
+	echoMarkingHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(fmt.Sprint(req.Context().Value(ctxKey{}))))
+	})
+
+	r := wrouter.New(whttprouter.New())
+	api := r.Subrouter("/api", wrouter.RouteMiddleware(newMarkingMiddleware("subrouterParam")))
+	admin := api.Subrouter("/admin")
+	require.NoError(t, admin.Get("/users", echoMarkingHandler, wrouter.RouteMiddleware(newMarkingMiddleware("route"))))
+	require.NoError(t, api.Get("/datasets", echoMarkingHandler, wrouter.RouteMiddleware(newMarkingMiddleware("route"))))
+	require.NoError(t, r.Get("/api/other", echoMarkingHandler))
+
+	// middleware added after the routes are registered
+	r.AddRouteHandlerMiddleware(newMarkingMiddleware("global"))
+	admin.(wrouter.MiddlewareRouter).AddRouteHandlerMiddleware(newMarkingMiddleware("admin"))
+	api.(wrouter.MiddlewareRouter).AddRouteHandlerMiddleware(newMarkingMiddleware("api1"), newMarkingMiddleware("api2"))
+
+	for _, tc := range []struct {
+		path string
+		want string
+	}{
+		{"/api/admin/users", "[global api1 api2 admin route]"},
+		{"/api/datasets", "[global api1 api2 subrouterParam route]"},
+		{"/api/other", "[global]"},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			assert.Equal(t, tc.want, rw.Body.String())
+		})
+	}
+
+	infos := wrouter.RegisteredRouteInfos(r)
+	require.Len(t, infos, 3)
+	assert.Equal(t, "/api/admin/users", infos[0].Spec.PathTemplate)
+	assert.Len(t, infos[0].Middleware, 4)
+}
+
+// Tests that requests to registered paths with unregistered methods result in 405 responses and that OPTIONS requests
+// are answered using the registered routes, and that the behavior is the same for all router implementations.
+func TestRouterImplMethodNotAllowed(t *testing.T) {
+	for _, routerImpl := range []struct {
+		name string
+		impl wrouter.RouterImpl
+	}{
+		{"wgorillamux", wgorillamux.New()},
+		{"whttprouter", whttprouter.New()},
+		{"wradix", wradix.New()},
+	} {
+		t.Run(routerImpl.name, func(t *testing.T) {
+			r := wrouter.New(routerImpl.impl)
+			okHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusOK)
+			})
+			require.NoError(t, r.Get("/datasets/{rid}", okHandler))
+			require.NoError(t, r.Put("/datasets/{rid}", okHandler))
+			require.NoError(t, r.Delete("/datasets/{rid}", okHandler))
+			require.NoError(t, r.Post("/files/{path*}", okHandler))
+			require.NoError(t, r.Register(http.MethodOptions, "/custom", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusTeapot)
+			})))
+			r.RegisterNotFoundHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusNotFound)
+			}))
+			r.(wrouter.MethodNotAllowedRouter).RegisterMethodNotAllowedHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusMethodNotAllowed)
+				_, _ = rw.Write([]byte("custom 405"))
+			}))
+
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			for _, tc := range []struct {
+				name       string
+				method     string
+				path       string
+				wantStatus int
+				wantAllow  string
+				wantBody   string
+			}{
+				{"registered method", http.MethodGet, "/datasets/id-500", http.StatusOK, "", ""},
+				{"unregistered method", http.MethodPost, "/datasets/id-500", http.StatusMethodNotAllowed, "DELETE, GET, OPTIONS, PUT", "custom 405"},
+				{"unregistered method trailing param", http.MethodGet, "/files/var/data.txt", http.StatusMethodNotAllowed, "OPTIONS, POST", "custom 405"},
+				{"options", http.MethodOptions, "/datasets/id-500", http.StatusOK, "DELETE, GET, OPTIONS, PUT", ""},
+				{"options trailing param", http.MethodOptions, "/files/var/data.txt", http.StatusOK, "OPTIONS, POST", ""},
+				{"registered options", http.MethodOptions, "/custom", http.StatusTeapot, "", ""},
+				{"unregistered path", http.MethodGet, "/unknown", http.StatusNotFound, "", ""},
+				{"options unregistered path", http.MethodOptions, "/unknown", http.StatusNotFound, "", ""},
+			} {
+				t.Run(tc.name, func(t *testing.T) {
+					req, err := http.NewRequest(tc.method, server.URL+tc.path, nil)
+					require.NoError(t, err)
+					resp, err := http.DefaultClient.Do(req)
+					require.NoError(t, err)
+					defer func() { _ = resp.Body.Close() }()
+					body, err := io.ReadAll(resp.Body)
+					require.NoError(t, err)
+
+					assert.Equal(t, tc.wantStatus, resp.StatusCode)
+					assert.Equal(t, tc.wantAllow, resp.Header.Get("Allow"))
+					assert.Equal(t, tc.wantBody, string(body))
+				})
+			}
+		})
+	}
+}
+
+func TestRouterImplDefaultMethodNotAllowed(t *testing.T) {
+	for _, routerImpl := range []struct {
+		name string
+		impl wrouter.RouterImpl
+	}{
+		{"wgorillamux", wgorillamux.New()},
+		{"whttprouter", whttprouter.New()},
+		{"wradix", wradix.New()},
+	} {
+		t.Run(routerImpl.name, func(t *testing.T) {
+			r := wrouter.New(routerImpl.impl)
+			require.NoError(t, r.Get("/foo", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})))
+
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			resp, err := http.Post(server.URL+"/foo", "text/plain", nil)
+			require.NoError(t, err)
+			defer func() { _ = resp.Body.Close() }()
+			assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+			assert.Equal(t, "GET, OPTIONS", resp.Header.Get("Allow"))
+		})
+	}
+}
+
+// Tests that the methods in the Allow header of OPTIONS and 405 responses only include the methods of the routes whose
+// conditions the request satisfies.
+func TestMethodNotAllowedRouteConditions(t *testing.T) {
+	for _, routerImpl := range []struct {
+		name string
+		impl wrouter.RouterImpl
+	}{
+		{"wgorillamux", wgorillamux.New()},
+		{"whttprouter", whttprouter.New()},
+		{"wradix", wradix.New()},
+	} {
+		t.Run(routerImpl.name, func(t *testing.T) {
+			r := wrouter.New(routerImpl.impl)
+			okHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+			require.NoError(t, r.Get("/items", okHandler))
+			require.NoError(t, r.Put("/items", okHandler, wrouter.MatchHost("api.example.com")))
+			require.NoError(t, r.Delete("/items", okHandler, wrouter.MatchHeader("X-Admin", "true")))
+			require.NoError(t, r.Post("/admin", okHandler, wrouter.MatchHost("api.example.com")))
+
+			for _, tc := range []struct {
+				method     string
+				path       string
+				host       string
+				admin      bool
+				wantStatus int
+				wantAllow  string
+			}{
+				{http.MethodOptions, "/items", "localhost", false, http.StatusOK, "GET, OPTIONS"},
+				{http.MethodOptions, "/items", "api.example.com", false, http.StatusOK, "GET, OPTIONS, PUT"},
+				{http.MethodOptions, "/items", "localhost", true, http.StatusOK, "DELETE, GET, OPTIONS"},
+				{http.MethodPost, "/items", "localhost", false, http.StatusMethodNotAllowed, "GET, OPTIONS"},
+				{http.MethodPost, "/items", "api.example.com", true, http.StatusMethodNotAllowed, "DELETE, GET, OPTIONS, PUT"},
+				{http.MethodOptions, "/admin", "api.example.com", false, http.StatusOK, "OPTIONS, POST"},
+				{http.MethodOptions, "/admin", "localhost", false, http.StatusNotFound, ""},
+				{http.MethodGet, "/admin", "localhost", false, http.StatusNotFound, ""},
+			} {
+				req := httptest.NewRequest(tc.method, tc.path, nil)
+				req.Host = tc.host
+				if tc.admin {
+					req.Header.Set("X-Admin", "true")
+				}
+				rw := httptest.NewRecorder()
+				r.ServeHTTP(rw, req)
+				assert.Equal(t, tc.wantStatus, rw.Code, "[%s] %s host %s", tc.method, tc.path, tc.host)
+				assert.Equal(t, tc.wantAllow, rw.Header().Get("Allow"), "[%s] %s host %s", tc.method, tc.path, tc.host)
+			}
+		})
+	}
+}
+
+// Tests that a RouterImpl that does not implement MethodNotAllowedRouterImpl can be used with the root router, in which
+// case requests whose method is not allowed are handled by the RouterImpl.
+func TestRouterImplWithoutMethodNotAllowedHandler(t *testing.T) {
+	r := wrouter.New(struct{ wrouter.RouterImpl }{whttprouter.New()})
+	require.NoError(t, r.Get("/foo", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusNoContent)
+	})))
+	r.(wrouter.MethodNotAllowedRouter).RegisterMethodNotAllowedHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusTeapot)
+	}))
+
+	rw := httptest.NewRecorder()
+	r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/foo", nil))
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+
+	rw = httptest.NewRecorder()
+	r.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/foo", nil))
+	assert.NotEqual(t, http.StatusTeapot, rw.Code)
+}
+
+// Tests that path parameter constraints are enforced and that typed path parameter accessors return parsed values on
+// all router implementations.
+func TestRouterImplPathParamConstraints(t *testing.T) {
+	for _, routerImpl := range []struct {
+		name string
+		impl wrouter.RouterImpl
+	}{
+		{"wgorillamux", wgorillamux.New()},
+		{"whttprouter", whttprouter.New()},
+		{"wradix", wradix.New()},
+	} {
+		t.Run(routerImpl.name, func(t *testing.T) {
+			r := wrouter.New(routerImpl.impl)
+			require.NoError(t, r.Get("/datasets/{id:int}", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				id, err := wrouter.PathParamInt(req, "id")
+				require.NoError(t, err)
+				_, _ = fmt.Fprintf(rw, "dataset %d", id)
+			})))
+			require.NoError(t, r.Get("/files/{fileId:uuid}", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				fileID, err := wrouter.PathParamUUID(req, "fileId")
+				require.NoError(t, err)
+				_, _ = fmt.Fprintf(rw, "file %s", fileID)
+			})))
+			require.NoError(t, r.Get("/users/{name:[a-z]+}", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = fmt.Fprintf(rw, "user %s", wrouter.PathParams(req)["name"])
+			})))
+			require.NoError(t, r.Get("/charts/{kind:(bar|line)}/{version:v(?P<major>[0-9]+)}", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = fmt.Fprintf(rw, "%s %s", wrouter.PathParams(req)["kind"], wrouter.PathParams(req)["version"])
+			})))
+			require.NoError(t, r.Get("/names/{name:.+}/{suffix:[^a]+}", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_, _ = fmt.Fprintf(rw, "name %s %s", wrouter.PathParams(req)["name"], wrouter.PathParams(req)["suffix"])
+			})))
+			r.RegisterNotFoundHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusNotFound)
+			}))
+
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			for _, tc := range []struct {
+				method     string
+				path       string
+				wantStatus int
+				wantBody   string
+			}{
+				{http.MethodGet, "/datasets/42", http.StatusOK, "dataset 42"},
+				{http.MethodGet, "/names/a.b/c", http.StatusOK, "name a.b c"},
+				{http.MethodGet, "/names/a/b/c", http.StatusNotFound, ""},
+				{http.MethodGet, "/names/a/b/c/d", http.StatusNotFound, ""},
+				{http.MethodGet, "/datasets/abc", http.StatusNotFound, ""},
+				{http.MethodGet, "/datasets/99999999999999999999", http.StatusNotFound, ""},
+				{http.MethodGet, "/files/0D8BA6D2-9B0F-4A8C-8C42-35D1D1A2B6E0", http.StatusOK, "file 0d8ba6d2-9b0f-4a8c-8c42-35d1d1a2b6e0"},
+				{http.MethodGet, "/files/not-a-uuid", http.StatusNotFound, ""},
+				{http.MethodGet, "/users/alice", http.StatusOK, "user alice"},
+				{http.MethodGet, "/users/Alice", http.StatusNotFound, ""},
+				{http.MethodGet, "/charts/bar/v2", http.StatusOK, "bar v2"},
+				{http.MethodGet, "/charts/line/v10", http.StatusOK, "line v10"},
+				{http.MethodGet, "/charts/pie/v2", http.StatusNotFound, ""},
+				{http.MethodGet, "/charts/bar/2", http.StatusNotFound, ""},
+				{http.MethodPost, "/datasets/42", http.StatusMethodNotAllowed, "Method Not Allowed\n"},
+				{http.MethodPost, "/datasets/abc", http.StatusNotFound, ""},
+			} {
+				req, err := http.NewRequest(tc.method, server.URL+tc.path, nil)
+				require.NoError(t, err)
+				resp, err := http.DefaultClient.Do(req)
+				require.NoError(t, err)
+				body, err := io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantStatus, resp.StatusCode, "%s %s", tc.method, tc.path)
+				assert.Equal(t, tc.wantBody, string(body), "%s %s", tc.method, tc.path)
+			}
+		})
+	}
+}
+
+// Tests that conflicting routes are rejected by all router implementations, that routes whose path templates overlap are
+// rejected unless the router implementation resolves them, and that router implementations that cannot register routes
+// return an error rather than panicking.
+func TestRouterImplRouteConflicts(t *testing.T) {
+	const (
+		equivalentErr  = "the path templates are equivalent"
+		overlappingErr = "the path templates match some of the same paths"
+		httprouterErr  = "httprouter does not support a path parameter segment and a different segment at the same position"
+	)
+	for _, tc := range []struct {
+		name     string
+		existing []string
+		method   string
+		path     string
+		// wantErrs stores the reason of the expected error for each router implementation. No error is expected for
+		// router implementations that are not present.
+		wantErrs map[string]string
+	}{
+		{
+			name:     "duplicate route",
+			existing: []string{"/a/b"},
+			method:   http.MethodGet,
+			path:     "/a/b",
+			wantErrs: map[string]string{"wgorillamux": equivalentErr, "whttprouter": equivalentErr, "wradix": equivalentErr, "default": equivalentErr},
+		},
+		{
+			name:     "path params with different names",
+			existing: []string{"/a/{x}"},
+			method:   http.MethodGet,
+			path:     "/a/{y}",
+			wantErrs: map[string]string{"wgorillamux": equivalentErr, "whttprouter": equivalentErr, "wradix": equivalentErr, "default": equivalentErr},
+		},
+		{
+			name:     "literal and trailing path param",
+			existing: []string{"/a/b"},
+			method:   http.MethodGet,
+			path:     "/a/{rest*}",
+			wantErrs: map[string]string{"wgorillamux": overlappingErr, "whttprouter": httprouterErr, "default": overlappingErr},
+		},
+		{
+			name:     "root and trailing path param",
+			existing: []string{"/"},
+			method:   http.MethodGet,
+			path:     "/{rest*}",
+			wantErrs: map[string]string{"whttprouter": httprouterErr},
+		},
+		{
+			name:     "trailing path param and longer path",
+			existing: []string{"/a/{rest*}"},
+			method:   http.MethodGet,
+			path:     "/a/{x}/c",
+			wantErrs: map[string]string{"wgorillamux": overlappingErr, "whttprouter": httprouterErr, "default": overlappingErr},
+		},
+		{
+			name:     "literal and path param",
+			existing: []string{"/a/latest"},
+			method:   http.MethodGet,
+			path:     "/a/{id}",
+			wantErrs: map[string]string{"wgorillamux": overlappingErr, "whttprouter": httprouterErr, "default": overlappingErr},
+		},
+		{
+			name:     "literal and constrained path param",
+			existing: []string{"/a/latest"},
+			method:   http.MethodGet,
+			path:     "/a/{id:int}",
+			wantErrs: map[string]string{"whttprouter": httprouterErr},
+		},
+		{
+			name:     "path params with different names in different paths",
+			existing: []string{"/a/{x}/b"},
+			method:   http.MethodGet,
+			path:     "/a/{y}/c",
+			wantErrs: map[string]string{"whttprouter": httprouterErr},
+		},
+		{
+			name:     "path params with different constraints",
+			existing: []string{"/a/{id:int}"},
+			method:   http.MethodGet,
+			path:     "/a/{id:uuid}",
+			wantErrs: map[string]string{
+				"whttprouter": "httprouter does not support path templates that only differ in their path parameter constraints",
+			},
+		},
+		{
+			name:     "path params with overlapping constraints",
+			existing: []string{"/a/{id:int}"},
+			method:   http.MethodGet,
+			path:     "/a/{id:[0-9]+}",
+			wantErrs: map[string]string{
+				"wgorillamux": overlappingErr,
+				"whttprouter": "httprouter does not support path templates that only differ in their path parameter constraints",
+				"wradix":      overlappingErr,
+				"default":     overlappingErr,
+			},
+		},
+		{
+			name:     "constrained and unconstrained path params",
+			existing: []string{"/a/{id:int}"},
+			method:   http.MethodGet,
+			path:     "/a/{name}",
+			wantErrs: map[string]string{"wgorillamux": overlappingErr, "whttprouter": httprouterErr, "default": overlappingErr},
+		},
+		{
+			name:     "literal and path param in different positions",
+			existing: []string{"/a/{x}/c"},
+			method:   http.MethodGet,
+			path:     "/a/b/{y}",
+			wantErrs: map[string]string{"wgorillamux": overlappingErr, "whttprouter": httprouterErr, "default": overlappingErr},
+		},
+		{
+			name:     "different methods",
+			existing: []string{"/a/{x}"},
+			method:   http.MethodPost,
+			path:     "/a/{y}",
+		},
+		{
+			name:     "different lengths",
+			existing: []string{"/a/{x}"},
+			method:   http.MethodGet,
+			path:     "/a/{x}/{y}",
+		},
+		{
+			name:     "trailing path param and parent path",
+			existing: []string{"/a"},
+			method:   http.MethodGet,
+			path:     "/a/{rest*}",
+		},
+	} {
+		for _, routerImpl := range []struct {
+			name string
+			impl wrouter.RouterImpl
+		}{
+			{"wgorillamux", wgorillamux.New()},
+			{"whttprouter", whttprouter.New()},
+			{"wradix", wradix.New()},
+			// a RouterImpl that does not implement ConflictCheckingRouterImpl or OverlapResolvingRouterImpl
+			{"default", struct{ wrouter.RouterImpl }{wradix.New()}},
+		} {
+			t.Run(tc.name+" "+routerImpl.name, func(t *testing.T) {
+				r := wrouter.New(routerImpl.impl)
+				for _, path := range tc.existing {
+					require.NoError(t, r.Get(path, http.NotFoundHandler()))
+				}
+				err := r.Register(tc.method, tc.path, http.NotFoundHandler())
+				wantErr, ok := tc.wantErrs[routerImpl.name]
+				if !ok {
+					assert.NoError(t, err)
+					return
+				}
+				assert.EqualError(t, err, fmt.Sprintf("route [%s] %s conflicts with existing route [GET] %s: %s", tc.method, tc.path, tc.existing[0], wantErr))
+				assert.Len(t, r.RegisteredRoutes(), len(tc.existing))
+			})
+		}
+	}
+}
+
+// Tests that router implementations that resolve overlapping routes route requests to them in the same way before and
+// after the router implementation is rebuilt.
+func TestRouterImplOverlappingRoutes(t *testing.T) {
+	for _, routerImpl := range []struct {
+		name string
+		impl wrouter.RouterImpl
+	}{
+		{"wradix", wradix.New()},
+	} {
+		t.Run(routerImpl.name, func(t *testing.T) {
+			r := wrouter.New(routerImpl.impl)
+			for _, path := range []string{"/a/{id}", "/a/latest", "/a/{rest*}"} {
+				path := path
+				require.NoError(t, r.Get(path, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					_, _ = io.WriteString(rw, path)
+				})))
+			}
+			doRequests := func() map[string]string {
+				bodies := make(map[string]string)
+				for _, path := range []string{"/a/latest", "/a/42", "/a/b/c", "/a/latest/c"} {
+					rw := httptest.NewRecorder()
+					r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, path, nil))
+					bodies[path] = rw.Body.String()
+				}
+				return bodies
+			}
+			before := doRequests()
+			assert.Equal(t, "/a/latest", before["/a/latest"])
+			assert.Equal(t, "/a/{id}", before["/a/42"])
+			assert.Equal(t, "/a/{rest*}", before["/a/b/c"])
+
+			// adding a route once requests are routed rebuilds the RouterImpl
+			require.NoError(t, r.Post("/b", http.NotFoundHandler()))
+			assert.Equal(t, before, doRequests())
+		})
+	}
+}
+
+// Tests that routes can be added, replaced and removed after the router has started routing requests, and that the
+// behavior is the same for all router implementations.
+func TestRouterImplDynamicRoutes(t *testing.T) {
+	for _, routerImpl := range []struct {
+		name string
+		impl wrouter.RouterImpl
+	}{
+		{"wgorillamux", wgorillamux.New()},
+		{"whttprouter", whttprouter.New()},
+		{"wradix", wradix.New()},
+	} {
+		t.Run(routerImpl.name, func(t *testing.T) {
+			r := wrouter.New(routerImpl.impl)
+			r.RegisterNotFoundHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusNotFound)
+				_, _ = rw.Write([]byte("custom 404"))
+			}))
+			textHandler := func(text string) http.Handler {
+				return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+					_, _ = rw.Write([]byte(text))
+				})
+			}
+			api := r.Subrouter("/api")
+			require.NoError(t, api.Get("/widgets", textHandler("widgets")))
+
+			doRequest := func(method, path string) (int, string) {
+				rw := httptest.NewRecorder()
+				r.ServeHTTP(rw, httptest.NewRequest(method, path, nil))
+				return rw.Code, rw.Body.String()
+			}
+			assertResponse := func(t *testing.T, method, path string, wantStatus int, wantBody string) {
+				status, body := doRequest(method, path)
+				assert.Equal(t, wantStatus, status)
+				assert.Equal(t, wantBody, body)
+			}
+			assertResponse(t, http.MethodGet, "/api/widgets", http.StatusOK, "widgets")
+
+			// add routes after the router has started routing requests
+			require.NoError(t, api.Get("/gadgets/{id}", textHandler("gadget")))
+			require.NoError(t, api.Post("/widgets", textHandler("created")))
+			assertResponse(t, http.MethodGet, "/api/gadgets/1", http.StatusOK, "gadget")
+			assertResponse(t, http.MethodPost, "/api/widgets", http.StatusOK, "created")
+			assert.Equal(t, []wrouter.RouteSpec{
+				{Method: http.MethodGet, PathTemplate: "/api/gadgets/{id}"},
+				{Method: http.MethodGet, PathTemplate: "/api/widgets"},
+				{Method: http.MethodPost, PathTemplate: "/api/widgets"},
+			}, r.RegisteredRoutes())
+
+			// routes that conflict are rejected unless they replace the existing route
+			require.EqualError(t, api.Get("/widgets", textHandler("replaced")),
+				"route [GET] /api/widgets conflicts with existing route [GET] /api/widgets: the path templates are equivalent")
+			require.NoError(t, api.Get("/widgets", textHandler("replaced"), wrouter.ReplaceExistingRoute()))
+			assertResponse(t, http.MethodGet, "/api/widgets", http.StatusOK, "replaced")
+
+			// remove routes
+			require.NoError(t, api.(wrouter.UnregisteringRouter).Unregister(http.MethodPost, "/widgets"))
+			assertResponse(t, http.MethodPost, "/api/widgets", http.StatusMethodNotAllowed, "Method Not Allowed\n")
+			require.NoError(t, r.(wrouter.UnregisteringRouter).Unregister(http.MethodGet, "/api/gadgets/{id}"))
+			assertResponse(t, http.MethodGet, "/api/gadgets/1", http.StatusNotFound, "custom 404")
+			require.EqualError(t, r.(wrouter.UnregisteringRouter).Unregister(http.MethodGet, "/api/gadgets/{id}"), "no route is registered for [GET] /api/gadgets/{id}")
+			assert.Equal(t, []wrouter.RouteSpec{{Method: http.MethodGet, PathTemplate: "/api/widgets"}}, r.RegisteredRoutes())
+
+			// a removed path template can be registered again with different path parameter names
+			require.NoError(t, api.Get("/gadgets/{gadgetId}", textHandler("gadget again")))
+			assertResponse(t, http.MethodGet, "/api/gadgets/1", http.StatusOK, "gadget again")
+		})
+	}
+}
+
+// Tests that routes with conditions are replaced and removed individually.
+func TestDynamicRouteConditions(t *testing.T) {
+	r := wrouter.New(wradix.New())
+	textHandler := func(text string) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			_, _ = rw.Write([]byte(text))
+		})
+	}
+	require.NoError(t, r.Get("/widgets", textHandler("default")))
+	require.NoError(t, r.Get("/widgets", textHandler("v2"), wrouter.MatchHeader("X-Api-Version", "2")))
+	doRequest := func(version string) string {
+		req := httptest.NewRequest(http.MethodGet, "/widgets", nil)
+		req.Header.Set("X-Api-Version", version)
+		rw := httptest.NewRecorder()
+		r.ServeHTTP(rw, req)
+		return rw.Body.String()
+	}
+	assert.Equal(t, "v2", doRequest("2"))
+
+	require.NoError(t, r.Get("/widgets", textHandler("v2 replaced"), wrouter.MatchHeader("X-Api-Version", "2"), wrouter.ReplaceExistingRoute()))
+	assert.Equal(t, "v2 replaced", doRequest("2"))
+	assert.Equal(t, "default", doRequest("1"))
+
+	require.EqualError(t, r.(wrouter.UnregisteringRouter).Unregister(http.MethodGet, "/widgets", wrouter.MatchHeader("X-Api-Version", "3")),
+		"no route with the conditions X-Api-Version=3 is registered for [GET] /widgets")
+	require.NoError(t, r.(wrouter.UnregisteringRouter).Unregister(http.MethodGet, "/widgets", wrouter.MatchHeader("X-Api-Version", "2")))
+	assert.Equal(t, "default", doRequest("2"))
+	assert.Len(t, wrouter.RegisteredRouteInfos(r), 1)
+}
+
+// Tests that routes can be added and removed while requests are routed concurrently. Intended to be run with the race
+// detector.
+func TestDynamicRoutesConcurrentRequests(t *testing.T) {
+	r := wrouter.New(whttprouter.New())
+	require.NoError(t, r.Get("/stable", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})))
+
+	done := make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			rw := httptest.NewRecorder()
+			r.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/stable", nil))
+			if rw.Code != http.StatusOK {
+				errs <- fmt.Errorf("unexpected status %d", rw.Code)
+				return
+			}
+			_ = wrouter.RegisteredRouteInfos(r)
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		path := fmt.Sprintf("/dynamic/%d", i)
+		require.NoError(t, r.Get(path, http.NotFoundHandler()))
+		if i%2 == 0 {
+			require.NoError(t, r.(wrouter.UnregisteringRouter).Unregister(http.MethodGet, path))
+		}
+	}
+	close(done)
+	require.NoError(t, <-errs)
+	assert.Len(t, r.RegisteredRoutes(), 26)
+}
+
+// Tests that root routers whose RouterImpl cannot be rebuilt reject changes that require a new RouterImpl once they
+// have started routing requests.
+func TestDynamicRoutesNonRebuildableRouterImpl(t *testing.T) {
+	type nonRebuildableRouterImpl struct {
+		wrouter.RouterImpl
+	}
+	r := wrouter.New(nonRebuildableRouterImpl{RouterImpl: whttprouter.New()})
+	require.NoError(t, r.Get("/before", http.NotFoundHandler()))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/before", nil))
+
+	err := r.Get("/after", http.NotFoundHandler())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support removing routes or adding routes once the router has started routing requests")
+	// replacing a route does not require a new RouterImpl
+	assert.NoError(t, r.Get("/before", http.NotFoundHandler(), wrouter.ReplaceExistingRoute()))
+	assert.Len(t, r.RegisteredRoutes(), 1)
+}
+
+// BenchmarkRouterImpls benchmarks routing requests through a root router backed by each RouterImpl for a route table
+// typical of a service API.
+func BenchmarkRouterImpls(b *testing.B) {
+	routes := []string{
+		"/status/liveness",
+		"/status/readiness",
+		"/status/health",
+		"/catalog/datasets",
+		"/catalog/datasets/{datasetRid}",
+		"/catalog/datasets/{datasetRid}/branches",
+		"/catalog/datasets/{datasetRid}/branches/{branchId}",
+		"/catalog/datasets/{datasetRid}/transactions/{transactionRid}/files",
+		"/catalog/datasets/{datasetRid}/files/{filePath*}",
+		"/compass/resources/{rid}/children",
+		"/compass/resources/{rid}/path",
+		"/compass/folders/{folderRid}/children/{childRid}",
+		"/users/{userId}/groups",
+		"/users/{userId}/settings/{key}",
+		"/assets/{path*}",
+	}
+	requests := []struct {
+		name string
+		path string
+	}{
+		{"literal", "/status/readiness"},
+		{"param", "/catalog/datasets/ri.foundry.main.dataset.1/branches/master"},
+		{"trailing", "/catalog/datasets/ri.foundry.main.dataset.1/files/a/b/c/file.txt"},
+	}
+	for _, currCase := range []struct {
+		name string
+		impl func() wrouter.RouterImpl
+	}{
+		{"wgorillamux", func() wrouter.RouterImpl { return wgorillamux.New() }},
+		{"whttprouter", func() wrouter.RouterImpl { return whttprouter.New() }},
+		{"wradix", wradix.New},
+	} {
+		r := wrouter.New(currCase.impl())
+		for _, route := range routes {
+			require.NoError(b, r.Get(route, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				_ = wrouter.PathParams(req)
+			})))
+		}
+		for _, currReq := range requests {
+			req := httptest.NewRequest(http.MethodGet, currReq.path, nil)
+			b.Run(currCase.name+"/"+currReq.name, func(b *testing.B) {
+				rw := httptest.NewRecorder()
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					r.ServeHTTP(rw, req)
+				}
+			})
+		}
+	}
+}
+
+// Tests that RegisteredRouteInfos returns the specs of the registered routes for routers that do not implement
+// RouteInfoRouter.
+func TestRegisteredRouteInfosWithoutRouteInfoRouter(t *testing.T) {
+	r := wrouter.New(whttprouter.New())
+	require.NoError(t, r.Get("/foo", http.NotFoundHandler(), wrouter.DisableTelemetry()))
+
+	infos := wrouter.RegisteredRouteInfos(rootRouterWithoutRouteInfos{Router: r, root: r})
+	assert.Equal(t, []wrouter.RouteInfo{{Spec: wrouter.RouteSpec{Method: http.MethodGet, PathTemplate: "/foo"}}}, infos)
+	assert.True(t, wrouter.RegisteredRouteInfos(r)[0].DisableTelemetry)
+}
+
+// rootRouterWithoutRouteInfos is a RootRouter that does not implement RouteInfoRouter.
+type rootRouterWithoutRouteInfos struct {
+	wrouter.Router
+	root wrouter.RootRouter
+}
+
+func (r rootRouterWithoutRouteInfos) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	r.root.ServeHTTP(rw, req)
+}
+
+func (r rootRouterWithoutRouteInfos) AddRequestHandlerMiddleware(handlers ...wrouter.RequestHandlerMiddleware) {
+	r.root.AddRequestHandlerMiddleware(handlers...)
+}
+
+func (r rootRouterWithoutRouteInfos) AddRouteHandlerMiddleware(handlers ...wrouter.RouteHandlerMiddleware) {
+	r.root.AddRouteHandlerMiddleware(handlers...)
+}
+
+func (r rootRouterWithoutRouteInfos) RegisterNotFoundHandler(handler http.Handler) {
+	r.root.RegisterNotFoundHandler(handler)
+}
+
+// Tests that middleware can be added to a subrouter while requests for its routes are served.
+func TestSubrouterMiddlewareConcurrentRequests(t *testing.T) {
+	r := wrouter.New(whttprouter.New())
+	api := r.Subrouter("/api")
+	require.NoError(t, api.Get("/widgets", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			api.(wrouter.MiddlewareRouter).AddRouteHandlerMiddleware(func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+				next(rw, req, reqVals)
+			})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/widgets", nil))
+		}
+	}()
+	wg.Wait()
+	assert.Len(t, wrouter.RegisteredRouteInfos(r)[0].Middleware, 100)
+}
+
+func TestReplaceExistingRouteInFlightRequest(t *testing.T) {
+	routed := make(chan struct{})
+	replaced := make(chan struct{})
+	r := wrouter.New(whttprouter.New(), wrouter.RootRouterParamAddRequestHandlerMiddleware(func(rw http.ResponseWriter, req *http.Request, next http.Handler) {
+		if req.URL.Path == "/blocked" {
+			close(routed)
+			<-replaced
+		}
+		next.ServeHTTP(rw, req)
+	}))
+	require.NoError(t, r.Get("/widgets", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprint(rw, "old")
+	})))
+	require.NoError(t, r.Get("/blocked", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprint(rw, "blocked")
+	})))
+
+	// the request is received before the route is replaced and resumes once the replacement is complete
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req := httptest.NewRequest(http.MethodGet, "/blocked", nil)
+		r.ServeHTTP(rec, req)
+	}()
+	<-routed
+	require.NoError(t, r.Get("/blocked", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = fmt.Fprint(rw, "new")
+	}), wrouter.ReplaceExistingRoute()))
+	require.NoError(t, r.(wrouter.UnregisteringRouter).Unregister(http.MethodGet, "/widgets"))
+	close(replaced)
+	<-done
+	assert.Equal(t, "blocked", rec.Body.String())
+}
+
+func TestRootRouterHandlersConcurrentRequests(t *testing.T) {
+	r := wrouter.New(whttprouter.New())
+	require.NoError(t, r.Get("/widgets", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})))
+
+	var calls int32
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			r.AddRouteHandlerMiddleware(func(rw http.ResponseWriter, req *http.Request, reqVals wrouter.RequestVals, next wrouter.RouteRequestHandler) {
+				atomic.AddInt32(&calls, 1)
+				next(rw, req, reqVals)
+			})
+			r.RegisterNotFoundHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusNotFound)
+			}))
+			r.(wrouter.MethodNotAllowedRouter).RegisterMethodNotAllowedHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				rw.WriteHeader(http.StatusMethodNotAllowed)
+			}))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/widgets", nil))
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/gadgets", nil))
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/widgets", nil))
+		}
+	}()
+	wg.Wait()
+
+	atomic.StoreInt32(&calls, 0)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/widgets", nil))
+	assert.Equal(t, int32(100), atomic.LoadInt32(&calls))
+}
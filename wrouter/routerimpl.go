@@ -29,6 +29,8 @@ const (
 type PathSegment struct {
 	Type  SegmentType
 	Value string
+	// Constraint restricts the values matched by a PathParamSegment. Nil if the segment is not constrained.
+	Constraint PathParamConstraint
 }
 
 type RouterImpl interface {
@@ -48,3 +50,56 @@ type RouterImpl interface {
 	// If not provided, the implementation's default behavior is used (typically returns an http.Error with a 404 response code).
 	RegisterNotFoundHandler(handler http.Handler)
 }
+
+// MethodNotAllowedRouterImpl is a RouterImpl that can delegate requests whose path matches a registered route but whose
+// method does not to a handler. The root router uses this handler to write 405 responses with the "Allow" header set
+// and to answer OPTIONS requests. Root routers whose RouterImpl does not implement this interface leave such requests
+// to the default behavior of the RouterImpl. All of the RouterImpl implementations provided by this module implement
+// this interface.
+type MethodNotAllowedRouterImpl interface {
+	RouterImpl
+
+	// RegisterMethodNotAllowedHandler registers a handler that is used to handle any requests whose path matches a
+	// registered route but whose method does not match the method of any route registered for that path. This includes
+	// OPTIONS requests for paths that do not have a registered OPTIONS route: the implementation must not answer such
+	// requests itself.
+	RegisterMethodNotAllowedHandler(handler http.Handler)
+}
+
+// ConflictCheckingRouterImpl is a RouterImpl that cannot register some routes that the root router would otherwise
+// accept. Root routers whose RouterImpl implements this interface use it to check whether a new route conflicts with an
+// existing route with the same method before registering it, in addition to rejecting routes whose path templates are
+// equivalent or overlap (see OverlapResolvingRouterImpl).
+type ConflictCheckingRouterImpl interface {
+	RouterImpl
+
+	// CheckConflict returns an error if the RouterImpl cannot register routes with the same method and the provided
+	// path templates, which are not equivalent.
+	CheckConflict(existing, segments []PathSegment) error
+}
+
+// OverlapResolvingRouterImpl is a RouterImpl that routes requests for paths that match the path templates of multiple
+// routes in a way that does not depend on the order in which the routes were registered. Root routers reject a route
+// whose path template overlaps with the path template of an existing route with the same method unless their
+// RouterImpl implements this interface and resolves every overlap between the path templates.
+type OverlapResolvingRouterImpl interface {
+	RouterImpl
+
+	// ResolvesOverlap returns true if the RouterImpl routes requests for paths that match path templates with the
+	// provided overlap to one of the routes regardless of the order in which they were registered. Implementations
+	// should document the route that handles such requests for each overlap that they resolve.
+	ResolvesOverlap(overlap RouteOverlap) bool
+}
+
+// RebuildableRouterImpl is a RouterImpl that can create new, empty instances of itself. RouterImpl implementations are
+// not required to support removing routes or registering routes while they route requests, so the root router routes
+// requests using a new instance once routes are added or removed after it has started routing requests. Root routers
+// whose RouterImpl does not implement this interface return an error for such changes. All of the RouterImpl
+// implementations provided by this module implement this interface.
+type RebuildableRouterImpl interface {
+	RouterImpl
+
+	// NewEmpty returns a new RouterImpl that is configured in the same way as this one and has no registered routes or
+	// handlers.
+	NewEmpty() RouterImpl
+}
@@ -23,13 +23,15 @@ import (
 	"github.com/palantir/witchcraft-go-server/v2/wrouter"
 )
 
-// New returns a wrouter.RouterImpl backed by a new mux.Router configured using the provided parameters.
+// New returns a wrouter.RouterImpl backed by a new mux.Router configured using the provided parameters. mux.Router
+// routes requests for paths that match the path templates of multiple routes to the route that was registered first, so
+// the RouterImpl does not implement wrouter.OverlapResolvingRouterImpl and routes whose path templates overlap conflict.
 func New(params ...Param) wrouter.RouterImpl {
 	r := mux.NewRouter()
 	for _, p := range params {
 		p.apply(r)
 	}
-	return (*router)(r)
+	return &router{router: r, params: params}
 }
 
 type Param interface {
@@ -66,18 +68,32 @@ func UseEncodedPath() Param {
 	})
 }
 
-type router mux.Router
+type router struct {
+	router *mux.Router
+	// params stores the parameters with which the router was configured so that NewEmpty can configure new routers in
+	// the same way.
+	params []Param
+}
+
+// NewEmpty returns a new wrouter.RouterImpl backed by a new mux.Router configured using the same parameters as this one.
+func (r *router) NewEmpty() wrouter.RouterImpl {
+	return New(r.params...)
+}
 
 func (r *router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	(*mux.Router)(r).ServeHTTP(w, req)
+	r.router.ServeHTTP(w, req)
 }
 
 func (r *router) Register(method string, pathSegments []wrouter.PathSegment, handler http.Handler) {
-	(*mux.Router)(r).Path(r.convertPathParams(pathSegments)).Methods(method).Handler(handler)
+	r.router.Path(r.convertPathParams(pathSegments)).Methods(method).Handler(handler)
 }
 
 func (r *router) RegisterNotFoundHandler(handler http.Handler) {
-	(*mux.Router)(r).NotFoundHandler = handler
+	r.router.NotFoundHandler = handler
+}
+
+func (r *router) RegisterMethodNotAllowedHandler(handler http.Handler) {
+	r.router.MethodNotAllowedHandler = handler
 }
 
 func (r *router) PathParams(req *http.Request, pathVarNames []string) map[string]string {
@@ -99,6 +115,12 @@ func (r *router) convertPathParams(path []wrouter.PathSegment) string {
 		case wrouter.TrailingPathParamSegment:
 			pathParts[i] = fmt.Sprintf("{%s:.+}", segment.Value)
 		case wrouter.PathParamSegment:
+			if segment.Constraint != nil {
+				// express constraints natively so that values that do not satisfy them do not match the route. The
+				// regular expression never matches '/', so the value is limited to a single path segment.
+				pathParts[i] = fmt.Sprintf("{%s:(?:%s)}", segment.Value, segment.Constraint.Regexp())
+				continue
+			}
 			pathParts[i] = fmt.Sprintf("{%s}", segment.Value)
 		default:
 			pathParts[i] = segment.Value
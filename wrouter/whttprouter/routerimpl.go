@@ -15,6 +15,7 @@
 package whttprouter
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -28,7 +29,7 @@ func New(params ...Param) wrouter.RouterImpl {
 	for _, p := range params {
 		p.apply(r)
 	}
-	return (*router)(r)
+	return &router{router: r, params: params}
 }
 
 type Param interface {
@@ -59,28 +60,69 @@ func HandleMethodNotAllowed(notAllowed bool) Param {
 	})
 }
 
+// HandleOPTIONS configures whether the underlying httprouter.Router answers OPTIONS requests. Has no effect for routers
+// used to create a wrouter.RootRouter, which answers OPTIONS requests itself.
 func HandleOPTIONS(handle bool) Param {
 	return paramFunc(func(r *httprouter.Router) {
 		r.HandleOPTIONS = handle
 	})
 }
 
-type router httprouter.Router
+type router struct {
+	router *httprouter.Router
+	// params stores the parameters with which the router was configured so that NewEmpty can configure new routers in
+	// the same way.
+	params []Param
+}
+
+// NewEmpty returns a new wrouter.RouterImpl backed by a new httprouter.Router configured using the same parameters as
+// this one.
+func (r *router) NewEmpty() wrouter.RouterImpl {
+	return New(r.params...)
+}
 
 func (r *router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	(*httprouter.Router)(r).ServeHTTP(w, req)
+	r.router.ServeHTTP(w, req)
 }
 
 func (r *router) Register(method string, pathSegments []wrouter.PathSegment, handler http.Handler) {
-	(*httprouter.Router)(r).Handler(method, r.convertPathParams(pathSegments), handler)
+	r.router.Handler(method, r.convertPathParams(pathSegments), handler)
+}
+
+// CheckConflict returns an error if httprouter.Router cannot register routes with both of the provided path templates.
+// httprouter.Router does not allow a path parameter or trailing path parameter segment to share its position with any
+// other segment and does not enforce constraints, so path templates conflict unless the first segment in which they
+// differ is a literal segment in both of them.
+func (r *router) CheckConflict(existing, segments []wrouter.PathSegment) error {
+	for i := 0; i < len(existing) && i < len(segments); i++ {
+		if existing[i].Type == segments[i].Type && existing[i].Value == segments[i].Value {
+			continue
+		}
+		if existing[i].Type == wrouter.LiteralSegment && segments[i].Type == wrouter.LiteralSegment {
+			return nil
+		}
+		return errors.New("httprouter does not support a path parameter segment and a different segment at the same position")
+	}
+	if len(existing) == len(segments) {
+		return errors.New("httprouter does not support path templates that only differ in their path parameter constraints")
+	}
+	return nil
 }
 
 func (r *router) RegisterNotFoundHandler(handler http.Handler) {
-	(*httprouter.Router)(r).NotFound = handler
+	r.router.NotFound = handler
+}
+
+// RegisterMethodNotAllowedHandler registers the provided handler as the MethodNotAllowed handler of the underlying
+// httprouter.Router and disables its automatic handling of OPTIONS requests so that OPTIONS requests are handled by the
+// provided handler. Requests are only routed to the handler if HandleMethodNotAllowed is true (which is the default).
+func (r *router) RegisterMethodNotAllowedHandler(handler http.Handler) {
+	r.router.MethodNotAllowed = handler
+	r.router.HandleOPTIONS = false
 }
 
 func (r *router) PathParams(req *http.Request, pathVarNames []string) map[string]string {
-	_, vars, _ := (*httprouter.Router)(r).Lookup(req.Method, req.URL.Path)
+	_, vars, _ := r.router.Lookup(req.Method, req.URL.Path)
 	if len(vars) == 0 {
 		return nil
 	}
@@ -0,0 +1,287 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wradix
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+)
+
+// New returns a wrouter.RouterImpl that routes requests using a tree of path segments. Unlike the other RouterImpl
+// implementations, it has no dependencies and supports every path template that wrouter supports:
+//
+//   - Literal, path parameter and trailing path parameter segments may appear at the same level. Literal segments take
+//     precedence over path parameter segments, which take precedence over trailing path parameter segments. If a more
+//     specific segment matches but the rest of the path does not, the less specific segments are tried.
+//   - Path parameter constraints are enforced natively, so path parameters with different constraints can appear at
+//     the same level. Constrained path parameter segments take precedence over unconstrained ones, while routes with
+//     differently constrained segments that may match the same value conflict.
+func New() wrouter.RouterImpl {
+	return &router{
+		root: &node{},
+	}
+}
+
+type router struct {
+	root             *node
+	maxParams        int
+	notFound         http.Handler
+	methodNotAllowed http.Handler
+}
+
+// NewEmpty returns a new wrouter.RouterImpl with no registered routes.
+func (r *router) NewEmpty() wrouter.RouterImpl {
+	return New()
+}
+
+// node is a node in the routing tree. Each node represents the path up to and including a segment.
+type node struct {
+	// literals stores the children of this node for literal segments keyed by the segment value.
+	literals map[string]*node
+	// params stores the children of this node for path parameter segments in the order in which they are tried:
+	// constrained parameters first, then the unconstrained parameter (if any).
+	params []*paramNode
+	// trailing stores the child of this node for a trailing path parameter segment.
+	trailing *node
+	// handlers stores the handlers for the routes whose path ends at this node keyed by method.
+	handlers map[string]http.Handler
+}
+
+type paramNode struct {
+	constraint wrouter.PathParamConstraint
+	*node
+}
+
+func (r *router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	m := matcher{
+		method:    req.Method,
+		maxParams: r.maxParams,
+	}
+	handler := m.match(r.root, strings.TrimPrefix(req.URL.Path, "/"))
+	switch {
+	case handler != nil:
+		handler.ServeHTTP(w, req)
+	case m.pathMatched && r.methodNotAllowed != nil:
+		r.methodNotAllowed.ServeHTTP(w, req)
+	case m.pathMatched:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	case r.notFound != nil:
+		r.notFound.ServeHTTP(w, req)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (r *router) Register(method string, pathSegments []wrouter.PathSegment, handler http.Handler) {
+	n := r.root
+	numParams := 0
+	for _, segment := range pathSegments {
+		switch segment.Type {
+		case wrouter.TrailingPathParamSegment:
+			if n.trailing == nil {
+				n.trailing = &node{}
+			}
+			n = n.trailing
+			numParams++
+		case wrouter.PathParamSegment:
+			n = n.paramChild(segment.Constraint)
+			numParams++
+		default:
+			if n.literals == nil {
+				n.literals = make(map[string]*node)
+			}
+			child, ok := n.literals[segment.Value]
+			if !ok {
+				child = &node{}
+				n.literals[segment.Value] = child
+			}
+			n = child
+		}
+	}
+	if n.handlers == nil {
+		n.handlers = make(map[string]http.Handler)
+	}
+	n.handlers[method] = handler
+	if numParams > r.maxParams {
+		r.maxParams = numParams
+	}
+}
+
+// ResolvesOverlap returns true for all overlaps except wrouter.ConstrainedPathParamsOverlap: requests for paths that
+// match the path templates of multiple routes are routed according to the precedence of the first segment in which the
+// templates differ (see New). Path parameters with different constraints are tried in the order in which they were
+// registered, so routes whose constraints may match the same value conflict.
+func (r *router) ResolvesOverlap(overlap wrouter.RouteOverlap) bool {
+	switch overlap {
+	case wrouter.LiteralAndPathParamOverlap, wrouter.ConstrainedAndUnconstrainedPathParamOverlap, wrouter.TrailingPathParamOverlap:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *router) RegisterNotFoundHandler(handler http.Handler) {
+	r.notFound = handler
+}
+
+func (r *router) RegisterMethodNotAllowedHandler(handler http.Handler) {
+	r.methodNotAllowed = handler
+}
+
+// PathParams matches the path of the provided request again to determine the values of its path parameters rather than
+// storing them in the context of the request when it is routed, which would copy the request. The values are returned
+// in a new map since wrouter.RouterImpl requires a map: this is the only allocation other than matching the path that
+// routing a request with path parameters performs, and it cannot be avoided without changing wrouter.RouterImpl.
+func (r *router) PathParams(req *http.Request, pathVarNames []string) map[string]string {
+	m := matcher{
+		method:       req.Method,
+		maxParams:    r.maxParams,
+		recordValues: true,
+	}
+	_ = m.match(r.root, strings.TrimPrefix(req.URL.Path, "/"))
+	values := m.values
+	if len(values) == 0 {
+		return nil
+	}
+	params := make(map[string]string, len(values))
+	for i, name := range pathVarNames {
+		if i < len(values) {
+			params[name] = values[i]
+		}
+	}
+	return params
+}
+
+// paramChild returns the child of this node for a path parameter with the provided constraint, creating it if it does
+// not exist.
+func (n *node) paramChild(constraint wrouter.PathParamConstraint) *node {
+	for _, p := range n.params {
+		if sameConstraint(p.constraint, constraint) {
+			return p.node
+		}
+	}
+	child := &paramNode{
+		constraint: constraint,
+		node:       &node{},
+	}
+	if constraint == nil {
+		n.params = append(n.params, child)
+		return child.node
+	}
+	// constrained parameters are tried before the unconstrained parameter
+	idx := len(n.params)
+	if idx > 0 && n.params[idx-1].constraint == nil {
+		idx--
+	}
+	n.params = append(n.params, nil)
+	copy(n.params[idx+1:], n.params[idx:])
+	n.params[idx] = child
+	return child.node
+}
+
+func sameConstraint(a, b wrouter.PathParamConstraint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.String() == b.String()
+}
+
+// matcher stores the state of matching a single request. If recordValues is true, values stores the path parameter
+// values matched so far in the order in which they appear in the path and is allocated with a capacity of maxParams on
+// first use, so matching a path performs at most one allocation. Otherwise, matching a path does not allocate.
+type matcher struct {
+	method       string
+	maxParams    int
+	recordValues bool
+	values       []string
+	pathMatched  bool
+}
+
+// match returns the handler for the matcher's method registered for the provided path relative to the provided node,
+// or nil if there is none. The path must not have a leading slash.
+func (m *matcher) match(n *node, path string) http.Handler {
+	segment, rest, hasRest := cutSegment(path)
+
+	if child, ok := n.literals[segment]; ok {
+		if handler := m.matchChild(child, rest, hasRest); handler != nil {
+			return handler
+		}
+	}
+	if segment != "" {
+		for _, p := range n.params {
+			if p.constraint != nil && !p.constraint.Matches(segment) {
+				continue
+			}
+			m.pushValue(segment)
+			if handler := m.matchChild(p.node, rest, hasRest); handler != nil {
+				return handler
+			}
+			m.popValue()
+		}
+	}
+	if n.trailing != nil && path != "" {
+		m.pushValue(path)
+		if handler := m.handler(n.trailing); handler != nil {
+			return handler
+		}
+		m.popValue()
+	}
+	return nil
+}
+
+func (m *matcher) pushValue(value string) {
+	if !m.recordValues {
+		return
+	}
+	if m.values == nil {
+		m.values = make([]string, 0, m.maxParams)
+	}
+	m.values = append(m.values, value)
+}
+
+func (m *matcher) popValue() {
+	if !m.recordValues {
+		return
+	}
+	m.values = m.values[:len(m.values)-1]
+}
+
+func (m *matcher) matchChild(child *node, rest string, hasRest bool) http.Handler {
+	if !hasRest {
+		return m.handler(child)
+	}
+	return m.match(child, rest)
+}
+
+// handler returns the handler for the matcher's method registered on the provided node, recording whether any handler
+// is registered on the node.
+func (m *matcher) handler(n *node) http.Handler {
+	if len(n.handlers) == 0 {
+		return nil
+	}
+	m.pathMatched = true
+	return n.handlers[m.method]
+}
+
+// cutSegment returns the first segment of the provided path and the remainder of the path after the separator that
+// follows it.
+func cutSegment(path string) (segment, rest string, hasRest bool) {
+	if idx := strings.IndexByte(path, '/'); idx != -1 {
+		return path[:idx], path[idx+1:], true
+	}
+	return path, "", false
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2026 Palantir Technologies. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wradix_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/palantir/witchcraft-go-server/v2/wrouter"
+	"github.com/palantir/witchcraft-go-server/v2/wrouter/wradix"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	handlerCalled := false
+	router := wradix.New()
+	router.Register(http.MethodGet, []wrouter.PathSegment{
+		{
+			Type:  wrouter.LiteralSegment,
+			Value: "hello",
+		},
+	}, http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		handlerCalled = true
+	}))
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	_, err := http.Get(server.URL + "/hello/")
+	require.NoError(t, err)
+
+	assert.False(t, handlerCalled)
+}
+
+// Tests the precedence of literal, path parameter and trailing path parameter segments at the same level.
+func TestSegmentPrecedence(t *testing.T) {
+	router := wrouter.New(wradix.New())
+	for _, path := range []string{
+		"/datasets/latest",
+		"/datasets/{id:int}",
+		"/datasets/{rid}",
+		"/datasets/{rid}/files",
+		"/datasets/{path*}",
+		"/datasets/latest/{branch}/info",
+	} {
+		template, err := wrouter.NewPathTemplate(path)
+		require.NoError(t, err)
+		var pathVarNames []string
+		for _, segment := range template.Segments() {
+			if segment.Type != wrouter.LiteralSegment {
+				pathVarNames = append(pathVarNames, segment.Value)
+			}
+		}
+		path := path
+		require.NoError(t, router.Get(path, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			_, _ = io.WriteString(rw, path)
+			for _, name := range pathVarNames {
+				_, _ = io.WriteString(rw, " "+wrouter.PathParams(req)[name])
+			}
+		})))
+	}
+
+	for _, tc := range []struct {
+		path string
+		want string
+	}{
+		{"/datasets/latest", "/datasets/latest"},
+		{"/datasets/42", "/datasets/{id:int} 42"},
+		{"/datasets/ri.1", "/datasets/{rid} ri.1"},
+		{"/datasets/ri.1/files", "/datasets/{rid}/files ri.1"},
+		{"/datasets/42/files", "/datasets/{rid}/files 42"},
+		{"/datasets/latest/master/info", "/datasets/latest/{branch}/info master"},
+		{"/datasets/latest/files", "/datasets/{rid}/files latest"},
+		{"/datasets/latest/master", "/datasets/{path*} latest/master"},
+		{"/datasets/a/b/c", "/datasets/{path*} a/b/c"},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			assert.Equal(t, http.StatusOK, rw.Code)
+			assert.Equal(t, tc.want, rw.Body.String())
+		})
+	}
+
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/datasets/latest", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rw.Code)
+
+	rw = httptest.NewRecorder()
+	router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, rw.Code)
+}